@@ -0,0 +1,42 @@
+/**
+ * Copyright 2025 Wingify Software Pvt. Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Command vwo-storage lists, exports, imports and erases the decisions of a file store.
+//
+//	vwo-storage forget -store decisions.db -user user-123
+//	vwo-storage export -store decisions.db -out decisions.jsonl
+package main
+
+import (
+	"strings"
+
+	"github.com/wingify/vwo-fme-go-sdk/pkg/storage"
+	"github.com/wingify/vwo-fme-go-sdk/pkg/storage/filestore"
+	"github.com/wingify/vwo-fme-go-sdk/pkg/storage/storagecmd"
+)
+
+func main() {
+	storagecmd.Main(openFileStore)
+}
+
+// openFileStore opens the file store at store, which may carry a "file:" prefix
+func openFileStore(store string) (storage.Connector, func() error, error) {
+	s, err := filestore.Open(strings.TrimPrefix(store, "file:"), filestore.Options{})
+	if err != nil {
+		return nil, nil, err
+	}
+	return s, s.Close, nil
+}
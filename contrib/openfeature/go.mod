@@ -0,0 +1,21 @@
+module github.com/wingify/vwo-fme-go-sdk/contrib/openfeature
+
+// go 1.26 is the minimum required by github.com/open-feature/go-sdk; the root module stays at go 1.16
+go 1.26.0
+
+require (
+	github.com/open-feature/go-sdk v1.19.0
+	github.com/stretchr/testify v1.12.1
+	github.com/wingify/vwo-fme-go-sdk v1.61.0
+	github.com/wingify/wingify-fme-go-sdk v1.60.0
+)
+
+require (
+	github.com/google/uuid v1.6.0 // indirect
+	github.com/spaolacci/murmur3 v1.1.0 // indirect
+	go.uber.org/mock v0.6.0 // indirect
+	go.yaml.in/yaml/v3 v3.0.5 // indirect
+)
+
+// replace builds against the working tree; it is ignored by modules that depend on this one
+replace github.com/wingify/vwo-fme-go-sdk => ../..
@@ -0,0 +1,334 @@
+/**
+ * Copyright 2025 Wingify Software Pvt. Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package openfeature provides an OpenFeature provider backed by VWOClient.
+//
+// Boolean flags resolve to GetFlag(featureKey).IsEnabled(). Typed flags are addressed
+// as "featureKey.variableKey" (split at the last dot) and resolve to the variable value.
+package openfeature
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+
+	of "github.com/open-feature/go-sdk/openfeature"
+	"github.com/wingify/wingify-fme-go-sdk/pkg/enums"
+	"github.com/wingify/wingify-fme-go-sdk/pkg/models"
+)
+
+// ProviderName is reported in the provider metadata
+const ProviderName = "VWO"
+
+// Variants reported for boolean flags
+const (
+	VariantOn  = "on"
+	VariantOff = "off"
+)
+
+// Client is the subset of VWOClient used by the provider
+type Client interface {
+	GetFlag(featureKey string, context map[string]interface{}) (models.GetFlagResponse, error)
+	TrackEvent(eventName string, context map[string]interface{}, eventProperties ...map[string]interface{}) (map[string]bool, error)
+}
+
+// Options configures the provider
+type Options struct {
+	// OnTrackError is called when TrackEvent fails, as the OpenFeature track API cannot return errors
+	OnTrackError func(eventName string, err error)
+}
+
+// Provider implements of.FeatureProvider and of.Tracker on top of VWOClient
+type Provider struct {
+	client  Client
+	options Options
+}
+
+var (
+	_ of.FeatureProvider = (*Provider)(nil)
+	_ of.Tracker         = (*Provider)(nil)
+)
+
+// NewProvider creates a new Provider instance
+func NewProvider(client Client) *Provider {
+	return NewProviderWithOptions(client, Options{})
+}
+
+// NewProviderWithOptions creates a new Provider instance with options
+func NewProviderWithOptions(client Client, options Options) *Provider {
+	return &Provider{
+		client:  client,
+		options: options,
+	}
+}
+
+// Metadata returns the provider metadata
+func (p *Provider) Metadata() of.Metadata {
+	return of.Metadata{Name: ProviderName}
+}
+
+// Hooks returns the provider hooks
+func (p *Provider) Hooks() []of.Hook {
+	return []of.Hook{}
+}
+
+// BooleanEvaluation resolves a feature to its enabled state, or a boolean variable when addressed as featureKey.variableKey
+func (p *Provider) BooleanEvaluation(ctx context.Context, flag string, defaultValue bool, flatCtx of.FlattenedContext) of.BoolResolutionDetail {
+	if strings.Contains(flag, ".") {
+		value, detail := p.resolveVariable(flag, defaultValue, flatCtx)
+		result, ok := value.(bool)
+		if !ok {
+			return of.BoolResolutionDetail{Value: defaultValue, ProviderResolutionDetail: typeMismatch(flag, "bool", value, detail)}
+		}
+		return of.BoolResolutionDetail{Value: result, ProviderResolutionDetail: detail}
+	}
+
+	getFlag, detail := p.getFlag(flag, flatCtx)
+	if getFlag == nil {
+		return of.BoolResolutionDetail{Value: defaultValue, ProviderResolutionDetail: detail}
+	}
+
+	if getFlag.IsEnabled() {
+		return of.BoolResolutionDetail{Value: true, ProviderResolutionDetail: of.ProviderResolutionDetail{
+			Reason:       of.TargetingMatchReason,
+			Variant:      VariantOn,
+			FlagMetadata: flagMetadata(getFlag),
+		}}
+	}
+	return of.BoolResolutionDetail{Value: false, ProviderResolutionDetail: of.ProviderResolutionDetail{
+		Reason:       of.DefaultReason,
+		Variant:      VariantOff,
+		FlagMetadata: flagMetadata(getFlag),
+	}}
+}
+
+// StringEvaluation resolves a string variable addressed as featureKey.variableKey
+func (p *Provider) StringEvaluation(ctx context.Context, flag string, defaultValue string, flatCtx of.FlattenedContext) of.StringResolutionDetail {
+	value, detail := p.resolveVariable(flag, defaultValue, flatCtx)
+	result, ok := value.(string)
+	if !ok {
+		return of.StringResolutionDetail{Value: defaultValue, ProviderResolutionDetail: typeMismatch(flag, "string", value, detail)}
+	}
+	return of.StringResolutionDetail{Value: result, ProviderResolutionDetail: detail}
+}
+
+// FloatEvaluation resolves a numeric variable addressed as featureKey.variableKey
+func (p *Provider) FloatEvaluation(ctx context.Context, flag string, defaultValue float64, flatCtx of.FlattenedContext) of.FloatResolutionDetail {
+	value, detail := p.resolveVariable(flag, defaultValue, flatCtx)
+	result, ok := toFloat(value)
+	if !ok {
+		return of.FloatResolutionDetail{Value: defaultValue, ProviderResolutionDetail: typeMismatch(flag, "float", value, detail)}
+	}
+	return of.FloatResolutionDetail{Value: result, ProviderResolutionDetail: detail}
+}
+
+// IntEvaluation resolves an integer variable addressed as featureKey.variableKey
+func (p *Provider) IntEvaluation(ctx context.Context, flag string, defaultValue int64, flatCtx of.FlattenedContext) of.IntResolutionDetail {
+	value, detail := p.resolveVariable(flag, defaultValue, flatCtx)
+	result, ok := toInt(value)
+	if !ok {
+		return of.IntResolutionDetail{Value: defaultValue, ProviderResolutionDetail: typeMismatch(flag, "int", value, detail)}
+	}
+	return of.IntResolutionDetail{Value: result, ProviderResolutionDetail: detail}
+}
+
+// ObjectEvaluation resolves a variable of any type addressed as featureKey.variableKey
+func (p *Provider) ObjectEvaluation(ctx context.Context, flag string, defaultValue interface{}, flatCtx of.FlattenedContext) of.InterfaceResolutionDetail {
+	value, detail := p.resolveVariable(flag, defaultValue, flatCtx)
+	return of.InterfaceResolutionDetail{Value: value, ProviderResolutionDetail: detail}
+}
+
+// Track forwards an OpenFeature tracking event to TrackEvent
+func (p *Provider) Track(ctx context.Context, trackingEventName string, evaluationContext of.EvaluationContext, details of.TrackingEventDetails) {
+	flatCtx := of.FlattenedContext(evaluationContext.Attributes())
+	flatCtx[of.TargetingKey] = evaluationContext.TargetingKey()
+
+	userContext, err := toUserContext(flatCtx)
+	if err != nil {
+		p.reportTrackError(trackingEventName, err)
+		return
+	}
+
+	eventProperties := details.Attributes()
+	if details.Value() != 0 {
+		eventProperties["value"] = details.Value()
+	}
+
+	if _, err := p.client.TrackEvent(trackingEventName, userContext, eventProperties); err != nil {
+		p.reportTrackError(trackingEventName, err)
+	}
+}
+
+// reportTrackError passes a tracking failure to the configured handler
+func (p *Provider) reportTrackError(eventName string, err error) {
+	if p.options.OnTrackError != nil {
+		p.options.OnTrackError(eventName, err)
+	}
+}
+
+// getFlag evaluates a feature, returning a nil flag and an error detail when the evaluation fails
+func (p *Provider) getFlag(featureKey string, flatCtx of.FlattenedContext) (models.GetFlagResponse, of.ProviderResolutionDetail) {
+	userContext, err := toUserContext(flatCtx)
+	if err != nil {
+		return nil, errorDetail(of.NewTargetingKeyMissingResolutionError(err.Error()))
+	}
+
+	getFlag, err := p.client.GetFlag(featureKey, userContext)
+	if err != nil {
+		return nil, errorDetail(of.NewGeneralResolutionError(err.Error()))
+	}
+	if getFlag == nil {
+		return nil, errorDetail(of.NewGeneralResolutionError("no flag returned for " + featureKey))
+	}
+	return getFlag, of.ProviderResolutionDetail{}
+}
+
+// resolveVariable evaluates the variable addressed by flag, returning defaultValue with the reason when it cannot be resolved
+func (p *Provider) resolveVariable(flag string, defaultValue interface{}, flatCtx of.FlattenedContext) (interface{}, of.ProviderResolutionDetail) {
+	featureKey, variableKey, ok := splitFlagKey(flag)
+	if !ok {
+		return defaultValue, errorDetail(of.NewParseErrorResolutionError(fmt.Sprintf("flag key %q must be in the form featureKey.variableKey", flag)))
+	}
+
+	getFlag, detail := p.getFlag(featureKey, flatCtx)
+	if getFlag == nil {
+		return defaultValue, detail
+	}
+
+	metadata := flagMetadata(getFlag)
+	if !getFlag.IsEnabled() {
+		return defaultValue, of.ProviderResolutionDetail{Reason: of.DefaultReason, Variant: VariantOff, FlagMetadata: metadata}
+	}
+
+	for _, variable := range getFlag.GetVariables() {
+		if variable["key"] == variableKey {
+			return variable["value"], of.ProviderResolutionDetail{Reason: of.TargetingMatchReason, Variant: VariantOn, FlagMetadata: metadata}
+		}
+	}
+
+	detail = errorDetail(of.NewFlagNotFoundResolutionError(fmt.Sprintf("variable %q not found in feature %q", variableKey, featureKey)))
+	detail.FlagMetadata = metadata
+	return defaultValue, detail
+}
+
+// splitFlagKey splits featureKey.variableKey at the last dot
+func splitFlagKey(flag string) (string, string, bool) {
+	index := strings.LastIndex(flag, ".")
+	if index <= 0 || index == len(flag)-1 {
+		return "", "", false
+	}
+	return flag[:index], flag[index+1:], true
+}
+
+// errorDetail builds a resolution detail for an error
+func errorDetail(resolutionError of.ResolutionError) of.ProviderResolutionDetail {
+	return of.ProviderResolutionDetail{ResolutionError: resolutionError, Reason: of.ErrorReason}
+}
+
+// typeMismatch builds the detail returned when a resolved value has the wrong type.
+// Details that already carry an error or a default reason are kept as they are.
+func typeMismatch(flag string, expected string, value interface{}, detail of.ProviderResolutionDetail) of.ProviderResolutionDetail {
+	if detail.Reason != of.TargetingMatchReason {
+		return detail
+	}
+	mismatch := errorDetail(of.NewTypeMismatchResolutionError(fmt.Sprintf("flag %q resolved to %T, expected %s", flag, value, expected)))
+	mismatch.FlagMetadata = detail.FlagMetadata
+	return mismatch
+}
+
+// flagMetadata exposes the evaluation identifiers of a flag
+func flagMetadata(getFlag models.GetFlagResponse) of.FlagMetadata {
+	metadata := of.FlagMetadata{}
+	if uuid := getFlag.GetUUID(); uuid != "" {
+		metadata["uuid"] = uuid
+	}
+	if sessionID := getFlag.GetSessionId(); sessionID != 0 {
+		metadata["sessionId"] = sessionID
+	}
+	return metadata
+}
+
+// toFloat converts a numeric variable value to float64
+func toFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	}
+	return 0, false
+}
+
+// toInt converts a whole numeric variable value to int64
+func toInt(value interface{}) (int64, bool) {
+	switch v := value.(type) {
+	case int:
+		return int64(v), true
+	case int64:
+		return v, true
+	case int32:
+		return int64(v), true
+	case float64:
+		// 2^63 is representable as a float64 but not as an int64, so the upper bound is exclusive
+		if v == math.Trunc(v) && v >= math.MinInt64 && v < math.MaxInt64 {
+			return int64(v), true
+		}
+	}
+	return 0, false
+}
+
+// reservedContextKeys are evaluation context attributes passed as top-level VWO context fields
+var reservedContextKeys = map[string]bool{
+	enums.ContextUserAgent.GetValue():         true,
+	enums.ContextIPAddress.GetValue():         true,
+	enums.ContextBucketingSeed.GetValue():     true,
+	enums.ContextSessionID.GetValue():         true,
+	enums.ContextPlatformVariables.GetValue(): true,
+}
+
+// toUserContext maps an OpenFeature context to a VWO user context.
+// The targeting key becomes the user id and the remaining attributes become custom variables.
+func toUserContext(flatCtx of.FlattenedContext) (map[string]interface{}, error) {
+	targetingKey, _ := flatCtx[of.TargetingKey].(string)
+	if targetingKey == "" {
+		return nil, fmt.Errorf("targeting key is required")
+	}
+
+	userContext := map[string]interface{}{
+		enums.ContextID.GetValue(): targetingKey,
+	}
+	customVariables := map[string]interface{}{}
+	for key, value := range flatCtx {
+		switch {
+		case key == of.TargetingKey:
+		case reservedContextKeys[key]:
+			userContext[key] = value
+		default:
+			customVariables[key] = value
+		}
+	}
+	if len(customVariables) > 0 {
+		userContext[enums.ContextCustomVariables.GetValue()] = customVariables
+	}
+	return userContext, nil
+}
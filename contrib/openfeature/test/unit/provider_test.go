@@ -0,0 +1,263 @@
+/**
+ * Copyright 2025 Wingify Software Pvt. Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package unit
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	of "github.com/open-feature/go-sdk/openfeature"
+	"github.com/stretchr/testify/assert"
+	vwo "github.com/wingify/vwo-fme-go-sdk"
+	"github.com/wingify/vwo-fme-go-sdk/contrib/openfeature"
+	"github.com/wingify/wingify-fme-go-sdk/pkg/models"
+)
+
+var _ openfeature.Client = (*vwo.VWOClient)(nil)
+
+// fakeVWOClient serves flags from a fixed map and records tracked events
+type fakeVWOClient struct {
+	mu         sync.Mutex
+	flags      map[string]models.GetFlagResponse
+	getFlagErr error
+	trackErr   error
+	contexts   []map[string]interface{}
+	events     []string
+	properties []map[string]interface{}
+}
+
+func newFakeVWOClient() *fakeVWOClient {
+	variables := []*models.Variable{
+		models.NewVariable("int", 10.0, "integer", 1),
+		models.NewVariable("float", 20.01, "double", 2),
+		models.NewVariable("string", "test", "string", 3),
+		models.NewVariable("boolean", true, "boolean", 4),
+		models.NewVariable("json", map[string]interface{}{"name": "VWO"}, "json", 5),
+		models.NewVariable("huge", 1e19, "integer", 6),
+	}
+	return &fakeVWOClient{
+		flags: map[string]models.GetFlagResponse{
+			"feature1": models.NewGetFlag(true, variables, "uuid-1", 42),
+			"feature2": models.NewGetFlag(false, variables, "uuid-1", 42),
+		},
+	}
+}
+
+func (c *fakeVWOClient) GetFlag(featureKey string, context map[string]interface{}) (models.GetFlagResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.contexts = append(c.contexts, context)
+	if c.getFlagErr != nil {
+		return nil, c.getFlagErr
+	}
+	if flag, ok := c.flags[featureKey]; ok {
+		return flag, nil
+	}
+	return models.NewGetFlag(false, nil, "", 0), nil
+}
+
+func (c *fakeVWOClient) TrackEvent(eventName string, context map[string]interface{}, eventProperties ...map[string]interface{}) (map[string]bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.contexts = append(c.contexts, context)
+	c.events = append(c.events, eventName)
+	if len(eventProperties) > 0 {
+		c.properties = append(c.properties, eventProperties[0])
+	}
+	if c.trackErr != nil {
+		return nil, c.trackErr
+	}
+	return map[string]bool{eventName: true}, nil
+}
+
+func flatContext(targetingKey string, attributes map[string]interface{}) of.FlattenedContext {
+	flat := of.FlattenedContext{}
+	for key, value := range attributes {
+		flat[key] = value
+	}
+	if targetingKey != "" {
+		flat[of.TargetingKey] = targetingKey
+	}
+	return flat
+}
+
+func TestOpenFeatureProvider(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Metadata", func(t *testing.T) {
+		provider := openfeature.NewProvider(newFakeVWOClient())
+		assert.Equal(t, "VWO", provider.Metadata().Name)
+		assert.Empty(t, provider.Hooks())
+	})
+
+	t.Run("MapsEvaluationContext", func(t *testing.T) {
+		client := newFakeVWOClient()
+		provider := openfeature.NewProvider(client)
+
+		provider.BooleanEvaluation(ctx, "feature1", false, flatContext("user-1", map[string]interface{}{
+			"userAgent": "test-agent",
+			"ipAddress": "1.2.3.4",
+			"tier":      "gold",
+		}))
+
+		assert.Equal(t, map[string]interface{}{
+			"id":              "user-1",
+			"userAgent":       "test-agent",
+			"ipAddress":       "1.2.3.4",
+			"customVariables": map[string]interface{}{"tier": "gold"},
+		}, client.contexts[0])
+	})
+
+	t.Run("BooleanFromIsEnabled", func(t *testing.T) {
+		provider := openfeature.NewProvider(newFakeVWOClient())
+
+		enabled := provider.BooleanEvaluation(ctx, "feature1", false, flatContext("user-1", nil))
+		assert.True(t, enabled.Value)
+		assert.Equal(t, of.TargetingMatchReason, enabled.Reason)
+		assert.Equal(t, openfeature.VariantOn, enabled.Variant)
+		assert.Equal(t, "uuid-1", enabled.FlagMetadata["uuid"])
+		assert.Equal(t, int64(42), enabled.FlagMetadata["sessionId"])
+
+		disabled := provider.BooleanEvaluation(ctx, "feature2", true, flatContext("user-1", nil))
+		assert.False(t, disabled.Value)
+		assert.Equal(t, of.DefaultReason, disabled.Reason)
+		assert.Equal(t, openfeature.VariantOff, disabled.Variant)
+	})
+
+	t.Run("TypedVariables", func(t *testing.T) {
+		provider := openfeature.NewProvider(newFakeVWOClient())
+		flat := flatContext("user-1", nil)
+
+		assert.Equal(t, int64(10), provider.IntEvaluation(ctx, "feature1.int", 0, flat).Value)
+		assert.Equal(t, 20.01, provider.FloatEvaluation(ctx, "feature1.float", 0, flat).Value)
+		assert.Equal(t, "test", provider.StringEvaluation(ctx, "feature1.string", "", flat).Value)
+		assert.True(t, provider.BooleanEvaluation(ctx, "feature1.boolean", false, flat).Value)
+		assert.Equal(t, map[string]interface{}{"name": "VWO"}, provider.ObjectEvaluation(ctx, "feature1.json", nil, flat).Value)
+
+		detail := provider.StringEvaluation(ctx, "feature1.string", "", flat)
+		assert.Equal(t, of.TargetingMatchReason, detail.Reason)
+		assert.NoError(t, detail.Error())
+	})
+
+	t.Run("DisabledFeatureReturnsDefault", func(t *testing.T) {
+		provider := openfeature.NewProvider(newFakeVWOClient())
+
+		detail := provider.StringEvaluation(ctx, "feature2.string", "fallback", flatContext("user-1", nil))
+		assert.Equal(t, "fallback", detail.Value)
+		assert.Equal(t, of.DefaultReason, detail.Reason)
+		assert.NoError(t, detail.Error())
+	})
+
+	t.Run("ErrorCodes", func(t *testing.T) {
+		client := newFakeVWOClient()
+		provider := openfeature.NewProvider(client)
+		flat := flatContext("user-1", nil)
+
+		missing := provider.StringEvaluation(ctx, "feature1.missing", "fallback", flat)
+		assert.Equal(t, "fallback", missing.Value)
+		assert.Equal(t, of.ErrorReason, missing.Reason)
+		assert.Equal(t, of.FlagNotFoundCode, missing.ResolutionDetail().ErrorCode)
+
+		mismatch := provider.IntEvaluation(ctx, "feature1.string", 7, flat)
+		assert.Equal(t, int64(7), mismatch.Value)
+		assert.Equal(t, of.TypeMismatchCode, mismatch.ResolutionDetail().ErrorCode)
+
+		fraction := provider.IntEvaluation(ctx, "feature1.float", 7, flat)
+		assert.Equal(t, of.TypeMismatchCode, fraction.ResolutionDetail().ErrorCode)
+
+		overflow := provider.IntEvaluation(ctx, "feature1.huge", 7, flat)
+		assert.Equal(t, int64(7), overflow.Value)
+		assert.Equal(t, of.TypeMismatchCode, overflow.ResolutionDetail().ErrorCode)
+
+		unaddressed := provider.StringEvaluation(ctx, "feature1", "fallback", flat)
+		assert.Equal(t, of.ParseErrorCode, unaddressed.ResolutionDetail().ErrorCode)
+
+		noTargetingKey := provider.BooleanEvaluation(ctx, "feature1", false, flatContext("", nil))
+		assert.Equal(t, of.TargetingKeyMissingCode, noTargetingKey.ResolutionDetail().ErrorCode)
+
+		client.getFlagErr = errors.New("settings not loaded")
+		general := provider.BooleanEvaluation(ctx, "feature1", true, flat)
+		assert.True(t, general.Value)
+		assert.Equal(t, of.GeneralCode, general.ResolutionDetail().ErrorCode)
+	})
+
+	t.Run("TrackForwardsToTrackEvent", func(t *testing.T) {
+		client := newFakeVWOClient()
+		provider := openfeature.NewProvider(client)
+
+		evalCtx := of.NewEvaluationContext("user-1", map[string]interface{}{"tier": "gold"})
+		provider.Track(ctx, "purchase", evalCtx, of.NewTrackingEventDetails(99.5).Add("currency", "USD"))
+
+		assert.Equal(t, []string{"purchase"}, client.events)
+		assert.Equal(t, map[string]interface{}{"currency": "USD", "value": 99.5}, client.properties[0])
+		assert.Equal(t, "user-1", client.contexts[0]["id"])
+		assert.Equal(t, map[string]interface{}{"tier": "gold"}, client.contexts[0]["customVariables"])
+	})
+
+	t.Run("TrackReportsErrors", func(t *testing.T) {
+		client := newFakeVWOClient()
+		client.trackErr = errors.New("event not found")
+
+		var reported []string
+		provider := openfeature.NewProviderWithOptions(client, openfeature.Options{
+			OnTrackError: func(eventName string, err error) {
+				reported = append(reported, eventName+": "+err.Error())
+			},
+		})
+
+		provider.Track(ctx, "purchase", of.NewEvaluationContext("user-1", nil), of.TrackingEventDetails{})
+		provider.Track(ctx, "purchase", of.NewEvaluationContext("", nil), of.TrackingEventDetails{})
+
+		assert.Equal(t, []string{"purchase: event not found", "purchase: targeting key is required"}, reported)
+		assert.Len(t, client.events, 1)
+	})
+}
+
+func TestOpenFeatureClient(t *testing.T) {
+	client := newFakeVWOClient()
+	assert.NoError(t, of.SetNamedProviderAndWait("vwo-test", openfeature.NewProvider(client)))
+	ofClient := of.NewClient("vwo-test")
+	ctx := context.Background()
+	evalCtx := of.NewEvaluationContext("user-1", map[string]interface{}{"tier": "gold"})
+
+	t.Run("Boolean", func(t *testing.T) {
+		details, err := ofClient.BooleanValueDetails(ctx, "feature1", false, evalCtx)
+		assert.NoError(t, err)
+		assert.True(t, details.Value)
+		assert.Equal(t, openfeature.VariantOn, details.Variant)
+	})
+
+	t.Run("Variable", func(t *testing.T) {
+		value, err := ofClient.IntValue(ctx, "feature1.int", 0, evalCtx)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(10), value)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		details, err := ofClient.StringValueDetails(ctx, "feature1.missing", "fallback", evalCtx)
+		assert.Error(t, err)
+		assert.Equal(t, "fallback", details.Value)
+		assert.Equal(t, of.FlagNotFoundCode, details.ErrorCode)
+	})
+
+	t.Run("Track", func(t *testing.T) {
+		ofClient.Track(ctx, "signup", evalCtx, of.NewTrackingEventDetails(0))
+		assert.Contains(t, client.events, "signup")
+	})
+}
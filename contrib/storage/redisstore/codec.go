@@ -0,0 +1,69 @@
+/**
+ * Copyright 2025 Wingify Software Pvt. Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package redisstore
+
+import (
+	"encoding/json"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// Codec encodes stored decisions
+type Codec interface {
+	Marshal(data map[string]interface{}) ([]byte, error)
+	Unmarshal(payload []byte) (map[string]interface{}, error)
+}
+
+// Codecs shipped with the connector
+var (
+	JSON    Codec = jsonCodec{}
+	MsgPack Codec = msgpackCodec{}
+)
+
+// jsonCodec encodes decisions as JSON
+type jsonCodec struct{}
+
+// Marshal encodes a decision as JSON
+func (jsonCodec) Marshal(data map[string]interface{}) ([]byte, error) {
+	return json.Marshal(data)
+}
+
+// Unmarshal decodes a JSON decision
+func (jsonCodec) Unmarshal(payload []byte) (map[string]interface{}, error) {
+	var data map[string]interface{}
+	if err := json.Unmarshal(payload, &data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// msgpackCodec encodes decisions as msgpack
+type msgpackCodec struct{}
+
+// Marshal encodes a decision as msgpack
+func (msgpackCodec) Marshal(data map[string]interface{}) ([]byte, error) {
+	return msgpack.Marshal(data)
+}
+
+// Unmarshal decodes a msgpack decision
+func (msgpackCodec) Unmarshal(payload []byte) (map[string]interface{}, error) {
+	var data map[string]interface{}
+	if err := msgpack.Unmarshal(payload, &data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
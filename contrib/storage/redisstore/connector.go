@@ -0,0 +1,258 @@
+/**
+ * Copyright 2025 Wingify Software Pvt. Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package redisstore provides a Redis storage connector for the VWO SDK.
+//
+// Decisions are stored under "<prefix><featureKey>:<userId>", encoded with JSON or msgpack.
+package redisstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// DefaultPrefix is the key prefix used when Options.Prefix is empty
+const DefaultPrefix = "vwo:"
+
+// scanCount is the COUNT hint of the SCAN calls made by Scan
+const scanCount = 500
+
+// Options configures the connector
+type Options struct {
+	// Prefix is prepended to every key
+	Prefix string
+
+	// TTL expires stored decisions; zero keeps them forever
+	TTL time.Duration
+
+	// Codec encodes stored decisions; defaults to JSON
+	Codec Codec
+
+	// Timeout bounds every Redis call; zero disables the timeout
+	Timeout time.Duration
+}
+
+// Connector implements storage.Connector on top of a Redis client
+type Connector struct {
+	client goredis.UniversalClient
+	opts   Options
+}
+
+// New creates a new Connector instance
+func New(client goredis.UniversalClient, opts Options) *Connector {
+	if opts.Prefix == "" {
+		opts.Prefix = DefaultPrefix
+	}
+	if opts.Codec == nil {
+		opts.Codec = JSON
+	}
+	return &Connector{
+		client: client,
+		opts:   opts,
+	}
+}
+
+// Key returns the Redis key of a decision
+func (c *Connector) Key(featureKey string, userID string) string {
+	return c.opts.Prefix + featureKey + ":" + userID
+}
+
+// Set stores a decision
+func (c *Connector) Set(data map[string]interface{}) error {
+	key, payload, err := c.encode(data)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := c.context()
+	defer cancel()
+	return c.client.Set(ctx, key, payload, c.opts.TTL).Err()
+}
+
+// Get retrieves a decision, returning nil when none is stored
+func (c *Connector) Get(featureKey string, userID string) (interface{}, error) {
+	ctx, cancel := c.context()
+	defer cancel()
+
+	payload, err := c.client.Get(ctx, c.Key(featureKey, userID)).Bytes()
+	if errors.Is(err, goredis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return c.opts.Codec.Unmarshal(payload)
+}
+
+// GetMany retrieves the decisions of a user for several features in one pipelined round trip.
+// Features without a stored decision are absent from the result.
+func (c *Connector) GetMany(userID string, featureKeys []string) (map[string]map[string]interface{}, error) {
+	result := make(map[string]map[string]interface{}, len(featureKeys))
+	if len(featureKeys) == 0 {
+		return result, nil
+	}
+
+	ctx, cancel := c.context()
+	defer cancel()
+
+	commands := make([]*goredis.StringCmd, len(featureKeys))
+	_, err := c.client.Pipelined(ctx, func(pipe goredis.Pipeliner) error {
+		for i, featureKey := range featureKeys {
+			commands[i] = pipe.Get(ctx, c.Key(featureKey, userID))
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, goredis.Nil) {
+		return nil, err
+	}
+
+	for i, command := range commands {
+		payload, err := command.Bytes()
+		if errors.Is(err, goredis.Nil) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		data, err := c.opts.Codec.Unmarshal(payload)
+		if err != nil {
+			return nil, fmt.Errorf("decoding %s: %w", c.Key(featureKeys[i], userID), err)
+		}
+		result[featureKeys[i]] = data
+	}
+	return result, nil
+}
+
+// SetMany stores several decisions in one pipelined round trip
+func (c *Connector) SetMany(records []map[string]interface{}) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	keys := make([]string, len(records))
+	payloads := make([][]byte, len(records))
+	for i, data := range records {
+		key, payload, err := c.encode(data)
+		if err != nil {
+			return err
+		}
+		keys[i] = key
+		payloads[i] = payload
+	}
+
+	ctx, cancel := c.context()
+	defer cancel()
+
+	_, err := c.client.Pipelined(ctx, func(pipe goredis.Pipeliner) error {
+		for i := range keys {
+			pipe.Set(ctx, keys[i], payloads[i], c.opts.TTL)
+		}
+		return nil
+	})
+	return err
+}
+
+// Delete removes a decision
+func (c *Connector) Delete(featureKey string, userID string) error {
+	ctx, cancel := c.context()
+	defer cancel()
+	return c.client.Del(ctx, c.Key(featureKey, userID)).Err()
+}
+
+// Scan calls fn for every decision of userID, or of every user when userID is empty.
+// Keys are enumerated with SCAN on every master node, so decisions written during the scan may be missed.
+func (c *Connector) Scan(ctx context.Context, userID string, fn func(data map[string]interface{}) error) error {
+	pattern := escapePattern(c.opts.Prefix) + "*"
+	if userID != "" {
+		pattern += ":" + escapePattern(userID)
+	}
+
+	scanNode := func(ctx context.Context, client goredis.UniversalClient) error {
+		iter := client.Scan(ctx, 0, pattern, scanCount).Iterator()
+		for iter.Next(ctx) {
+			payload, err := client.Get(ctx, iter.Val()).Bytes()
+			if errors.Is(err, goredis.Nil) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			data, err := c.opts.Codec.Unmarshal(payload)
+			if err != nil {
+				return fmt.Errorf("decoding %s: %w", iter.Val(), err)
+			}
+			// the pattern also matches users whose id ends with ":"+userID
+			if userID != "" && data["userId"] != userID {
+				continue
+			}
+			if err := fn(data); err != nil {
+				return err
+			}
+		}
+		return iter.Err()
+	}
+
+	if cluster, ok := c.client.(*goredis.ClusterClient); ok {
+		return cluster.ForEachMaster(ctx, func(ctx context.Context, node *goredis.Client) error {
+			return scanNode(ctx, node)
+		})
+	}
+	return scanNode(ctx, c.client)
+}
+
+// encode validates a decision and returns its key and payload
+func (c *Connector) encode(data map[string]interface{}) (string, []byte, error) {
+	featureKey, ok := data["featureKey"].(string)
+	if !ok || featureKey == "" {
+		return "", nil, fmt.Errorf("featureKey not found or not a string")
+	}
+	userID, ok := data["userId"].(string)
+	if !ok || userID == "" {
+		return "", nil, fmt.Errorf("userId not found or not a string")
+	}
+
+	payload, err := c.opts.Codec.Marshal(data)
+	if err != nil {
+		return "", nil, err
+	}
+	return c.Key(featureKey, userID), payload, nil
+}
+
+// context returns the context of a Redis call
+func (c *Connector) context() (context.Context, context.CancelFunc) {
+	if c.opts.Timeout > 0 {
+		return context.WithTimeout(context.Background(), c.opts.Timeout)
+	}
+	return context.Background(), func() {}
+}
+
+// escapePattern escapes the glob characters of s for a SCAN MATCH pattern
+func escapePattern(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch r {
+		case '*', '?', '[', ']', '\\':
+			b.WriteRune('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,29 @@
+module github.com/wingify/vwo-fme-go-sdk/contrib/storage/redisstore
+
+// go 1.24 is the minimum required by github.com/redis/go-redis/v9; the root module stays at go 1.16
+go 1.24
+
+require (
+	github.com/alicebob/miniredis/v2 v2.39.0
+	github.com/redis/go-redis/v9 v9.22.0
+	github.com/stretchr/testify v1.7.5
+	github.com/vmihailenco/msgpack/v5 v5.4.1
+	github.com/wingify/vwo-fme-go-sdk v1.61.0
+	github.com/wingify/wingify-fme-go-sdk v1.60.0
+)
+
+require (
+	github.com/cespare/xxhash/v2 v2.3.0 // indirect
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/google/uuid v1.6.0 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	github.com/spaolacci/murmur3 v1.1.0 // indirect
+	github.com/vmihailenco/tagparser/v2 v2.0.0 // indirect
+	github.com/yuin/gopher-lua v1.1.1 // indirect
+	go.uber.org/atomic v1.11.0 // indirect
+	golang.org/x/sys v0.30.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+// replace builds against the working tree; it is ignored by modules that depend on this one
+replace github.com/wingify/vwo-fme-go-sdk => ../../..
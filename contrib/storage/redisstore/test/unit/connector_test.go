@@ -0,0 +1,251 @@
+/**
+ * Copyright 2025 Wingify Software Pvt. Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package unit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	vwo "github.com/wingify/vwo-fme-go-sdk"
+	"github.com/wingify/vwo-fme-go-sdk/contrib/storage/redisstore"
+	vwostorage "github.com/wingify/vwo-fme-go-sdk/pkg/storage"
+	"github.com/wingify/wingify-fme-go-sdk/pkg/enums"
+	storageModels "github.com/wingify/wingify-fme-go-sdk/pkg/models/storage"
+	"github.com/wingify/wingify-fme-go-sdk/pkg/packages/storage"
+)
+
+var (
+	_ storage.Connector         = (*redisstore.Connector)(nil)
+	_ vwostorage.BatchConnector = (*redisstore.Connector)(nil)
+	_ vwostorage.Scanner        = (*redisstore.Connector)(nil)
+	_ vwostorage.Deleter        = (*redisstore.Connector)(nil)
+)
+
+func newConnector(t *testing.T, opts redisstore.Options) (*redisstore.Connector, *miniredis.Miniredis) {
+	server := miniredis.RunT(t)
+	client := goredis.NewClient(&goredis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return redisstore.New(client, opts), server
+}
+
+func decision(featureKey string, userID string) map[string]interface{} {
+	return map[string]interface{}{
+		"featureKey":         featureKey,
+		"userId":             userID,
+		"featureId":          1,
+		"rolloutKey":         featureKey + "_rollout",
+		"rolloutId":          2,
+		"rolloutVariationId": 1,
+	}
+}
+
+// toStorageData parses a stored decision the same way the SDK does
+func toStorageData(t *testing.T, data interface{}) storageModels.StorageData {
+	payload, err := json.Marshal(data)
+	assert.NoError(t, err)
+	var storageData storageModels.StorageData
+	assert.NoError(t, json.Unmarshal(payload, &storageData))
+	return storageData
+}
+
+func TestRedisConnector(t *testing.T) {
+	for name, codec := range map[string]redisstore.Codec{"JSON": redisstore.JSON, "MsgPack": redisstore.MsgPack} {
+		t.Run(name+"RoundTrip", func(t *testing.T) {
+			connector, _ := newConnector(t, redisstore.Options{Codec: codec})
+
+			assert.NoError(t, connector.Set(decision("feature1", "user-1")))
+
+			data, err := connector.Get("feature1", "user-1")
+			assert.NoError(t, err)
+			storageData := toStorageData(t, data)
+			assert.Equal(t, "feature1_rollout", storageData.RolloutKey)
+			assert.Equal(t, 2, storageData.RolloutID)
+			assert.Equal(t, 1, storageData.RolloutVariationID)
+		})
+	}
+
+	t.Run("MissingDecision", func(t *testing.T) {
+		connector, _ := newConnector(t, redisstore.Options{})
+
+		data, err := connector.Get("feature1", "user-1")
+		assert.NoError(t, err)
+		assert.Nil(t, data)
+	})
+
+	t.Run("KeyPrefix", func(t *testing.T) {
+		connector, server := newConnector(t, redisstore.Options{Prefix: "app:"})
+
+		assert.NoError(t, connector.Set(decision("feature1", "user-1")))
+		assert.True(t, server.Exists("app:feature1:user-1"))
+		assert.Equal(t, "app:feature1:user-1", connector.Key("feature1", "user-1"))
+
+		defaultConnector, _ := newConnector(t, redisstore.Options{})
+		assert.Equal(t, "vwo:feature1:user-1", defaultConnector.Key("feature1", "user-1"))
+	})
+
+	t.Run("TTL", func(t *testing.T) {
+		connector, server := newConnector(t, redisstore.Options{TTL: time.Minute})
+
+		assert.NoError(t, connector.Set(decision("feature1", "user-1")))
+		assert.Equal(t, time.Minute, server.TTL("vwo:feature1:user-1"))
+
+		server.FastForward(2 * time.Minute)
+		data, err := connector.Get("feature1", "user-1")
+		assert.NoError(t, err)
+		assert.Nil(t, data)
+	})
+
+	t.Run("InvalidDecision", func(t *testing.T) {
+		connector, _ := newConnector(t, redisstore.Options{})
+
+		assert.Error(t, connector.Set(map[string]interface{}{"userId": "user-1"}))
+		assert.Error(t, connector.Set(map[string]interface{}{"featureKey": "feature1"}))
+	})
+
+	t.Run("ServerDown", func(t *testing.T) {
+		connector, server := newConnector(t, redisstore.Options{Timeout: time.Second})
+		server.Close()
+
+		_, err := connector.Get("feature1", "user-1")
+		assert.Error(t, err)
+		assert.Error(t, connector.Set(decision("feature1", "user-1")))
+	})
+}
+
+func TestRedisConnectorBulk(t *testing.T) {
+	t.Run("GetMany", func(t *testing.T) {
+		connector, _ := newConnector(t, redisstore.Options{Codec: redisstore.MsgPack})
+
+		assert.NoError(t, connector.SetMany([]map[string]interface{}{
+			decision("feature1", "user-1"),
+			decision("feature2", "user-1"),
+			decision("feature1", "user-2"),
+		}))
+
+		result, err := connector.GetMany("user-1", []string{"feature1", "feature2", "feature3"})
+		assert.NoError(t, err)
+		assert.Len(t, result, 2)
+		assert.Equal(t, "feature1_rollout", toStorageData(t, result["feature1"]).RolloutKey)
+		assert.Equal(t, "feature2_rollout", toStorageData(t, result["feature2"]).RolloutKey)
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		connector, _ := newConnector(t, redisstore.Options{})
+
+		result, err := connector.GetMany("user-1", nil)
+		assert.NoError(t, err)
+		assert.Empty(t, result)
+		assert.NoError(t, connector.SetMany(nil))
+	})
+
+	t.Run("SetManyValidatesBeforeWriting", func(t *testing.T) {
+		connector, server := newConnector(t, redisstore.Options{})
+
+		err := connector.SetMany([]map[string]interface{}{decision("feature1", "user-1"), {"featureKey": "feature2"}})
+		assert.Error(t, err)
+		assert.False(t, server.Exists("vwo:feature1:user-1"))
+	})
+
+	t.Run("CorruptPayload", func(t *testing.T) {
+		connector, server := newConnector(t, redisstore.Options{})
+		assert.NoError(t, server.Set("vwo:feature1:user-1", "not-json"))
+
+		_, err := connector.GetMany("user-1", []string{"feature1"})
+		assert.Error(t, err)
+	})
+}
+
+func TestRedisConnectorAdmin(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("ScanAndForgetUser", func(t *testing.T) {
+		connector, server := newConnector(t, redisstore.Options{Prefix: "app[1]:"})
+		assert.NoError(t, connector.SetMany([]map[string]interface{}{
+			decision("feature1", "user-1"),
+			decision("feature2", "user-1"),
+			decision("feature1", "user-2"),
+			decision("feature1", "prefix:user-1"),
+		}))
+		assert.NoError(t, server.Set("other:feature1:user-1", "{}"))
+
+		admin := vwostorage.NewAdmin(connector)
+		decisions, err := admin.ListUser(ctx, "user-1")
+		assert.NoError(t, err)
+		assert.Len(t, decisions, 2)
+
+		deleted, err := admin.ForgetUser(ctx, "user-1")
+		assert.NoError(t, err)
+		assert.Equal(t, 2, deleted)
+		assert.False(t, server.Exists("app[1]:feature1:user-1"))
+		assert.True(t, server.Exists("app[1]:feature1:prefix:user-1"))
+		assert.True(t, server.Exists("other:feature1:user-1"))
+	})
+
+	t.Run("ExportImport", func(t *testing.T) {
+		source, _ := newConnector(t, redisstore.Options{})
+		assert.NoError(t, source.SetMany([]map[string]interface{}{decision("feature1", "user-1"), decision("feature1", "user-2")}))
+
+		var export bytes.Buffer
+		exported, err := vwostorage.NewAdmin(source).Export(ctx, &export, "")
+		assert.NoError(t, err)
+		assert.Equal(t, 2, exported)
+
+		target, _ := newConnector(t, redisstore.Options{Codec: redisstore.MsgPack})
+		imported, err := vwostorage.NewAdmin(target).Import(ctx, &export)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, imported)
+
+		data, err := target.Get("feature1", "user-2")
+		assert.NoError(t, err)
+		assert.Equal(t, 1, toStorageData(t, data).RolloutVariationID)
+	})
+}
+
+func TestRedisConnectorWithSDK(t *testing.T) {
+	settings, err := os.ReadFile("../../../../../test/data/settings/BASIC_ROLLOUT_SETTINGS.json")
+	assert.NoError(t, err)
+
+	connector, server := newConnector(t, redisstore.Options{})
+
+	vwoClient, err := vwo.Init(map[string]interface{}{
+		enums.OptionSDKKey.GetValue():    "abcd",
+		enums.OptionAccountID.GetValue(): 12345,
+		enums.OptionSettings.GetValue():  string(settings),
+		enums.OptionStorage.GetValue():   connector,
+	})
+	assert.NoError(t, err)
+
+	flag, err := vwoClient.GetFlag("feature1", map[string]interface{}{"id": "user-1"})
+	assert.NoError(t, err)
+	assert.True(t, flag.IsEnabled())
+	assert.True(t, server.Exists("vwo:feature1:user-1"))
+
+	data, err := connector.Get("feature1", "user-1")
+	assert.NoError(t, err)
+	assert.NotEmpty(t, toStorageData(t, data).RolloutKey)
+
+	flag, err = vwoClient.GetFlag("feature1", map[string]interface{}{"id": "user-1"})
+	assert.NoError(t, err)
+	assert.True(t, flag.IsEnabled())
+}
@@ -0,0 +1,251 @@
+/**
+ * Copyright 2025 Wingify Software Pvt. Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package sqlstore provides a database/sql storage connector for the VWO SDK.
+//
+// Decisions are stored one row per feature and user in a table created by Migrate.
+// PostgreSQL, MySQL and SQLite are supported; the caller registers the driver.
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// DefaultTable is the table name used when Options.Table is empty
+const DefaultTable = "vwo_decisions"
+
+// tableNamePattern restricts table names to plain identifiers as they are interpolated into statements
+var tableNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]{0,47}$`)
+
+// decisionColumns are the columns written by Set, in bind order
+var decisionColumns = []string{
+	"feature_key", "user_id", "feature_id",
+	"rollout_id", "rollout_key", "rollout_variation_id",
+	"experiment_id", "experiment_key", "experiment_variation_id",
+	"extra", "created_at", "updated_at",
+}
+
+// selectedColumns are the columns read by scanDecision: every decision column but the timestamps
+var selectedColumns = strings.Join(decisionColumns[:len(decisionColumns)-2], ", ")
+
+// Options configures the connector
+type Options struct {
+	// Dialect is the SQL flavour of the database
+	Dialect Dialect
+
+	// Table is the name of the decisions table
+	Table string
+
+	// Timeout bounds every Set and Get call; zero disables the timeout
+	Timeout time.Duration
+}
+
+// Connector implements storage.Connector on top of a *sql.DB
+type Connector struct {
+	db   *sql.DB
+	opts Options
+
+	upsertQuery string
+	selectQuery string
+	deleteQuery string
+}
+
+// New creates a new Connector instance. Call Migrate before first use.
+func New(db *sql.DB, opts Options) (*Connector, error) {
+	if opts.Dialect.name == "" {
+		return nil, fmt.Errorf("sqlstore: dialect is required")
+	}
+	if opts.Table == "" {
+		opts.Table = DefaultTable
+	}
+	if !tableNamePattern.MatchString(opts.Table) {
+		return nil, fmt.Errorf("sqlstore: invalid table name %q", opts.Table)
+	}
+
+	c := &Connector{db: db, opts: opts}
+
+	// created_at is left out of the update so that it keeps the time of the first decision
+	updated := make([]string, 0, len(decisionColumns))
+	for _, column := range decisionColumns[2:] {
+		if column != "created_at" {
+			updated = append(updated, column)
+		}
+	}
+	c.upsertQuery = fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s) %s",
+		opts.Table, strings.Join(decisionColumns, ", "),
+		opts.Dialect.placeholders(1, len(decisionColumns)),
+		opts.Dialect.upsertClause(updated))
+	c.selectQuery = fmt.Sprintf("SELECT %s FROM %s WHERE feature_key = %s AND user_id = %s",
+		selectedColumns, opts.Table,
+		opts.Dialect.placeholder(1), opts.Dialect.placeholder(2))
+	c.deleteQuery = fmt.Sprintf("DELETE FROM %s WHERE feature_key = %s AND user_id = %s",
+		opts.Table, opts.Dialect.placeholder(1), opts.Dialect.placeholder(2))
+
+	return c, nil
+}
+
+// Set upserts a decision
+func (c *Connector) Set(data map[string]interface{}) error {
+	args, err := decisionArgs(data, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := c.context()
+	defer cancel()
+	_, err = c.db.ExecContext(ctx, c.upsertQuery, args...)
+	return err
+}
+
+// Get retrieves a decision, returning nil when none is stored
+func (c *Connector) Get(featureKey string, userID string) (interface{}, error) {
+	ctx, cancel := c.context()
+	defer cancel()
+
+	data, err := scanDecision(c.db.QueryRowContext(ctx, c.selectQuery, featureKey, userID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// GetMany retrieves the decisions of a user for several features in one query.
+// Features without a stored decision are absent from the result.
+func (c *Connector) GetMany(userID string, featureKeys []string) (map[string]map[string]interface{}, error) {
+	result := make(map[string]map[string]interface{}, len(featureKeys))
+	if len(featureKeys) == 0 {
+		return result, nil
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE user_id = %s AND feature_key IN (%s)",
+		selectedColumns, c.opts.Table,
+		c.opts.Dialect.placeholder(1), c.opts.Dialect.placeholders(2, len(featureKeys)))
+	args := make([]interface{}, 0, len(featureKeys)+1)
+	args = append(args, userID)
+	for _, featureKey := range featureKeys {
+		args = append(args, featureKey)
+	}
+
+	ctx, cancel := c.context()
+	defer cancel()
+
+	rows, err := c.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		data, err := scanDecision(rows)
+		if err != nil {
+			return nil, err
+		}
+		result[data["featureKey"].(string)] = data
+	}
+	return result, rows.Err()
+}
+
+// SetMany upserts several decisions in one transaction
+func (c *Connector) SetMany(records []map[string]interface{}) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	now := time.Now().UTC()
+	rowArgs := make([][]interface{}, len(records))
+	for i, data := range records {
+		args, err := decisionArgs(data, now)
+		if err != nil {
+			return err
+		}
+		rowArgs[i] = args
+	}
+
+	ctx, cancel := c.context()
+	defer cancel()
+
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	statement, err := tx.PrepareContext(ctx, c.upsertQuery)
+	if err != nil {
+		return err
+	}
+	defer statement.Close()
+
+	for _, args := range rowArgs {
+		if _, err := statement.ExecContext(ctx, args...); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// Delete removes a decision
+func (c *Connector) Delete(featureKey string, userID string) error {
+	ctx, cancel := c.context()
+	defer cancel()
+	_, err := c.db.ExecContext(ctx, c.deleteQuery, featureKey, userID)
+	return err
+}
+
+// Scan calls fn for every decision of userID, or of every user when userID is empty, ordered by user and feature.
+// The rows stay open while fn runs, so fn must not wait on the same single-connection database.
+func (c *Connector) Scan(ctx context.Context, userID string, fn func(data map[string]interface{}) error) error {
+	query := fmt.Sprintf("SELECT %s FROM %s", selectedColumns, c.opts.Table)
+	var args []interface{}
+	if userID != "" {
+		query += " WHERE user_id = " + c.opts.Dialect.placeholder(1)
+		args = append(args, userID)
+	}
+	query += " ORDER BY user_id, feature_key"
+
+	rows, err := c.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		data, err := scanDecision(rows)
+		if err != nil {
+			return err
+		}
+		if err := fn(data); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// context returns the context of a database call
+func (c *Connector) context() (context.Context, context.CancelFunc) {
+	if c.opts.Timeout > 0 {
+		return context.WithTimeout(context.Background(), c.opts.Timeout)
+	}
+	return context.Background(), func() {}
+}
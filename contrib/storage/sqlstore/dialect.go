@@ -0,0 +1,90 @@
+/**
+ * Copyright 2025 Wingify Software Pvt. Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sqlstore
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Dialect describes the SQL flavour of a database
+type Dialect struct {
+	name          string
+	timestampType string
+	numbered      bool
+	upsertClause  func(columns []string) string
+}
+
+// Supported dialects
+var (
+	Postgres = Dialect{
+		name:          "postgres",
+		timestampType: "TIMESTAMPTZ",
+		numbered:      true,
+		upsertClause:  onConflictClause,
+	}
+	MySQL = Dialect{
+		name:          "mysql",
+		timestampType: "DATETIME(6)",
+		upsertClause:  onDuplicateKeyClause,
+	}
+	SQLite = Dialect{
+		name:          "sqlite",
+		timestampType: "TIMESTAMP",
+		upsertClause:  onConflictClause,
+	}
+)
+
+// Name returns the name of the dialect
+func (d Dialect) Name() string {
+	return d.name
+}
+
+// placeholder returns the bind parameter for the n-th argument, starting at 1
+func (d Dialect) placeholder(n int) string {
+	if d.numbered {
+		return fmt.Sprintf("$%d", n)
+	}
+	return "?"
+}
+
+// placeholders returns count bind parameters starting at the first-th argument
+func (d Dialect) placeholders(first int, count int) string {
+	params := make([]string, count)
+	for i := range params {
+		params[i] = d.placeholder(first + i)
+	}
+	return strings.Join(params, ", ")
+}
+
+// onConflictClause builds the upsert clause of PostgreSQL and SQLite
+func onConflictClause(columns []string) string {
+	assignments := make([]string, len(columns))
+	for i, column := range columns {
+		assignments[i] = column + " = excluded." + column
+	}
+	return "ON CONFLICT (feature_key, user_id) DO UPDATE SET " + strings.Join(assignments, ", ")
+}
+
+// onDuplicateKeyClause builds the upsert clause of MySQL
+func onDuplicateKeyClause(columns []string) string {
+	assignments := make([]string, len(columns))
+	for i, column := range columns {
+		assignments[i] = column + " = VALUES(" + column + ")"
+	}
+	return "ON DUPLICATE KEY UPDATE " + strings.Join(assignments, ", ")
+}
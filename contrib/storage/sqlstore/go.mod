@@ -0,0 +1,30 @@
+module github.com/wingify/vwo-fme-go-sdk/contrib/storage/sqlstore
+
+// go 1.26 is the minimum required by modernc.org/sqlite; the root module stays at go 1.16
+go 1.26.0
+
+require (
+	github.com/stretchr/testify v1.7.5
+	github.com/wingify/vwo-fme-go-sdk v1.61.0
+	github.com/wingify/wingify-fme-go-sdk v1.60.0
+	modernc.org/sqlite v1.60.1
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/dustin/go-humanize v1.0.1 // indirect
+	github.com/google/uuid v1.6.0 // indirect
+	github.com/mattn/go-isatty v0.0.24 // indirect
+	github.com/ncruces/go-strftime v1.0.0 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	github.com/remyoudompheng/bigfft v0.0.0-20230129092748-24d4a6f8daec // indirect
+	github.com/spaolacci/murmur3 v1.1.0 // indirect
+	golang.org/x/sys v0.48.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+	modernc.org/libc v1.77.1 // indirect
+	modernc.org/mathutil v1.7.1 // indirect
+	modernc.org/memory v1.12.1 // indirect
+)
+
+// replace builds against the working tree; it is ignored by modules that depend on this one
+replace github.com/wingify/vwo-fme-go-sdk => ../../..
@@ -0,0 +1,126 @@
+/**
+ * Copyright 2025 Wingify Software Pvt. Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// migration is a versioned schema change
+type migration struct {
+	version    int
+	statements func(table string, d Dialect) []string
+}
+
+// migrations lists the schema changes in the order they are applied; released entries must never change
+var migrations = []migration{
+	{
+		version: 1,
+		statements: func(table string, d Dialect) []string {
+			return []string{fmt.Sprintf(`CREATE TABLE %s (
+	feature_key VARCHAR(255) NOT NULL,
+	user_id VARCHAR(255) NOT NULL,
+	feature_id BIGINT NOT NULL,
+	rollout_id BIGINT NULL,
+	rollout_key VARCHAR(255) NULL,
+	rollout_variation_id BIGINT NULL,
+	experiment_id BIGINT NULL,
+	experiment_key VARCHAR(255) NULL,
+	experiment_variation_id BIGINT NULL,
+	extra TEXT NULL,
+	created_at %s NOT NULL,
+	updated_at %s NOT NULL,
+	PRIMARY KEY (feature_key, user_id)
+)`, table, d.timestampType, d.timestampType)}
+		},
+	},
+	{
+		version: 2,
+		statements: func(table string, d Dialect) []string {
+			return []string{fmt.Sprintf("CREATE INDEX %s_user_id_idx ON %s (user_id)", table, table)}
+		},
+	},
+}
+
+// LatestSchemaVersion is the schema version after all migrations are applied
+func LatestSchemaVersion() int {
+	return migrations[len(migrations)-1].version
+}
+
+// Migrate applies the pending migrations, each in its own transaction
+func (c *Connector) Migrate(ctx context.Context) error {
+	createVersions := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (version BIGINT NOT NULL PRIMARY KEY, applied_at %s NOT NULL)",
+		c.migrationsTable(), c.opts.Dialect.timestampType)
+	if _, err := c.db.ExecContext(ctx, createVersions); err != nil {
+		return fmt.Errorf("creating %s: %w", c.migrationsTable(), err)
+	}
+
+	current, err := c.SchemaVersion(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range migrations {
+		if m.version <= current {
+			continue
+		}
+		if err := c.applyMigration(ctx, m); err != nil {
+			return fmt.Errorf("applying migration %d: %w", m.version, err)
+		}
+	}
+	return nil
+}
+
+// SchemaVersion returns the last applied migration, or 0 before the first one
+func (c *Connector) SchemaVersion(ctx context.Context) (int, error) {
+	var version sql.NullInt64
+	query := fmt.Sprintf("SELECT MAX(version) FROM %s", c.migrationsTable())
+	if err := c.db.QueryRowContext(ctx, query).Scan(&version); err != nil {
+		return 0, err
+	}
+	return int(version.Int64), nil
+}
+
+// applyMigration runs the statements of a migration and records its version
+func (c *Connector) applyMigration(ctx context.Context, m migration) error {
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, statement := range m.statements(c.opts.Table, c.opts.Dialect) {
+		if _, err := tx.ExecContext(ctx, statement); err != nil {
+			return err
+		}
+	}
+
+	record := fmt.Sprintf("INSERT INTO %s (version, applied_at) VALUES (%s)",
+		c.migrationsTable(), c.opts.Dialect.placeholders(1, 2))
+	if _, err := tx.ExecContext(ctx, record, m.version, time.Now().UTC()); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// migrationsTable returns the name of the table holding the applied versions
+func (c *Connector) migrationsTable() string {
+	return c.opts.Table + "_migrations"
+}
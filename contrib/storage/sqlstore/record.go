@@ -0,0 +1,174 @@
+/**
+ * Copyright 2025 Wingify Software Pvt. Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sqlstore
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+)
+
+// knownFields are the decision fields stored in their own columns; any other field is kept in the extra column
+var knownFields = map[string]bool{
+	"featureKey": true, "userId": true, "featureId": true,
+	"rolloutId": true, "rolloutKey": true, "rolloutVariationId": true,
+	"experimentId": true, "experimentKey": true, "experimentVariationId": true,
+}
+
+// rowScanner is implemented by *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// decisionArgs converts a decision to the bind arguments of decisionColumns
+func decisionArgs(data map[string]interface{}, now time.Time) ([]interface{}, error) {
+	featureKey, ok := data["featureKey"].(string)
+	if !ok || featureKey == "" {
+		return nil, fmt.Errorf("featureKey not found or not a string")
+	}
+	userID, ok := data["userId"].(string)
+	if !ok || userID == "" {
+		return nil, fmt.Errorf("userId not found or not a string")
+	}
+
+	extra, err := extraFields(data)
+	if err != nil {
+		return nil, err
+	}
+
+	featureID, _ := toInt64(data["featureId"])
+	return []interface{}{
+		featureKey,
+		userID,
+		featureID,
+		nullInt(data["rolloutId"]),
+		nullString(data["rolloutKey"]),
+		nullInt(data["rolloutVariationId"]),
+		nullInt(data["experimentId"]),
+		nullString(data["experimentKey"]),
+		nullInt(data["experimentVariationId"]),
+		extra,
+		now,
+		now,
+	}, nil
+}
+
+// scanDecision reads a row selected with selectedColumns into the storage map format
+func scanDecision(row rowScanner) (map[string]interface{}, error) {
+	var (
+		featureKey, userID                  string
+		featureID                           int64
+		rolloutID, rolloutVariationID       sql.NullInt64
+		experimentID, experimentVariationID sql.NullInt64
+		rolloutKey, experimentKey, extra    sql.NullString
+	)
+	err := row.Scan(&featureKey, &userID, &featureID,
+		&rolloutID, &rolloutKey, &rolloutVariationID,
+		&experimentID, &experimentKey, &experimentVariationID, &extra)
+	if err != nil {
+		return nil, err
+	}
+
+	data := map[string]interface{}{
+		"featureKey": featureKey,
+		"userId":     userID,
+		"featureId":  int(featureID),
+	}
+	setInt(data, "rolloutId", rolloutID)
+	setString(data, "rolloutKey", rolloutKey)
+	setInt(data, "rolloutVariationId", rolloutVariationID)
+	setInt(data, "experimentId", experimentID)
+	setString(data, "experimentKey", experimentKey)
+	setInt(data, "experimentVariationId", experimentVariationID)
+
+	if extra.Valid {
+		fields := map[string]interface{}{}
+		if err := json.Unmarshal([]byte(extra.String), &fields); err != nil {
+			return nil, fmt.Errorf("sqlstore: invalid extra fields of %s: %w", featureKey, err)
+		}
+		for key, value := range fields {
+			if !knownFields[key] {
+				data[key] = value
+			}
+		}
+	}
+	return data, nil
+}
+
+// extraFields encodes the fields of a decision that have no column of their own as a JSON object
+func extraFields(data map[string]interface{}) (sql.NullString, error) {
+	fields := map[string]interface{}{}
+	for key, value := range data {
+		if !knownFields[key] {
+			fields[key] = value
+		}
+	}
+	if len(fields) == 0 {
+		return sql.NullString{}, nil
+	}
+
+	encoded, err := json.Marshal(fields)
+	if err != nil {
+		return sql.NullString{}, fmt.Errorf("sqlstore: encoding extra fields: %w", err)
+	}
+	return sql.NullString{String: string(encoded), Valid: true}, nil
+}
+
+// setInt adds a nullable integer column to the decision when it is set
+func setInt(data map[string]interface{}, key string, value sql.NullInt64) {
+	if value.Valid {
+		data[key] = int(value.Int64)
+	}
+}
+
+// setString adds a nullable string column to the decision when it is set
+func setString(data map[string]interface{}, key string, value sql.NullString) {
+	if value.Valid {
+		data[key] = value.String
+	}
+}
+
+// nullInt converts a decision value to a nullable integer column
+func nullInt(value interface{}) sql.NullInt64 {
+	v, ok := toInt64(value)
+	return sql.NullInt64{Int64: v, Valid: ok && v != 0}
+}
+
+// nullString converts a decision value to a nullable string column
+func nullString(value interface{}) sql.NullString {
+	v, ok := value.(string)
+	return sql.NullString{String: v, Valid: ok && v != ""}
+}
+
+// toInt64 converts the numeric types a decision may hold to int64
+func toInt64(value interface{}) (int64, bool) {
+	switch v := value.(type) {
+	case int:
+		return int64(v), true
+	case int32:
+		return int64(v), true
+	case int64:
+		return v, true
+	case float64:
+		if v == math.Trunc(v) {
+			return int64(v), true
+		}
+	}
+	return 0, false
+}
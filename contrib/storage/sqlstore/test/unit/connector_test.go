@@ -0,0 +1,355 @@
+/**
+ * Copyright 2025 Wingify Software Pvt. Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package unit
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	vwo "github.com/wingify/vwo-fme-go-sdk"
+	"github.com/wingify/vwo-fme-go-sdk/contrib/storage/sqlstore"
+	vwostorage "github.com/wingify/vwo-fme-go-sdk/pkg/storage"
+	"github.com/wingify/wingify-fme-go-sdk/pkg/enums"
+	"github.com/wingify/wingify-fme-go-sdk/pkg/packages/storage"
+	_ "modernc.org/sqlite"
+)
+
+var (
+	_ storage.Connector         = (*sqlstore.Connector)(nil)
+	_ vwostorage.BatchConnector = (*sqlstore.Connector)(nil)
+	_ vwostorage.Scanner        = (*sqlstore.Connector)(nil)
+	_ vwostorage.Deleter        = (*sqlstore.Connector)(nil)
+)
+
+func openDB(t *testing.T) *sql.DB {
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "vwo.sqlite"))
+	assert.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func newConnector(t *testing.T, db *sql.DB, opts sqlstore.Options) *sqlstore.Connector {
+	opts.Dialect = sqlstore.SQLite
+	connector, err := sqlstore.New(db, opts)
+	assert.NoError(t, err)
+	assert.NoError(t, connector.Migrate(context.Background()))
+	return connector
+}
+
+func rolloutDecision(featureKey string, userID string, variationID int) map[string]interface{} {
+	return map[string]interface{}{
+		"featureKey":         featureKey,
+		"userId":             userID,
+		"featureId":          1,
+		"rolloutKey":         featureKey + "_rollout",
+		"rolloutId":          2,
+		"rolloutVariationId": variationID,
+	}
+}
+
+func TestSQLConnectorMigrations(t *testing.T) {
+	t.Run("AppliesAllMigrations", func(t *testing.T) {
+		db := openDB(t)
+		connector := newConnector(t, db, sqlstore.Options{})
+
+		version, err := connector.SchemaVersion(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, sqlstore.LatestSchemaVersion(), version)
+
+		var count int
+		assert.NoError(t, db.QueryRow("SELECT COUNT(*) FROM vwo_decisions_migrations").Scan(&count))
+		assert.Equal(t, sqlstore.LatestSchemaVersion(), count)
+	})
+
+	t.Run("IsIdempotent", func(t *testing.T) {
+		db := openDB(t)
+		connector := newConnector(t, db, sqlstore.Options{})
+		assert.NoError(t, connector.Set(rolloutDecision("feature1", "user-1", 1)))
+
+		assert.NoError(t, connector.Migrate(context.Background()))
+		data, err := connector.Get("feature1", "user-1")
+		assert.NoError(t, err)
+		assert.NotNil(t, data)
+	})
+
+	t.Run("ResumesFromRecordedVersion", func(t *testing.T) {
+		db := openDB(t)
+		connector := newConnector(t, db, sqlstore.Options{})
+
+		_, err := db.Exec("DROP INDEX vwo_decisions_user_id_idx")
+		assert.NoError(t, err)
+		_, err = db.Exec("DELETE FROM vwo_decisions_migrations WHERE version = 2")
+		assert.NoError(t, err)
+
+		assert.NoError(t, connector.Migrate(context.Background()))
+		var name string
+		assert.NoError(t, db.QueryRow("SELECT name FROM sqlite_master WHERE type = 'index' AND name = 'vwo_decisions_user_id_idx'").Scan(&name))
+		assert.NoError(t, connector.Set(rolloutDecision("feature1", "user-1", 1)))
+	})
+
+	t.Run("ValidatesOptions", func(t *testing.T) {
+		db := openDB(t)
+
+		_, err := sqlstore.New(db, sqlstore.Options{})
+		assert.Error(t, err)
+		_, err = sqlstore.New(db, sqlstore.Options{Dialect: sqlstore.SQLite, Table: "decisions; DROP TABLE users"})
+		assert.Error(t, err)
+		_, err = sqlstore.New(db, sqlstore.Options{Dialect: sqlstore.Postgres, Table: "app_decisions"})
+		assert.NoError(t, err)
+	})
+}
+
+func TestSQLConnector(t *testing.T) {
+	t.Run("SetAndGet", func(t *testing.T) {
+		connector := newConnector(t, openDB(t), sqlstore.Options{})
+
+		data, err := connector.Get("feature1", "user-1")
+		assert.NoError(t, err)
+		assert.Nil(t, data)
+
+		assert.NoError(t, connector.Set(rolloutDecision("feature1", "user-1", 1)))
+		data, err = connector.Get("feature1", "user-1")
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{
+			"featureKey":         "feature1",
+			"userId":             "user-1",
+			"featureId":          1,
+			"rolloutKey":         "feature1_rollout",
+			"rolloutId":          2,
+			"rolloutVariationId": 1,
+		}, data)
+	})
+
+	t.Run("UpsertKeepsCreatedAt", func(t *testing.T) {
+		db := openDB(t)
+		connector := newConnector(t, db, sqlstore.Options{Table: "app_decisions"})
+
+		assert.NoError(t, connector.Set(rolloutDecision("feature1", "user-1", 1)))
+		var createdAt time.Time
+		assert.NoError(t, db.QueryRow("SELECT created_at FROM app_decisions").Scan(&createdAt))
+
+		time.Sleep(5 * time.Millisecond)
+		decision := rolloutDecision("feature1", "user-1", 2)
+		decision["experimentKey"] = "feature1_experiment"
+		decision["experimentId"] = 3.0
+		decision["experimentVariationId"] = 2.0
+		assert.NoError(t, connector.Set(decision))
+
+		var count int
+		assert.NoError(t, db.QueryRow("SELECT COUNT(*) FROM app_decisions").Scan(&count))
+		assert.Equal(t, 1, count)
+
+		var created, updated time.Time
+		assert.NoError(t, db.QueryRow("SELECT created_at, updated_at FROM app_decisions").Scan(&created, &updated))
+		assert.True(t, created.Equal(createdAt))
+		assert.True(t, updated.After(createdAt))
+
+		data, _ := connector.Get("feature1", "user-1")
+		assert.Equal(t, 2, data.(map[string]interface{})["rolloutVariationId"])
+		assert.Equal(t, "feature1_experiment", data.(map[string]interface{})["experimentKey"])
+		assert.Equal(t, 2, data.(map[string]interface{})["experimentVariationId"])
+	})
+
+	t.Run("InvalidDecision", func(t *testing.T) {
+		connector := newConnector(t, openDB(t), sqlstore.Options{})
+
+		assert.Error(t, connector.Set(map[string]interface{}{"userId": "user-1"}))
+		assert.Error(t, connector.Set(map[string]interface{}{"featureKey": "feature1"}))
+	})
+
+	t.Run("Bulk", func(t *testing.T) {
+		connector := newConnector(t, openDB(t), sqlstore.Options{})
+
+		assert.NoError(t, connector.SetMany([]map[string]interface{}{
+			rolloutDecision("feature1", "user-1", 1),
+			rolloutDecision("feature2", "user-1", 2),
+			rolloutDecision("feature1", "user-2", 1),
+		}))
+
+		result, err := connector.GetMany("user-1", []string{"feature1", "feature2", "feature3"})
+		assert.NoError(t, err)
+		assert.Len(t, result, 2)
+		assert.Equal(t, 2, result["feature2"]["rolloutVariationId"])
+
+		err = connector.SetMany([]map[string]interface{}{rolloutDecision("feature3", "user-1", 1), {"featureKey": "feature4"}})
+		assert.Error(t, err)
+		data, _ := connector.Get("feature3", "user-1")
+		assert.Nil(t, data)
+	})
+}
+
+func TestSQLConnectorWrapped(t *testing.T) {
+	raw, err := os.ReadFile("../../../../../test/data/settings/BASIC_ROLLOUT_SETTINGS.json")
+	assert.NoError(t, err)
+	keys := vwostorage.StaticKeys{{ID: "k1", Secret: []byte("0123456789abcdef0123456789abcdef")}}
+
+	// extended adds a variation to the rollout so that stored fingerprints no longer match
+	var decoded map[string]interface{}
+	assert.NoError(t, json.Unmarshal(raw, &decoded))
+	campaign := decoded["campaigns"].([]interface{})[0].(map[string]interface{})
+	campaign["variations"] = append(campaign["variations"].([]interface{}), map[string]interface{}{"id": 2, "name": "Rollout-rule-2"})
+	extended, err := json.Marshal(decoded)
+	assert.NoError(t, err)
+
+	decision := map[string]interface{}{
+		"featureKey":         "feature1",
+		"userId":             "user-1",
+		"featureId":          1,
+		"rolloutKey":         "feature1_rolloutRule1",
+		"rolloutId":          1,
+		"rolloutVariationId": 1,
+	}
+
+	t.Run("KeepsExtraFields", func(t *testing.T) {
+		connector := newConnector(t, openDB(t), sqlstore.Options{})
+		data := rolloutDecision("feature1", "user-1", 1)
+		data["note"] = "kept"
+		assert.NoError(t, connector.Set(data))
+
+		stored, err := connector.Get("feature1", "user-1")
+		assert.NoError(t, err)
+		assert.Equal(t, "kept", stored.(map[string]interface{})["note"])
+	})
+
+	t.Run("Encrypted", func(t *testing.T) {
+		encrypted := vwostorage.Encrypted(newConnector(t, openDB(t), sqlstore.Options{}), keys)
+		assert.NoError(t, encrypted.Set(decision))
+
+		stored, err := encrypted.Get("feature1", "user-1")
+		assert.NoError(t, err)
+		assert.Equal(t, 1, decisionInt(stored, "rolloutVariationId"))
+	})
+
+	t.Run("Versioned", func(t *testing.T) {
+		versioned := vwostorage.Versioned(newConnector(t, openDB(t), sqlstore.Options{}), vwostorage.VersionOptions{
+			Settings:      vwostorage.StaticSettings(raw),
+			ChangedPolicy: vwostorage.DropStale,
+		})
+		assert.NoError(t, versioned.Set(decision))
+
+		stored, err := versioned.Get("feature1", "user-1")
+		assert.NoError(t, err)
+		assert.NotNil(t, stored)
+
+		versioned.UseSettings(vwostorage.StaticSettings(extended))
+		stored, err = versioned.Get("feature1", "user-1")
+		assert.NoError(t, err)
+		assert.Nil(t, stored)
+		assert.Equal(t, uint64(1), versioned.Report().Invalidated[vwostorage.ReasonCampaignChanged])
+	})
+
+	t.Run("VersionedAndEncrypted", func(t *testing.T) {
+		encrypted := vwostorage.Encrypted(newConnector(t, openDB(t), sqlstore.Options{}), keys)
+		versioned := vwostorage.Versioned(encrypted, vwostorage.VersionOptions{
+			Settings:      vwostorage.StaticSettings(raw),
+			ChangedPolicy: vwostorage.DropStale,
+		})
+		assert.NoError(t, versioned.Set(decision))
+
+		stored, err := versioned.Get("feature1", "user-1")
+		assert.NoError(t, err)
+		assert.Equal(t, 1, decisionInt(stored, "rolloutVariationId"))
+
+		versioned.UseSettings(vwostorage.StaticSettings(extended))
+		stored, err = versioned.Get("feature1", "user-1")
+		assert.NoError(t, err)
+		assert.Nil(t, stored)
+		assert.Equal(t, uint64(1), versioned.Report().Invalidated[vwostorage.ReasonCampaignChanged])
+	})
+}
+
+// decisionInt returns a numeric field of a decision decoded from JSON or read from a column
+func decisionInt(decision interface{}, key string) int {
+	switch n := decision.(map[string]interface{})[key].(type) {
+	case int:
+		return n
+	case float64:
+		return int(n)
+	}
+	return 0
+}
+
+func TestSQLConnectorAdmin(t *testing.T) {
+	ctx := context.Background()
+	connector := newConnector(t, openDB(t), sqlstore.Options{})
+	assert.NoError(t, connector.SetMany([]map[string]interface{}{
+		rolloutDecision("feature1", "user-1", 1),
+		rolloutDecision("feature2", "user-1", 2),
+		rolloutDecision("feature1", "user-2", 1),
+	}))
+	admin := vwostorage.NewAdmin(connector)
+
+	t.Run("Export", func(t *testing.T) {
+		var export bytes.Buffer
+		exported, err := admin.Export(ctx, &export, "")
+		assert.NoError(t, err)
+		assert.Equal(t, 3, exported)
+
+		target := newConnector(t, openDB(t), sqlstore.Options{})
+		imported, err := vwostorage.NewAdmin(target).Import(ctx, &export)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, imported)
+		data, _ := target.Get("feature2", "user-1")
+		assert.Equal(t, 2, data.(map[string]interface{})["rolloutVariationId"])
+	})
+
+	t.Run("ForgetUser", func(t *testing.T) {
+		deleted, err := admin.ForgetUser(ctx, "user-1")
+		assert.NoError(t, err)
+		assert.Equal(t, 2, deleted)
+
+		decisions, err := admin.ListUser(ctx, "user-1")
+		assert.NoError(t, err)
+		assert.Empty(t, decisions)
+		data, _ := connector.Get("feature1", "user-2")
+		assert.NotNil(t, data)
+	})
+}
+
+func TestSQLConnectorWithSDK(t *testing.T) {
+	settings, err := os.ReadFile("../../../../../test/data/settings/BASIC_ROLLOUT_SETTINGS.json")
+	assert.NoError(t, err)
+
+	connector := newConnector(t, openDB(t), sqlstore.Options{})
+
+	vwoClient, err := vwo.Init(map[string]interface{}{
+		enums.OptionSDKKey.GetValue():    "abcd",
+		enums.OptionAccountID.GetValue(): 12345,
+		enums.OptionSettings.GetValue():  string(settings),
+		enums.OptionStorage.GetValue():   connector,
+	})
+	assert.NoError(t, err)
+
+	flag, err := vwoClient.GetFlag("feature1", map[string]interface{}{"id": "user-1"})
+	assert.NoError(t, err)
+	assert.True(t, flag.IsEnabled())
+
+	data, err := connector.Get("feature1", "user-1")
+	assert.NoError(t, err)
+	assert.NotEmpty(t, data.(map[string]interface{})["rolloutKey"])
+
+	flag, err = vwoClient.GetFlag("feature1", map[string]interface{}{"id": "user-1"})
+	assert.NoError(t, err)
+	assert.True(t, flag.IsEnabled())
+}
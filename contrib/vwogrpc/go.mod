@@ -0,0 +1,25 @@
+module github.com/wingify/vwo-fme-go-sdk/contrib/vwogrpc
+
+// go 1.25 is the minimum required by google.golang.org/grpc; the root module stays at go 1.16
+go 1.25.0
+
+require (
+	github.com/stretchr/testify v1.7.5
+	github.com/wingify/vwo-fme-go-sdk v1.61.0
+	github.com/wingify/wingify-fme-go-sdk v1.60.0
+	google.golang.org/grpc v1.84.0
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	golang.org/x/net v0.57.0 // indirect
+	golang.org/x/sys v0.47.0 // indirect
+	golang.org/x/text v0.40.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20260706201446-f0a921348800 // indirect
+	google.golang.org/protobuf v1.36.11 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+// replace builds against the working tree; it is ignored by modules that depend on this one
+replace github.com/wingify/vwo-fme-go-sdk => ../..
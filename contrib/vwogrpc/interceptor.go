@@ -0,0 +1,147 @@
+/**
+ * Copyright 2025 Wingify Software Pvt. Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package vwogrpc provides gRPC interceptors that build the VWO user context from
+// incoming metadata, attach a lazily-evaluating flag accessor to the handler context
+// and optionally forward evaluated assignments to downstream services.
+package vwogrpc
+
+import (
+	"context"
+
+	"github.com/wingify/vwo-fme-go-sdk/pkg/flagcontext"
+	"github.com/wingify/wingify-fme-go-sdk/pkg/models"
+	"google.golang.org/grpc"
+)
+
+// Metadata keys used when the corresponding option is left empty
+const (
+	DefaultUserIDKey        = "x-vwo-user-id"
+	DefaultSessionIDKey     = "x-vwo-session-id"
+	DefaultBucketingSeedKey = "x-vwo-bucketing-seed"
+	DefaultAssignmentsKey   = "x-vwo-assignments"
+	DefaultUserAgentKey     = "x-vwo-user-agent"
+)
+
+// Options configures the interceptors
+type Options struct {
+	// UserIDKey is the metadata key holding the user id
+	UserIDKey string
+
+	// SessionIDKey is the metadata key holding the session id
+	SessionIDKey string
+
+	// BucketingSeedKey is the metadata key holding the bucketing seed
+	BucketingSeedKey string
+
+	// AssignmentsKey is the metadata key holding forwarded assignments
+	AssignmentsKey string
+
+	// UserAgentKey is the metadata key holding the end user's user agent. The user-agent
+	// metadata set by the gRPC transport describes the calling client and is not used.
+	UserAgentKey string
+
+	// CustomVariables optionally returns custom variables for the call
+	CustomVariables func(ctx context.Context) map[string]interface{}
+
+	// ReuseAssignments seeds the accessor with decisions forwarded by the caller.
+	// Only enable it for traffic coming from trusted internal services.
+	ReuseAssignments bool
+
+	// ForwardAssignments makes the client interceptors send the decisions evaluated so far
+	ForwardAssignments bool
+
+	// OnError is called when a flag evaluation or assignment decoding fails
+	OnError func(ctx context.Context, featureKey string, err error)
+}
+
+// withDefaults fills the empty metadata keys
+func (o Options) withDefaults() Options {
+	if o.UserIDKey == "" {
+		o.UserIDKey = DefaultUserIDKey
+	}
+	if o.SessionIDKey == "" {
+		o.SessionIDKey = DefaultSessionIDKey
+	}
+	if o.BucketingSeedKey == "" {
+		o.BucketingSeedKey = DefaultBucketingSeedKey
+	}
+	if o.AssignmentsKey == "" {
+		o.AssignmentsKey = DefaultAssignmentsKey
+	}
+	if o.UserAgentKey == "" {
+		o.UserAgentKey = DefaultUserAgentKey
+	}
+	return o
+}
+
+// UnaryServerInterceptor returns a server interceptor that attaches the flags of the caller to the handler context
+func UnaryServerInterceptor(client flagcontext.Client, opts Options) grpc.UnaryServerInterceptor {
+	opts = opts.withDefaults()
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		return handler(attachFlags(ctx, client, opts), req)
+	}
+}
+
+// StreamServerInterceptor returns a server interceptor that attaches the flags of the caller to the stream context
+func StreamServerInterceptor(client flagcontext.Client, opts Options) grpc.StreamServerInterceptor {
+	opts = opts.withDefaults()
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &serverStream{ServerStream: stream, ctx: attachFlags(stream.Context(), client, opts)})
+	}
+}
+
+// UnaryClientInterceptor returns a client interceptor that propagates the user context of ctx to the callee
+func UnaryClientInterceptor(opts Options) grpc.UnaryClientInterceptor {
+	opts = opts.withDefaults()
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, callOpts ...grpc.CallOption) error {
+		return invoker(propagate(ctx, opts), method, req, reply, cc, callOpts...)
+	}
+}
+
+// StreamClientInterceptor returns a client interceptor that propagates the user context of ctx to the callee
+func StreamClientInterceptor(opts Options) grpc.StreamClientInterceptor {
+	opts = opts.withDefaults()
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, callOpts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(propagate(ctx, opts), desc, cc, method, callOpts...)
+	}
+}
+
+// FromContext returns the flags attached by the server interceptors
+func FromContext(ctx context.Context) (*flagcontext.Flags, bool) {
+	return flagcontext.FromContext(ctx)
+}
+
+// FlagFromContext returns the flag for the feature, or a disabled flag when no flags are attached
+func FlagFromContext(ctx context.Context, featureKey string) models.GetFlagResponse {
+	return flagcontext.FlagFromContext(ctx, featureKey)
+}
+
+// IsEnabled returns whether the feature is enabled for the caller
+func IsEnabled(ctx context.Context, featureKey string) bool {
+	return flagcontext.IsEnabled(ctx, featureKey)
+}
+
+// serverStream overrides the context of a grpc.ServerStream
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context returns the context carrying the flags
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
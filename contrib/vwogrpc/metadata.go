@@ -0,0 +1,145 @@
+/**
+ * Copyright 2025 Wingify Software Pvt. Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwogrpc
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+
+	"github.com/wingify/vwo-fme-go-sdk/pkg/flagcontext"
+	"github.com/wingify/wingify-fme-go-sdk/pkg/enums"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// attachFlags builds the user context from the incoming metadata and stores the flags in ctx.
+// Calls without a user id are left untouched.
+func attachFlags(ctx context.Context, client flagcontext.Client, opts Options) context.Context {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	userID := firstValue(md, opts.UserIDKey)
+	if userID == "" {
+		return ctx
+	}
+
+	userContext := map[string]interface{}{
+		enums.ContextID.GetValue(): userID,
+	}
+
+	if sessionID := firstValue(md, opts.SessionIDKey); sessionID != "" {
+		if parsed, err := strconv.ParseInt(sessionID, 10, 64); err == nil {
+			userContext[enums.ContextSessionID.GetValue()] = parsed
+		}
+	}
+
+	if seed := firstValue(md, opts.BucketingSeedKey); seed != "" {
+		userContext[enums.ContextBucketingSeed.GetValue()] = seed
+	}
+
+	if userAgent := firstValue(md, opts.UserAgentKey); userAgent != "" {
+		userContext[enums.ContextUserAgent.GetValue()] = userAgent
+	}
+
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		if host, _, err := net.SplitHostPort(p.Addr.String()); err == nil && net.ParseIP(host) != nil {
+			userContext[enums.ContextIPAddress.GetValue()] = host
+		}
+	}
+
+	if opts.CustomVariables != nil {
+		if customVariables := opts.CustomVariables(ctx); len(customVariables) > 0 {
+			userContext[enums.ContextCustomVariables.GetValue()] = customVariables
+		}
+	}
+
+	var onError func(featureKey string, err error)
+	if opts.OnError != nil {
+		onError = func(featureKey string, err error) {
+			opts.OnError(ctx, featureKey, err)
+		}
+	}
+
+	flags := flagcontext.NewFlags(client, userContext, onError)
+
+	if opts.ReuseAssignments {
+		if encoded := firstValue(md, opts.AssignmentsKey); encoded != "" {
+			assignments, err := flagcontext.DecodeAssignments(encoded)
+			if err != nil {
+				if onError != nil {
+					onError("", err)
+				}
+			} else {
+				for featureKey, flag := range assignments {
+					flags.Seed(featureKey, flag)
+				}
+			}
+		}
+	}
+
+	return flagcontext.NewContext(ctx, flags)
+}
+
+// propagate copies the user context and, optionally, the evaluated assignments into the outgoing metadata.
+// Keys already present in the outgoing metadata are not overwritten.
+func propagate(ctx context.Context, opts Options) context.Context {
+	flags, ok := flagcontext.FromContext(ctx)
+	if !ok {
+		return ctx
+	}
+
+	outgoing, _ := metadata.FromOutgoingContext(ctx)
+	userContext := flags.UserContext()
+
+	var pairs []string
+	add := func(key string, value interface{}) {
+		if value == nil || len(outgoing.Get(key)) > 0 {
+			return
+		}
+		pairs = append(pairs, key, fmt.Sprint(value))
+	}
+
+	add(opts.UserIDKey, userContext[enums.ContextID.GetValue()])
+	add(opts.SessionIDKey, userContext[enums.ContextSessionID.GetValue()])
+	add(opts.BucketingSeedKey, userContext[enums.ContextBucketingSeed.GetValue()])
+	add(opts.UserAgentKey, userContext[enums.ContextUserAgent.GetValue()])
+
+	if opts.ForwardAssignments {
+		if evaluated := flags.Evaluated(); len(evaluated) > 0 {
+			if encoded, err := flagcontext.EncodeAssignments(evaluated); err == nil {
+				add(opts.AssignmentsKey, encoded)
+			} else if opts.OnError != nil {
+				opts.OnError(ctx, "", err)
+			}
+		}
+	}
+
+	if len(pairs) == 0 {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, pairs...)
+}
+
+// firstValue returns the first value of a metadata key
+func firstValue(md metadata.MD, key string) string {
+	values := md.Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
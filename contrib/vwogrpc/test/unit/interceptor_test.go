@@ -0,0 +1,243 @@
+/**
+ * Copyright 2025 Wingify Software Pvt. Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package unit
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wingify/vwo-fme-go-sdk/contrib/vwogrpc"
+	"github.com/wingify/wingify-fme-go-sdk/pkg/models"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// fakeFlagClient records GetFlag calls and enables the features listed in enabled
+type fakeFlagClient struct {
+	mu       sync.Mutex
+	enabled  map[string]bool
+	calls    []string
+	contexts []map[string]interface{}
+}
+
+func newFakeFlagClient(enabled ...string) *fakeFlagClient {
+	client := &fakeFlagClient{enabled: map[string]bool{}}
+	for _, featureKey := range enabled {
+		client.enabled[featureKey] = true
+	}
+	return client
+}
+
+func (c *fakeFlagClient) GetFlag(featureKey string, context map[string]interface{}) (models.GetFlagResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.calls = append(c.calls, featureKey)
+	c.contexts = append(c.contexts, context)
+	variables := []*models.Variable{models.NewVariable("limit", 10, "integer", 1)}
+	return models.NewGetFlag(c.enabled[featureKey], variables, "uuid", 1), nil
+}
+
+// fakeServerStream is a grpc.ServerStream carrying only a context
+type fakeServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *fakeServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func incomingContext(pairs ...string) context.Context {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(pairs...))
+	return peer.NewContext(ctx, &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("10.1.2.3"), Port: 5000}})
+}
+
+func TestGRPCServerInterceptors(t *testing.T) {
+	t.Run("UnaryBuildsUserContextFromMetadata", func(t *testing.T) {
+		client := newFakeFlagClient("feature1")
+		interceptor := vwogrpc.UnaryServerInterceptor(client, vwogrpc.Options{
+			CustomVariables: func(ctx context.Context) map[string]interface{} {
+				return map[string]interface{}{"tier": "gold"}
+			},
+		})
+
+		ctx := incomingContext(
+			"x-vwo-user-id", "user-1",
+			"x-vwo-session-id", "1700000000",
+			"x-vwo-bucketing-seed", "company-abc",
+			"x-vwo-user-agent", "Mozilla/5.0",
+			"user-agent", "grpc-go/test",
+		)
+
+		_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, req interface{}) (interface{}, error) {
+			assert.True(t, vwogrpc.IsEnabled(ctx, "feature1"))
+			assert.False(t, vwogrpc.IsEnabled(ctx, "feature2"))
+			return nil, nil
+		})
+		assert.NoError(t, err)
+
+		assert.Equal(t, []string{"feature1", "feature2"}, client.calls)
+		userContext := client.contexts[0]
+		assert.Equal(t, "user-1", userContext["id"])
+		assert.Equal(t, int64(1700000000), userContext["sessionId"])
+		assert.Equal(t, "company-abc", userContext["bucketingSeed"])
+		assert.Equal(t, "Mozilla/5.0", userContext["userAgent"])
+		assert.Equal(t, "10.1.2.3", userContext["ipAddress"])
+		assert.Equal(t, map[string]interface{}{"tier": "gold"}, userContext["customVariables"])
+	})
+
+	t.Run("EvaluatesLazilyAndOnce", func(t *testing.T) {
+		client := newFakeFlagClient("feature1")
+		interceptor := vwogrpc.UnaryServerInterceptor(client, vwogrpc.Options{})
+
+		_, _ = interceptor(incomingContext("x-vwo-user-id", "user-1"), nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, req interface{}) (interface{}, error) {
+			assert.Empty(t, client.calls)
+			vwogrpc.IsEnabled(ctx, "feature1")
+			vwogrpc.IsEnabled(ctx, "feature1")
+			return nil, nil
+		})
+		assert.Equal(t, []string{"feature1"}, client.calls)
+	})
+
+	t.Run("IgnoresTransportUserAgent", func(t *testing.T) {
+		client := newFakeFlagClient("feature1")
+		interceptor := vwogrpc.UnaryServerInterceptor(client, vwogrpc.Options{})
+
+		_, _ = interceptor(incomingContext("x-vwo-user-id", "user-1", "user-agent", "grpc-go/test"), nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, req interface{}) (interface{}, error) {
+			vwogrpc.IsEnabled(ctx, "feature1")
+			return nil, nil
+		})
+		assert.NotContains(t, client.contexts[0], "userAgent")
+	})
+
+	t.Run("MissingUserIDLeavesContextUntouched", func(t *testing.T) {
+		client := newFakeFlagClient("feature1")
+		interceptor := vwogrpc.UnaryServerInterceptor(client, vwogrpc.Options{})
+
+		_, _ = interceptor(incomingContext("x-other", "value"), nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, req interface{}) (interface{}, error) {
+			_, ok := vwogrpc.FromContext(ctx)
+			assert.False(t, ok)
+			assert.False(t, vwogrpc.IsEnabled(ctx, "feature1"))
+			return nil, nil
+		})
+		assert.Empty(t, client.calls)
+	})
+
+	t.Run("CustomMetadataKeys", func(t *testing.T) {
+		client := newFakeFlagClient()
+		interceptor := vwogrpc.UnaryServerInterceptor(client, vwogrpc.Options{UserIDKey: "account-id"})
+
+		_, _ = interceptor(incomingContext("account-id", "acc-9"), nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, req interface{}) (interface{}, error) {
+			vwogrpc.IsEnabled(ctx, "feature1")
+			return nil, nil
+		})
+		assert.Equal(t, "acc-9", client.contexts[0]["id"])
+	})
+
+	t.Run("StreamAttachesFlags", func(t *testing.T) {
+		client := newFakeFlagClient("feature1")
+		interceptor := vwogrpc.StreamServerInterceptor(client, vwogrpc.Options{})
+
+		stream := &fakeServerStream{ctx: incomingContext("x-vwo-user-id", "user-1")}
+		err := interceptor(nil, stream, &grpc.StreamServerInfo{}, func(srv interface{}, stream grpc.ServerStream) error {
+			assert.True(t, vwogrpc.IsEnabled(stream.Context(), "feature1"))
+			return nil
+		})
+		assert.NoError(t, err)
+	})
+}
+
+func TestGRPCAssignmentForwarding(t *testing.T) {
+	// upstream service evaluates feature1 and calls downstream
+	upstreamClient := newFakeFlagClient("feature1")
+	serverInterceptor := vwogrpc.UnaryServerInterceptor(upstreamClient, vwogrpc.Options{})
+	clientInterceptor := vwogrpc.UnaryClientInterceptor(vwogrpc.Options{ForwardAssignments: true})
+
+	var outgoing metadata.MD
+	_, _ = serverInterceptor(incomingContext("x-vwo-user-id", "user-1", "x-vwo-session-id", "42", "x-vwo-user-agent", "Mozilla/5.0"), nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, req interface{}) (interface{}, error) {
+		assert.True(t, vwogrpc.IsEnabled(ctx, "feature1"))
+		invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			outgoing, _ = metadata.FromOutgoingContext(ctx)
+			return nil
+		}
+		return nil, clientInterceptor(ctx, "/svc/Method", nil, nil, nil, invoker)
+	})
+
+	assert.Equal(t, []string{"user-1"}, outgoing.Get("x-vwo-user-id"))
+	assert.Equal(t, []string{"42"}, outgoing.Get("x-vwo-session-id"))
+	assert.Equal(t, []string{"Mozilla/5.0"}, outgoing.Get("x-vwo-user-agent"))
+	assert.Len(t, outgoing.Get("x-vwo-assignments"), 1)
+
+	t.Run("DownstreamReusesDecision", func(t *testing.T) {
+		downstreamClient := newFakeFlagClient()
+		interceptor := vwogrpc.UnaryServerInterceptor(downstreamClient, vwogrpc.Options{ReuseAssignments: true})
+
+		ctx := metadata.NewIncomingContext(context.Background(), outgoing)
+		_, _ = interceptor(ctx, nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, req interface{}) (interface{}, error) {
+			flag := vwogrpc.FlagFromContext(ctx, "feature1")
+			assert.True(t, flag.IsEnabled())
+			assert.Equal(t, float64(10), flag.GetVariable("limit", 0))
+			return nil, nil
+		})
+		assert.Empty(t, downstreamClient.calls)
+	})
+
+	t.Run("DownstreamIgnoresAssignmentsByDefault", func(t *testing.T) {
+		downstreamClient := newFakeFlagClient()
+		interceptor := vwogrpc.UnaryServerInterceptor(downstreamClient, vwogrpc.Options{})
+
+		ctx := metadata.NewIncomingContext(context.Background(), outgoing)
+		_, _ = interceptor(ctx, nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, req interface{}) (interface{}, error) {
+			assert.False(t, vwogrpc.IsEnabled(ctx, "feature1"))
+			return nil, nil
+		})
+		assert.Equal(t, []string{"feature1"}, downstreamClient.calls)
+	})
+
+	t.Run("InvalidAssignmentsAreReported", func(t *testing.T) {
+		var reported []error
+		interceptor := vwogrpc.UnaryServerInterceptor(newFakeFlagClient(), vwogrpc.Options{
+			ReuseAssignments: true,
+			OnError: func(ctx context.Context, featureKey string, err error) {
+				reported = append(reported, err)
+			},
+		})
+
+		_, _ = interceptor(incomingContext("x-vwo-user-id", "user-1", "x-vwo-assignments", "%%%"), nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return nil, nil
+		})
+		assert.Len(t, reported, 1)
+	})
+
+	t.Run("ExistingOutgoingKeysAreKept", func(t *testing.T) {
+		_, _ = serverInterceptor(incomingContext("x-vwo-user-id", "user-1"), nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, req interface{}) (interface{}, error) {
+			ctx = metadata.AppendToOutgoingContext(ctx, "x-vwo-user-id", "explicit")
+			var md metadata.MD
+			invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+				md, _ = metadata.FromOutgoingContext(ctx)
+				return nil
+			}
+			_ = clientInterceptor(ctx, "/svc/Method", nil, nil, nil, invoker)
+			assert.Equal(t, []string{"explicit"}, md.Get("x-vwo-user-id"))
+			return nil, nil
+		})
+	})
+}
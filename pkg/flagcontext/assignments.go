@@ -0,0 +1,82 @@
+/**
+ * Copyright 2025 Wingify Software Pvt. Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package flagcontext
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/wingify/wingify-fme-go-sdk/pkg/models"
+)
+
+// assignment is the wire representation of an evaluated flag
+type assignment struct {
+	Enabled   bool                     `json:"e"`
+	Variables []map[string]interface{} `json:"v,omitempty"`
+	UUID      string                   `json:"u,omitempty"`
+	SessionID int64                    `json:"s,omitempty"`
+}
+
+// EncodeAssignments serializes the evaluated flags into a header-safe string
+// so that downstream services can reuse the same decisions.
+func EncodeAssignments(flags map[string]models.GetFlagResponse) (string, error) {
+	assignments := make(map[string]assignment, len(flags))
+	for featureKey, flag := range flags {
+		if flag == nil {
+			continue
+		}
+		assignments[featureKey] = assignment{
+			Enabled:   flag.IsEnabled(),
+			Variables: flag.GetVariables(),
+			UUID:      flag.GetUUID(),
+			SessionID: flag.GetSessionId(),
+		}
+	}
+
+	payload, err := json.Marshal(assignments)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(payload), nil
+}
+
+// DecodeAssignments parses a value produced by EncodeAssignments
+func DecodeAssignments(value string) (map[string]models.GetFlagResponse, error) {
+	payload, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid assignments encoding: %v", err)
+	}
+
+	var assignments map[string]assignment
+	if err := json.Unmarshal(payload, &assignments); err != nil {
+		return nil, fmt.Errorf("invalid assignments payload: %v", err)
+	}
+
+	flags := make(map[string]models.GetFlagResponse, len(assignments))
+	for featureKey, assigned := range assignments {
+		variables := make([]*models.Variable, 0, len(assigned.Variables))
+		for _, variable := range assigned.Variables {
+			key, _ := variable["key"].(string)
+			varType, _ := variable["type"].(string)
+			id, _ := variable["id"].(float64)
+			variables = append(variables, models.NewVariable(key, variable["value"], varType, int(id)))
+		}
+		flags[featureKey] = models.NewGetFlag(assigned.Enabled, variables, assigned.UUID, assigned.SessionID)
+	}
+	return flags, nil
+}
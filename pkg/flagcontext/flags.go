@@ -0,0 +1,164 @@
+/**
+ * Copyright 2025 Wingify Software Pvt. Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package flagcontext carries a lazily-evaluating flag accessor through a context.Context.
+// It is shared by the transport integrations (net/http, gRPC).
+package flagcontext
+
+import (
+	"context"
+	"sync"
+
+	"github.com/wingify/wingify-fme-go-sdk/pkg/models"
+)
+
+// Client is the subset of VWOClient used to evaluate flags
+type Client interface {
+	GetFlag(featureKey string, context map[string]interface{}) (models.GetFlagResponse, error)
+}
+
+// contextKey is the unexported key under which Flags are stored in a context.Context
+type contextKey struct{}
+
+// Flags holds the user context of a request and the flags evaluated for it.
+// Flags not evaluated up front are evaluated on first access and cached for the request.
+type Flags struct {
+	client      Client
+	userContext map[string]interface{}
+	onError     func(featureKey string, err error)
+
+	mu        sync.Mutex
+	evaluated map[string]models.GetFlagResponse
+}
+
+// NewFlags creates a new Flags instance for the given user context
+func NewFlags(client Client, userContext map[string]interface{}, onError func(featureKey string, err error)) *Flags {
+	return &Flags{
+		client:      client,
+		userContext: userContext,
+		onError:     onError,
+		evaluated:   make(map[string]models.GetFlagResponse),
+	}
+}
+
+// UserContext returns a copy of the VWO user context
+func (f *Flags) UserContext() map[string]interface{} {
+	userContext := make(map[string]interface{}, len(f.userContext))
+	for key, value := range f.userContext {
+		userContext[key] = value
+	}
+	return userContext
+}
+
+// Get returns the flag for the feature, evaluating it if needed.
+// A failed evaluation returns a disabled flag and is not cached.
+// The lock is not held during evaluation; when two calls evaluate the same feature, the first result is kept.
+func (f *Flags) Get(featureKey string) models.GetFlagResponse {
+	f.mu.Lock()
+	flag, ok := f.evaluated[featureKey]
+	f.mu.Unlock()
+	if ok {
+		return flag
+	}
+
+	if f.client == nil {
+		return models.NewGetFlag(false, nil, "", 0)
+	}
+
+	flag, err := f.client.GetFlag(featureKey, f.UserContext())
+	if err != nil || flag == nil {
+		if err != nil && f.onError != nil {
+			f.onError(featureKey, err)
+		}
+		return models.NewGetFlag(false, nil, "", 0)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if stored, ok := f.evaluated[featureKey]; ok {
+		return stored
+	}
+	f.evaluated[featureKey] = flag
+	return flag
+}
+
+// Seed stores an already known decision so the feature is not evaluated again
+func (f *Flags) Seed(featureKey string, flag models.GetFlagResponse) {
+	if flag == nil {
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.evaluated[featureKey] = flag
+}
+
+// IsEnabled returns whether the feature is enabled for the user
+func (f *Flags) IsEnabled(featureKey string) bool {
+	return f.Get(featureKey).IsEnabled()
+}
+
+// GetVariable returns a variable of the feature with a default fallback
+func (f *Flags) GetVariable(featureKey string, variableKey string, defaultValue interface{}) interface{} {
+	return f.Get(featureKey).GetVariable(variableKey, defaultValue)
+}
+
+// Evaluated returns the flags evaluated so far keyed by feature key
+func (f *Flags) Evaluated() map[string]models.GetFlagResponse {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	evaluated := make(map[string]models.GetFlagResponse, len(f.evaluated))
+	for key, flag := range f.evaluated {
+		evaluated[key] = flag
+	}
+	return evaluated
+}
+
+// NewContext returns a copy of ctx carrying the flags
+func NewContext(ctx context.Context, flags *Flags) context.Context {
+	return context.WithValue(ctx, contextKey{}, flags)
+}
+
+// FromContext returns the flags stored in ctx
+func FromContext(ctx context.Context) (*Flags, bool) {
+	flags, ok := ctx.Value(contextKey{}).(*Flags)
+	return flags, ok && flags != nil
+}
+
+// UserContextFromContext returns the VWO user context stored in ctx
+func UserContextFromContext(ctx context.Context) (map[string]interface{}, bool) {
+	flags, ok := FromContext(ctx)
+	if !ok {
+		return nil, false
+	}
+	return flags.UserContext(), true
+}
+
+// FlagFromContext returns the flag for the feature, or a disabled flag when ctx carries no flags
+func FlagFromContext(ctx context.Context, featureKey string) models.GetFlagResponse {
+	flags, ok := FromContext(ctx)
+	if !ok {
+		return models.NewGetFlag(false, nil, "", 0)
+	}
+	return flags.Get(featureKey)
+}
+
+// IsEnabled returns whether the feature is enabled for the user stored in ctx
+func IsEnabled(ctx context.Context, featureKey string) bool {
+	return FlagFromContext(ctx, featureKey).IsEnabled()
+}
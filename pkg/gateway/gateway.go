@@ -0,0 +1,159 @@
+/**
+ * Copyright 2025 Wingify Software Pvt. Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package gateway provides an in-process stand-in for the VWO gateway service, which the SDK
+// needs to evaluate location, user agent and attribute list segments.
+//
+// Enrich adds the user details the gateway would return to a user context, so that these
+// segments are evaluated without any gateway. Local also serves the gateway protocol over HTTP,
+// for tests that configure the gatewayService option.
+package gateway
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// ContextKey is the key of the user details in a user context
+const ContextKey = "_wingify"
+
+// Options configures a Local gateway
+type Options struct {
+	// Locator finds the location of IP addresses; without it no location is reported
+	Locator Locator
+
+	// Lists holds the attribute lists checked by inlist(listId) segments, by list id
+	Lists map[string][]string
+
+	// Settings is served on the settings endpoints, for clients using the gateway as gatewayService
+	Settings string
+}
+
+// Details are the user details returned by the get-user-details endpoint
+type Details struct {
+	Location  map[string]string `json:"location,omitempty"`
+	UserAgent map[string]string `json:"userAgent,omitempty"`
+}
+
+// Local answers gateway lookups in process
+type Local struct {
+	locator  Locator
+	lists    map[string]map[string]bool
+	settings string
+}
+
+// New creates a Local gateway
+func New(opts Options) *Local {
+	g := &Local{
+		locator:  opts.Locator,
+		lists:    make(map[string]map[string]bool, len(opts.Lists)),
+		settings: opts.Settings,
+	}
+	for id, values := range opts.Lists {
+		set := make(map[string]bool, len(values))
+		for _, value := range values {
+			set[value] = true
+		}
+		g.lists[id] = set
+	}
+	return g
+}
+
+// Lookup returns the details of a user agent and an IP address; either may be empty
+func (g *Local) Lookup(userAgent string, ipAddress string) Details {
+	var details Details
+	if userAgent != "" {
+		if ua := ParseUserAgent(userAgent).Map(); len(ua) > 0 {
+			details.UserAgent = ua
+		}
+	}
+	if ip := net.ParseIP(strings.TrimSpace(ipAddress)); ip != nil && g.locator != nil {
+		if location, ok := g.locator.Locate(ip); ok {
+			details.Location = location.Map()
+		}
+	}
+	return details
+}
+
+// CheckAttribute returns whether an attribute value is in a list
+func (g *Local) CheckAttribute(listID string, attribute string) bool {
+	return g.lists[listID][attribute]
+}
+
+// Enrich returns a copy of a user context with the details of its userAgent and ipAddress,
+// as the SDK stores them after calling the gateway. A context that already has details is returned as is.
+// The details are only used when the gatewayService option is not set, and do not cover inlist segments.
+func (g *Local) Enrich(context map[string]interface{}) map[string]interface{} {
+	if _, ok := context[ContextKey]; ok {
+		return context
+	}
+	userAgent, _ := context["userAgent"].(string)
+	ipAddress, _ := context["ipAddress"].(string)
+	details := g.Lookup(userAgent, ipAddress)
+	if details.Location == nil && details.UserAgent == nil {
+		return context
+	}
+
+	wingify := make(map[string]interface{}, 2)
+	if details.Location != nil {
+		wingify["location"] = details.Location
+	}
+	if details.UserAgent != nil {
+		wingify["userAgent"] = details.UserAgent
+	}
+	enriched := make(map[string]interface{}, len(context)+1)
+	for key, value := range context {
+		enriched[key] = value
+	}
+	enriched[ContextKey] = wingify
+	return enriched
+}
+
+// ServeHTTP serves the gateway endpoints used by the SDK. Event requests are accepted and discarded.
+func (g *Local) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := r.URL.Path
+	query := r.URL.Query()
+	switch {
+	case strings.HasSuffix(path, "/get-user-details"):
+		writeJSON(w, g.Lookup(query.Get("userAgent"), query.Get("ipAddress")))
+	case strings.HasSuffix(path, "/check-attribute"):
+		writeJSON(w, g.CheckAttribute(query.Get("listId"), query.Get("attribute")))
+	case strings.HasSuffix(path, "/server-side/v2-settings"), strings.HasSuffix(path, "/server-side/v2-pull"):
+		if g.settings == "" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(g.settings))
+	case strings.HasSuffix(path, "/events/t"), strings.HasSuffix(path, "/server-side/batch-events-v2"):
+		writeJSON(w, map[string]interface{}{})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// writeJSON writes a JSON response without a trailing newline: the SDK compares check-attribute responses to "true"
+func writeJSON(w http.ResponseWriter, value interface{}) {
+	body, err := json.Marshal(value)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(body)
+}
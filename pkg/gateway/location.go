@@ -0,0 +1,162 @@
+/**
+ * Copyright 2025 Wingify Software Pvt. Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gateway
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Location is the location of an IP address
+type Location struct {
+	Country string
+	Region  string
+	City    string
+}
+
+// Map returns the fields under the keys of the gateway response, omitting empty ones
+func (l Location) Map() map[string]string {
+	fields := map[string]string{"country": l.Country, "region": l.Region, "city": l.City}
+	for key, value := range fields {
+		if value == "" {
+			delete(fields, key)
+		}
+	}
+	return fields
+}
+
+// Locator finds the location of an IP address
+type Locator interface {
+	Locate(ip net.IP) (Location, bool)
+}
+
+// Table is a Locator over a list of networks
+type Table struct {
+	ranges []ipRange
+}
+
+// ipRange is a network of a Table, with addresses in their 16-byte form
+type ipRange struct {
+	first, last net.IP
+	location    Location
+}
+
+// OpenCSV loads a Table from a CSV file
+func OpenCSV(path string) (*Table, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return LoadCSV(f)
+}
+
+// LoadCSV loads a Table from CSV rows of network,country,region,city, where network is a CIDR
+// such as 81.2.69.0/24 and region and city may be omitted. A header row starting with "network"
+// and rows starting with # are skipped. Networks must not overlap.
+func LoadCSV(r io.Reader) (*Table, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.Comment = '#'
+	reader.TrimLeadingSpace = true
+
+	table := &Table{}
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if line == 1 && strings.EqualFold(record[0], "network") {
+			continue
+		}
+		if len(record) < 2 {
+			return nil, fmt.Errorf("gateway: line %d: expected network and country", line)
+		}
+
+		_, network, err := net.ParseCIDR(record[0])
+		if err != nil {
+			return nil, fmt.Errorf("gateway: line %d: %w", line, err)
+		}
+		location := Location{Country: record[1]}
+		if len(record) > 2 {
+			location.Region = record[2]
+		}
+		if len(record) > 3 {
+			location.City = record[3]
+		}
+		table.ranges = append(table.ranges, newRange(network, location))
+	}
+	sort.Slice(table.ranges, func(i, j int) bool {
+		return bytes.Compare(table.ranges[i].first, table.ranges[j].first) < 0
+	})
+	return table, nil
+}
+
+// Add adds a network to the table; it must not be called concurrently with Locate
+func (t *Table) Add(network *net.IPNet, location Location) {
+	r := newRange(network, location)
+	i := sort.Search(len(t.ranges), func(i int) bool {
+		return bytes.Compare(t.ranges[i].first, r.first) > 0
+	})
+	t.ranges = append(t.ranges, ipRange{})
+	copy(t.ranges[i+1:], t.ranges[i:])
+	t.ranges[i] = r
+}
+
+// Locate returns the location of the network containing ip
+func (t *Table) Locate(ip net.IP) (Location, bool) {
+	ip = ip.To16()
+	if ip == nil {
+		return Location{}, false
+	}
+	i := sort.Search(len(t.ranges), func(i int) bool {
+		return bytes.Compare(t.ranges[i].first, ip) > 0
+	})
+	if i == 0 || bytes.Compare(ip, t.ranges[i-1].last) > 0 {
+		return Location{}, false
+	}
+	return t.ranges[i-1].location, true
+}
+
+// Len returns the number of networks in the table
+func (t *Table) Len() int {
+	return len(t.ranges)
+}
+
+// newRange returns the first and last addresses of a network
+func newRange(network *net.IPNet, location Location) ipRange {
+	first := network.IP.To16()
+	mask := network.Mask
+	if len(mask) == net.IPv4len {
+		// align the mask with the 16-byte form of IPv4 addresses
+		mask = append(net.CIDRMask(96, 128)[:12], mask...)
+	}
+	last := make(net.IP, net.IPv6len)
+	for i := range first {
+		last[i] = first[i] | ^mask[i]
+	}
+	return ipRange{first: first, last: last, location: location}
+}
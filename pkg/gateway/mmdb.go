@@ -0,0 +1,378 @@
+/**
+ * Copyright 2025 Wingify Software Pvt. Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gateway
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"os"
+)
+
+// metadataMarker precedes the metadata at the end of a MaxMind DB file
+var metadataMarker = []byte("\xab\xcd\xefMaxMind.com")
+
+// errMalformed is returned for a MaxMind DB file that cannot be decoded
+var errMalformed = errors.New("gateway: malformed MaxMind DB file")
+
+// MMDB is a Locator over a MaxMind DB file, such as GeoLite2-City.mmdb or GeoIP2-Country.mmdb.
+// The file is read into memory.
+type MMDB struct {
+	// Language selects the names of regions and cities, "en" by default
+	Language string
+
+	buf        []byte
+	nodeCount  uint
+	recordSize uint
+	ipVersion  uint
+	dataStart  uint
+	ipv4Start  uint
+}
+
+// OpenMMDB reads a MaxMind DB file
+func OpenMMDB(path string) (*MMDB, error) {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return LoadMMDB(buf)
+}
+
+// LoadMMDB reads a MaxMind DB from its content
+func LoadMMDB(buf []byte) (*MMDB, error) {
+	marker := bytes.LastIndex(buf, metadataMarker)
+	if marker < 0 {
+		return nil, fmt.Errorf("gateway: MaxMind DB metadata not found")
+	}
+	metaStart := uint(marker + len(metadataMarker))
+	metadata, _, err := (&decoder{buf: buf[metaStart:]}).decode(0)
+	if err != nil {
+		return nil, err
+	}
+	meta, ok := metadata.(map[string]interface{})
+	if !ok {
+		return nil, errMalformed
+	}
+
+	db := &MMDB{
+		buf:        buf,
+		nodeCount:  uint(toUint(meta["node_count"])),
+		recordSize: uint(toUint(meta["record_size"])),
+		ipVersion:  uint(toUint(meta["ip_version"])),
+		Language:   "en",
+	}
+	if db.recordSize != 24 && db.recordSize != 28 && db.recordSize != 32 {
+		return nil, fmt.Errorf("gateway: unsupported MaxMind DB record size %d", db.recordSize)
+	}
+	treeSize := db.nodeCount * db.recordSize / 4
+	db.dataStart = treeSize + 16
+	if db.dataStart > metaStart {
+		return nil, errMalformed
+	}
+
+	// IPv4 addresses live under 96 zero bits of an IPv6 tree
+	if db.ipVersion == 6 {
+		for i := 0; i < 96 && db.ipv4Start < db.nodeCount; i++ {
+			db.ipv4Start = db.record(db.ipv4Start, 0)
+		}
+	}
+	return db, nil
+}
+
+// Locate returns the location of ip: the ISO code of the country, and the names of the
+// first subdivision and of the city in the configured language
+func (db *MMDB) Locate(ip net.IP) (Location, bool) {
+	value, ok := db.Lookup(ip)
+	if !ok {
+		return Location{}, false
+	}
+	record, ok := value.(map[string]interface{})
+	if !ok {
+		return Location{}, false
+	}
+
+	location := Location{Country: stringAt(record, "country", "iso_code")}
+	if subdivisions, ok := record["subdivisions"].([]interface{}); ok && len(subdivisions) > 0 {
+		if subdivision, ok := subdivisions[0].(map[string]interface{}); ok {
+			location.Region = stringAt(subdivision, "names", db.Language)
+		}
+	}
+	location.City = stringAt(record, "city", "names", db.Language)
+	return location, location != Location{}
+}
+
+// Lookup returns the decoded record of the network containing ip
+func (db *MMDB) Lookup(ip net.IP) (interface{}, bool) {
+	bits := ip.To4()
+	node := db.ipv4Start
+	if bits == nil {
+		if db.ipVersion == 4 {
+			return nil, false
+		}
+		bits = ip.To16()
+		node = 0
+	}
+	if bits == nil {
+		return nil, false
+	}
+
+	for i := 0; i < len(bits)*8 && node < db.nodeCount; i++ {
+		bit := uint(bits[i/8]>>(7-uint(i%8))) & 1
+		node = db.record(node, bit)
+	}
+	if node <= db.nodeCount {
+		return nil, false
+	}
+
+	offset := node - db.nodeCount - 16
+	d := &decoder{buf: db.buf[db.dataStart:]}
+	value, _, err := d.decode(offset)
+	if err != nil {
+		return nil, false
+	}
+	return value, true
+}
+
+// record returns the left (bit 0) or right (bit 1) record of a node of the search tree
+func (db *MMDB) record(node uint, bit uint) uint {
+	switch db.recordSize {
+	case 24:
+		b := db.buf[node*6+bit*3:]
+		return uint(b[0])<<16 | uint(b[1])<<8 | uint(b[2])
+	case 28:
+		b := db.buf[node*7:]
+		if bit == 0 {
+			return uint(b[3]&0xf0)<<20 | uint(b[0])<<16 | uint(b[1])<<8 | uint(b[2])
+		}
+		return uint(b[3]&0x0f)<<24 | uint(b[4])<<16 | uint(b[5])<<8 | uint(b[6])
+	default:
+		return uint(binary.BigEndian.Uint32(db.buf[node*8+bit*4:]))
+	}
+}
+
+// Types of the MaxMind DB data section
+const (
+	typeExtended = iota
+	typePointer
+	typeString
+	typeDouble
+	typeBytes
+	typeUint16
+	typeUint32
+	typeMap
+	typeInt32
+	typeUint64
+	typeUint128
+	typeArray
+	typeContainer
+	typeEndMarker
+	typeBool
+	typeFloat
+)
+
+// maxDepth bounds the nesting of decoded values so that a pointer cycle cannot exhaust the stack
+const maxDepth = 512
+
+// decoder decodes values of a MaxMind DB data section
+type decoder struct {
+	buf []byte
+}
+
+// decode decodes the value at offset and returns the offset following it
+func (d *decoder) decode(offset uint) (interface{}, uint, error) {
+	return d.decodeAt(offset, 0, false)
+}
+
+// decodeAt decodes the value at offset, nested depth levels deep; viaPointer is set for the target of a pointer
+func (d *decoder) decodeAt(offset uint, depth int, viaPointer bool) (interface{}, uint, error) {
+	if depth > maxDepth {
+		return nil, 0, errMalformed
+	}
+	typeNum, size, offset, err := d.control(offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if typeNum == typePointer {
+		// the format does not allow a pointer to point to another pointer
+		if viaPointer {
+			return nil, 0, errMalformed
+		}
+		pointer, next, err := d.pointer(size, offset)
+		if err != nil {
+			return nil, 0, err
+		}
+		value, _, err := d.decodeAt(pointer, depth+1, true)
+		return value, next, err
+	}
+
+	// every entry takes at least one byte, so a larger size cannot be valid and must not size an allocation
+	if (typeNum == typeMap || typeNum == typeArray) && size > uint(len(d.buf))-offset {
+		return nil, 0, errMalformed
+	}
+
+	switch typeNum {
+	case typeMap:
+		m := make(map[string]interface{}, size)
+		for i := uint(0); i < size; i++ {
+			key, next, err := d.decodeAt(offset, depth+1, false)
+			if err != nil {
+				return nil, 0, err
+			}
+			name, ok := key.(string)
+			if !ok {
+				return nil, 0, errMalformed
+			}
+			if m[name], offset, err = d.decodeAt(next, depth+1, false); err != nil {
+				return nil, 0, err
+			}
+		}
+		return m, offset, nil
+	case typeArray:
+		a := make([]interface{}, size)
+		for i := range a {
+			if a[i], offset, err = d.decodeAt(offset, depth+1, false); err != nil {
+				return nil, 0, err
+			}
+		}
+		return a, offset, nil
+	case typeBool:
+		return size != 0, offset, nil
+	}
+
+	end := offset + size
+	if end > uint(len(d.buf)) {
+		return nil, 0, errMalformed
+	}
+	b := d.buf[offset:end]
+	switch typeNum {
+	case typeString:
+		return string(b), end, nil
+	case typeBytes, typeUint128:
+		return append([]byte(nil), b...), end, nil
+	case typeDouble:
+		if size != 8 {
+			return nil, 0, errMalformed
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(b)), end, nil
+	case typeFloat:
+		if size != 4 {
+			return nil, 0, errMalformed
+		}
+		return float64(math.Float32frombits(binary.BigEndian.Uint32(b))), end, nil
+	case typeUint16, typeUint32, typeUint64:
+		var n uint64
+		for _, c := range b {
+			n = n<<8 | uint64(c)
+		}
+		return n, end, nil
+	case typeInt32:
+		var n uint32
+		for _, c := range b {
+			n = n<<8 | uint32(c)
+		}
+		return int64(int32(n)), end, nil
+	}
+	return nil, 0, errMalformed
+}
+
+// control decodes the control byte at offset and returns the type, size and offset of the payload
+func (d *decoder) control(offset uint) (int, uint, uint, error) {
+	if offset >= uint(len(d.buf)) {
+		return 0, 0, 0, errMalformed
+	}
+	ctrl := d.buf[offset]
+	offset++
+	typeNum := int(ctrl >> 5)
+	if typeNum == typeExtended {
+		if offset >= uint(len(d.buf)) {
+			return 0, 0, 0, errMalformed
+		}
+		typeNum = int(d.buf[offset]) + 7
+		offset++
+	}
+	if typeNum == typePointer {
+		return typeNum, uint(ctrl), offset, nil
+	}
+
+	size := uint(ctrl & 0x1f)
+	if size >= 29 {
+		n := size - 28
+		if offset+n > uint(len(d.buf)) {
+			return 0, 0, 0, errMalformed
+		}
+		var extra uint
+		for _, c := range d.buf[offset : offset+n] {
+			extra = extra<<8 | uint(c)
+		}
+		offset += n
+		switch size {
+		case 29:
+			size = 29 + extra
+		case 30:
+			size = 285 + extra
+		default:
+			size = 65821 + extra
+		}
+	}
+	return typeNum, size, offset, nil
+}
+
+// pointer decodes a pointer from its control byte and returns its target and the offset following it
+func (d *decoder) pointer(ctrl uint, offset uint) (uint, uint, error) {
+	n := (ctrl>>3)&0x3 + 1
+	if offset+n > uint(len(d.buf)) {
+		return 0, 0, errMalformed
+	}
+	var p uint
+	if n < 4 {
+		p = ctrl & 0x7
+	}
+	for _, c := range d.buf[offset : offset+n] {
+		p = p<<8 | uint(c)
+	}
+	switch n {
+	case 2:
+		p += 2048
+	case 3:
+		p += 526336
+	}
+	return p, offset + n, nil
+}
+
+// stringAt returns the string found by following keys through nested maps
+func stringAt(m map[string]interface{}, keys ...string) string {
+	var value interface{} = m
+	for _, key := range keys {
+		next, ok := value.(map[string]interface{})
+		if !ok {
+			return ""
+		}
+		value = next[key]
+	}
+	s, _ := value.(string)
+	return s
+}
+
+// toUint converts a decoded unsigned integer
+func toUint(value interface{}) uint64 {
+	n, _ := value.(uint64)
+	return n
+}
@@ -0,0 +1,196 @@
+/**
+ * Copyright 2025 Wingify Software Pvt. Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gateway
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Device types reported for a user agent
+const (
+	DeviceDesktop = "desktop"
+	DeviceMobile  = "mobile"
+	DeviceTablet  = "tablet"
+)
+
+// UserAgent is the browser, operating system and device parsed from a user agent string
+type UserAgent struct {
+	Browser        string
+	BrowserVersion string
+	OS             string
+	OSVersion      string
+	DeviceType     string
+	Device         string
+}
+
+// browserRule recognizes a browser by the token preceding its version
+type browserRule struct {
+	name   string
+	tokens []string
+}
+
+// browserRules are checked in order: browsers built on Chrome or Safari also carry their tokens
+var browserRules = []browserRule{
+	{"Edge", []string{"Edg/", "EdgA/", "EdgiOS/", "Edge/"}},
+	{"Opera", []string{"OPR/", "OPT/", "Opera/"}},
+	{"Samsung Internet", []string{"SamsungBrowser/"}},
+	{"Firefox", []string{"Firefox/", "FxiOS/"}},
+	{"Chrome", []string{"CriOS/", "Chrome/"}},
+	{"Internet Explorer", []string{"MSIE ", "rv:"}},
+	{"Safari", []string{"Version/"}},
+}
+
+var (
+	windowsPattern  = regexp.MustCompile(`Windows NT ([\d.]+)`)
+	iosPattern      = regexp.MustCompile(`(?:iPhone|CPU) OS ([\d_]+)`)
+	macPattern      = regexp.MustCompile(`Mac OS X ([\d_.]+)`)
+	androidPattern  = regexp.MustCompile(`Android ([\d.]+)(?:; ([^;)]+))?`)
+	chromeOSPattern = regexp.MustCompile(`CrOS \S+ ([\d.]+)`)
+)
+
+// windowsVersions maps Windows NT versions to product versions
+var windowsVersions = map[string]string{
+	"10.0": "10",
+	"6.3":  "8.1",
+	"6.2":  "8",
+	"6.1":  "7",
+	"6.0":  "Vista",
+	"5.1":  "XP",
+}
+
+// ParseUserAgent parses a user agent string; fields that cannot be recognized are left empty
+func ParseUserAgent(ua string) UserAgent {
+	var result UserAgent
+	if strings.TrimSpace(ua) == "" {
+		return result
+	}
+	result.Browser, result.BrowserVersion = parseBrowser(ua)
+	result.OS, result.OSVersion, result.Device = parseOS(ua)
+	result.DeviceType = parseDeviceType(ua)
+	return result
+}
+
+// Map returns the fields under the keys of the gateway response, omitting empty ones
+func (u UserAgent) Map() map[string]string {
+	fields := map[string]string{
+		"browser_string":  u.Browser,
+		"browser_version": u.BrowserVersion,
+		"os":              u.OS,
+		"os_version":      u.OSVersion,
+		"device_type":     u.DeviceType,
+		"device":          u.Device,
+	}
+	for key, value := range fields {
+		if value == "" {
+			delete(fields, key)
+		}
+	}
+	return fields
+}
+
+// parseBrowser returns the browser name and version
+func parseBrowser(ua string) (string, string) {
+	for _, rule := range browserRules {
+		for _, token := range rule.tokens {
+			i := strings.Index(ua, token)
+			if i < 0 {
+				continue
+			}
+			if token == "rv:" && !strings.Contains(ua, "Trident/") {
+				continue
+			}
+			if rule.name == "Safari" && !strings.Contains(ua, "Safari/") {
+				continue
+			}
+			return rule.name, readVersion(ua[i+len(token):])
+		}
+	}
+	return "", ""
+}
+
+// parseOS returns the operating system, its version and, for mobile devices, the device name
+func parseOS(ua string) (string, string, string) {
+	switch {
+	case strings.Contains(ua, "iPhone"), strings.Contains(ua, "iPad"), strings.Contains(ua, "iPod"):
+		device := "iPhone"
+		if strings.Contains(ua, "iPad") {
+			device = "iPad"
+		} else if strings.Contains(ua, "iPod") {
+			device = "iPod"
+		}
+		return "iOS", submatch(iosPattern, ua, 1, true), device
+	case strings.Contains(ua, "Android"):
+		device := strings.TrimSpace(submatch(androidPattern, ua, 2, false))
+		if i := strings.Index(device, " Build/"); i >= 0 {
+			device = device[:i]
+		}
+		if device == "K" {
+			// Chrome reduces the model of every Android device to "K"
+			device = ""
+		}
+		return "Android", submatch(androidPattern, ua, 1, false), device
+	case strings.Contains(ua, "Windows"):
+		version := submatch(windowsPattern, ua, 1, false)
+		if product, ok := windowsVersions[version]; ok {
+			version = product
+		}
+		return "Windows", version, ""
+	case strings.Contains(ua, "CrOS"):
+		return "Chrome OS", submatch(chromeOSPattern, ua, 1, false), ""
+	case strings.Contains(ua, "Mac OS X"), strings.Contains(ua, "Macintosh"):
+		return "Mac OS X", submatch(macPattern, ua, 1, true), ""
+	case strings.Contains(ua, "Linux"):
+		return "Linux", "", ""
+	}
+	return "", "", ""
+}
+
+// parseDeviceType returns whether the user agent is a desktop, mobile or tablet device
+func parseDeviceType(ua string) string {
+	switch {
+	case strings.Contains(ua, "iPad"), strings.Contains(ua, "Tablet"),
+		strings.Contains(ua, "Android") && !strings.Contains(ua, "Mobile"):
+		return DeviceTablet
+	case strings.Contains(ua, "Mobi"), strings.Contains(ua, "iPhone"), strings.Contains(ua, "iPod"):
+		return DeviceMobile
+	}
+	return DeviceDesktop
+}
+
+// readVersion reads the version at the start of s
+func readVersion(s string) string {
+	end := strings.IndexFunc(s, func(r rune) bool {
+		return !(r >= '0' && r <= '9' || r == '.')
+	})
+	if end < 0 {
+		end = len(s)
+	}
+	return strings.TrimSuffix(s[:end], ".")
+}
+
+// submatch returns a group of the first match of pattern, with underscores turned into dots when dotted
+func submatch(pattern *regexp.Regexp, s string, group int, dotted bool) string {
+	match := pattern.FindStringSubmatch(s)
+	if len(match) <= group {
+		return ""
+	}
+	if dotted {
+		return strings.ReplaceAll(match[group], "_", ".")
+	}
+	return match[group]
+}
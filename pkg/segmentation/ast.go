@@ -0,0 +1,255 @@
+/**
+ * Copyright 2025 Wingify Software Pvt. Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package segmentation
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Node is a node of a parsed segment DSL
+type Node interface {
+	// DSL returns the node as decoded segment DSL
+	DSL() map[string]interface{}
+
+	// String returns a one-line description of the node, without its children
+	String() string
+}
+
+// And matches when all its conditions match
+type And struct {
+	Conditions []Node
+}
+
+// Or matches when any of its conditions matches
+type Or struct {
+	Conditions []Node
+}
+
+// Not matches when its condition does not match
+type Not struct {
+	Condition Node
+}
+
+// CustomVariable matches a custom variable against an operand
+type CustomVariable struct {
+	Name    string
+	Operand Operand
+}
+
+// Operand is the operand of a CustomVariable, such as regex(^a+) or, for an equality, a plain value
+type Operand struct {
+	// Name is the operand, such as regex, or empty for an equality
+	Name string
+
+	// Argument is the text between the parentheses, or the value of an equality
+	Argument string
+}
+
+// User matches a list of user ids
+type User struct {
+	IDs []string
+}
+
+// Other is a condition the SDK evaluates with the user context, such as a location or a browser
+type Other struct {
+	Operator string
+	Value    interface{}
+}
+
+// Parse parses a segment DSL, given as a JSON string or as decoded JSON. Unlike the evaluation, which
+// treats malformed conditions as not matching, Parse reports them.
+func Parse(dsl interface{}) (Node, error) {
+	decoded, err := decodeDSL(dsl)
+	if err != nil {
+		return nil, err
+	}
+	return parseNode(decoded, "$")
+}
+
+// parseNode parses the node at path
+func parseNode(dsl map[string]interface{}, path string) (Node, error) {
+	operator, value, ok := single(dsl)
+	if !ok {
+		return nil, fmt.Errorf("segmentation: %s has %d operators, want one", path, len(dsl))
+	}
+	path += "." + operator
+
+	switch operator {
+	case OperatorAnd, OperatorOr:
+		children, ok := value.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("segmentation: %s is not a list", path)
+		}
+		conditions := make([]Node, len(children))
+		for i, child := range children {
+			childNode, ok := child.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("segmentation: %s[%d] is not an object", path, i)
+			}
+			condition, err := parseNode(childNode, fmt.Sprintf("%s[%d]", path, i))
+			if err != nil {
+				return nil, err
+			}
+			conditions[i] = condition
+		}
+		if operator == OperatorAnd {
+			return &And{Conditions: conditions}, nil
+		}
+		return &Or{Conditions: conditions}, nil
+	case OperatorNot:
+		child, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("segmentation: %s is not an object", path)
+		}
+		condition, err := parseNode(child, path)
+		if err != nil {
+			return nil, err
+		}
+		return &Not{Condition: condition}, nil
+	case OperatorCustomVariable:
+		condition, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("segmentation: %s is not an object", path)
+		}
+		name, operand, ok := single(condition)
+		if !ok {
+			return nil, fmt.Errorf("segmentation: %s has %d variables, want one", path, len(condition))
+		}
+		return &CustomVariable{Name: name, Operand: ParseOperand(fmt.Sprint(operand))}, nil
+	case OperatorUser:
+		ids, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("segmentation: %s is not a string", path)
+		}
+		user := &User{}
+		for _, id := range strings.Split(ids, ",") {
+			user.IDs = append(user.IDs, strings.TrimSpace(strings.ReplaceAll(id, "\"", "")))
+		}
+		return user, nil
+	}
+	return &Other{Operator: operator, Value: value}, nil
+}
+
+// ParseOperand splits an operand such as regex(^a+) into its name and argument
+func ParseOperand(operand string) Operand {
+	if match := operandPattern.FindStringSubmatch(operand); match != nil {
+		return Operand{Name: match[1], Argument: match[2]}
+	}
+	return Operand{Argument: operand}
+}
+
+// String returns the operand as written in the DSL
+func (o Operand) String() string {
+	if o.Name == "" {
+		return o.Argument
+	}
+	return o.Name + "(" + o.Argument + ")"
+}
+
+// DSL returns the node as decoded segment DSL
+func (n *And) DSL() map[string]interface{} {
+	return map[string]interface{}{OperatorAnd: dslList(n.Conditions)}
+}
+
+// DSL returns the node as decoded segment DSL
+func (n *Or) DSL() map[string]interface{} {
+	return map[string]interface{}{OperatorOr: dslList(n.Conditions)}
+}
+
+// DSL returns the node as decoded segment DSL
+func (n *Not) DSL() map[string]interface{} {
+	return map[string]interface{}{OperatorNot: n.Condition.DSL()}
+}
+
+// DSL returns the node as decoded segment DSL
+func (n *CustomVariable) DSL() map[string]interface{} {
+	return map[string]interface{}{OperatorCustomVariable: map[string]interface{}{n.Name: n.Operand.String()}}
+}
+
+// DSL returns the node as decoded segment DSL
+func (n *User) DSL() map[string]interface{} {
+	return map[string]interface{}{OperatorUser: strings.Join(n.IDs, ",")}
+}
+
+// DSL returns the node as decoded segment DSL
+func (n *Other) DSL() map[string]interface{} {
+	return map[string]interface{}{n.Operator: n.Value}
+}
+
+func (n *And) String() string { return OperatorAnd }
+
+func (n *Or) String() string { return OperatorOr }
+
+func (n *Not) String() string { return OperatorNot }
+
+func (n *CustomVariable) String() string {
+	return OperatorCustomVariable + " " + n.Name + ": " + n.Operand.String()
+}
+
+func (n *User) String() string {
+	return OperatorUser + ": " + strings.Join(n.IDs, ", ")
+}
+
+func (n *Other) String() string {
+	if s, ok := n.Value.(string); ok {
+		return n.Operator + ": " + s
+	}
+	encoded, _ := json.Marshal(n.Value)
+	return n.Operator + ": " + string(encoded)
+}
+
+// Format prints a node as an indented tree, one condition per line. The output is canonical:
+// DSL differing only in whitespace or number formatting prints the same.
+func Format(n Node) string {
+	var b strings.Builder
+	format(&b, n, 0)
+	return b.String()
+}
+
+// format writes a node and its children
+func format(b *strings.Builder, n Node, depth int) {
+	b.WriteString(strings.Repeat("  ", depth))
+	b.WriteString(n.String())
+	b.WriteByte('\n')
+	for _, child := range Children(n) {
+		format(b, child, depth+1)
+	}
+}
+
+// Children returns the conditions of an And, an Or or a Not
+func Children(n Node) []Node {
+	switch v := n.(type) {
+	case *And:
+		return v.Conditions
+	case *Or:
+		return v.Conditions
+	case *Not:
+		return []Node{v.Condition}
+	}
+	return nil
+}
+
+// dslList returns nodes as a list of decoded segment DSL
+func dslList(nodes []Node) []interface{} {
+	list := make([]interface{}, len(nodes))
+	for i, n := range nodes {
+		list[i] = n.DSL()
+	}
+	return list
+}
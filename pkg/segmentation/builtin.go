@@ -0,0 +1,295 @@
+/**
+ * Copyright 2025 Wingify Software Pvt. Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package segmentation
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Operand patterns of the SDK, matched in this order; an operand matching none is an equality
+var (
+	lowerPattern    = regexp.MustCompile(`^lower\((.*)\)`)
+	wildcardPattern = regexp.MustCompile(`^wildcard\((.*)\)`)
+	regexPattern    = regexp.MustCompile(`^regex\((.*)\)`)
+	gtPattern       = regexp.MustCompile(`^gt\(([\d.]+)\)`)
+	gtePattern      = regexp.MustCompile(`^gte\(([\d.]+)\)`)
+	ltPattern       = regexp.MustCompile(`^lt\(([\d.]+)\)`)
+	ltePattern      = regexp.MustCompile(`^lte\(([\d.]+)\)`)
+)
+
+// builtinKind is the kind of an operand of the SDK
+type builtinKind int
+
+const (
+	kindEqual builtinKind = iota
+	kindLower
+	kindContains
+	kindSuffix
+	kindPrefix
+	kindRegex
+	kindGreaterThan
+	kindGreaterThanEqualTo
+	kindLessThan
+	kindLessThanEqualTo
+)
+
+// builtinOperand is an operand of the SDK, parsed once. It reproduces the SDK evaluation, including
+// its quirks: the operand is compared in its canonical number form when the custom variable is a
+// number, and wildcards without stars are regular expressions.
+type builtinOperand struct {
+	kind    builtinKind
+	plain   operandForm
+	numeric *operandForm
+}
+
+// operandForm is the operand compared with a custom variable
+type operandForm struct {
+	value    string
+	regex    *regexp.Regexp
+	regexErr error
+	version  bool
+	number   float64
+	numberOK bool
+}
+
+// compileBuiltin parses an operand of the SDK
+func compileBuiltin(operand string, c Coercion, limits RegexLimits) *builtinOperand {
+	kind, value := kindEqual, operand
+	switch {
+	case lowerPattern.MatchString(operand):
+		kind, value = kindLower, submatch(lowerPattern, operand)
+	case wildcardPattern.MatchString(operand):
+		value = submatch(wildcardPattern, operand)
+		startingStar, endingStar := strings.HasPrefix(value, "*"), strings.HasSuffix(value, "*")
+		switch {
+		case startingStar && endingStar:
+			kind = kindContains
+		case startingStar:
+			kind = kindSuffix
+		case endingStar:
+			kind = kindPrefix
+		default:
+			kind = kindRegex
+		}
+		value = strings.TrimSuffix(strings.TrimPrefix(value, "*"), "*")
+	case regexPattern.MatchString(operand):
+		kind, value = kindRegex, submatch(regexPattern, operand)
+	case gtPattern.MatchString(operand):
+		kind, value = kindGreaterThan, submatch(gtPattern, operand)
+	case gtePattern.MatchString(operand):
+		kind, value = kindGreaterThanEqualTo, submatch(gtePattern, operand)
+	case ltPattern.MatchString(operand):
+		kind, value = kindLessThan, submatch(ltPattern, operand)
+	case ltePattern.MatchString(operand):
+		kind, value = kindLessThanEqualTo, submatch(ltePattern, operand)
+	}
+
+	plain := strings.TrimSpace(strings.ReplaceAll(value, "\"", ""))
+	if boolean, ok := c.boolean(plain); ok && kind == kindEqual {
+		plain = strconv.FormatBool(boolean)
+	}
+	b := &builtinOperand{kind: kind, plain: newOperandForm(kind, plain, limits)}
+	if f, err := strconv.ParseFloat(value, 64); err == nil {
+		numeric := newOperandForm(kind, canonicalNumber(f), limits)
+		b.numeric = &numeric
+	}
+	return b
+}
+
+// newOperandForm precomputes what comparing with value needs
+func newOperandForm(kind builtinKind, value string, limits RegexLimits) operandForm {
+	form := operandForm{value: value, version: isVersionString(value)}
+	form.number, form.numberOK = parseNumber(value)
+	if kind == kindRegex {
+		form.regex, form.regexErr = limits.compile(value)
+	}
+	return form
+}
+
+// regexErr returns why the regular expression of the operand does not compile, if it is one
+func (b *builtinOperand) regexErr() error {
+	if b.kind != kindRegex {
+		return nil
+	}
+	return b.plain.regexErr
+}
+
+// match returns whether the value of a custom variable satisfies the operand
+func (b *builtinOperand) match(c Coercion, r *regexRun, actual interface{}) bool {
+	v := c.convert(actual)
+	if c.Strict {
+		return b.matchStrict(r, v)
+	}
+	byValue := v.kind == valueNumber || !c.NumbersAsText
+	tag, form := v.text, &b.plain
+	if b.numeric != nil && byValue && numericChars(tag) {
+		if f, err := strconv.ParseFloat(tag, 64); err == nil {
+			form, tag = b.numeric, canonicalNumber(f)
+		}
+	}
+
+	switch b.kind {
+	case kindGreaterThan, kindGreaterThanEqualTo, kindLessThan, kindLessThanEqualTo:
+		if form.version && isVersionString(tag) {
+			return b.accept(compareVersions(tag, form.value))
+		}
+		number, ok := parseNumber(tag)
+		return ok && b.compare(form, number)
+	case kindEqual:
+		if form.version && byValue && isVersionString(tag) {
+			return compareVersions(tag, form.value) == 0
+		}
+		return tag == form.value
+	}
+	return b.matchText(r, form, tag)
+}
+
+// matchStrict matches a custom variable without converting between strings and numbers
+func (b *builtinOperand) matchStrict(r *regexRun, v coerced) bool {
+	switch b.kind {
+	case kindGreaterThan, kindGreaterThanEqualTo, kindLessThan, kindLessThanEqualTo:
+		return v.kind == valueNumber && v.numberOK && b.compare(&b.plain, v.number)
+	case kindEqual:
+		switch v.kind {
+		case valueNumber:
+			return b.numeric != nil && v.numberOK && v.number == b.numeric.number
+		case valueString, valueBool:
+			return v.text == b.plain.value
+		}
+		return false
+	}
+	return v.kind == valueString && b.matchText(r, &b.plain, v.text)
+}
+
+// matchText matches the text operands: lower, wildcard and regex
+func (b *builtinOperand) matchText(r *regexRun, form *operandForm, tag string) bool {
+	switch b.kind {
+	case kindLower:
+		return strings.EqualFold(form.value, tag)
+	case kindContains:
+		return strings.Contains(tag, form.value)
+	case kindSuffix:
+		return strings.HasSuffix(tag, form.value)
+	case kindPrefix:
+		return strings.HasPrefix(tag, form.value)
+	case kindRegex:
+		return r.match(form.regex, tag)
+	}
+	return false
+}
+
+// compare compares a number with the operand of gt, gte, lt or lte
+func (b *builtinOperand) compare(form *operandForm, number float64) bool {
+	if !form.numberOK || math.IsNaN(number) || math.IsNaN(form.number) {
+		return false
+	}
+	return b.accept(compareFloat(number, form.number))
+}
+
+// accept returns whether a comparison with the operand satisfies gt, gte, lt or lte
+func (b *builtinOperand) accept(comparison int) bool {
+	switch b.kind {
+	case kindGreaterThan:
+		return comparison > 0
+	case kindGreaterThanEqualTo:
+		return comparison >= 0
+	case kindLessThan:
+		return comparison < 0
+	}
+	return comparison <= 0
+}
+
+// canonicalNumber formats a number as the SDK does: integers without decimals, others in their shortest form
+func canonicalNumber(f float64) string {
+	if f == float64(int(f)) {
+		return strconv.Itoa(int(f))
+	}
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+// numericChars returns whether s holds only digits and dots
+func numericChars(s string) bool {
+	for _, c := range s {
+		if (c < '0' || c > '9') && c != '.' {
+			return false
+		}
+	}
+	return true
+}
+
+// isVersionString returns whether s is digits separated by single dots, such as 2.10.1
+func isVersionString(s string) bool {
+	if s == "" || s[0] == '.' || s[len(s)-1] == '.' || strings.Contains(s, "..") {
+		return false
+	}
+	return numericChars(s)
+}
+
+// compareVersions compares dotted versions component by component, missing components being zero
+func compareVersions(a, b string) int {
+	partsA, partsB := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(partsA) || i < len(partsB); i++ {
+		x, y := versionComponent(partsA, i), versionComponent(partsB, i)
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// versionComponent returns a component of a version, zero when missing or out of range
+func versionComponent(parts []string, i int) int {
+	if i >= len(parts) {
+		return 0
+	}
+	n, err := strconv.Atoi(parts[i])
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+// parseNumber parses a number
+func parseNumber(s string) (float64, bool) {
+	f, err := strconv.ParseFloat(s, 64)
+	return f, err == nil
+}
+
+// compareFloat compares two numbers
+func compareFloat(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// submatch returns the first group of a pattern in s
+func submatch(pattern *regexp.Regexp, s string) string {
+	if match := pattern.FindStringSubmatch(s); len(match) >= 2 {
+		return match[1]
+	}
+	return s
+}
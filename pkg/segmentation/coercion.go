@@ -0,0 +1,146 @@
+/**
+ * Copyright 2025 Wingify Software Pvt. Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package segmentation
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Coercion decides how the values of custom variables are converted before operands compare them.
+// The zero value converts them as the SDK does:
+//   - strings are trimmed of surrounding spaces
+//   - strings holding only digits and dots equal numbers of the same value, so "123.4560000" equals
+//     123.456 and "5.0" equals 5; dotted versions such as "1.0" and "1.0.0" are equal too
+//   - booleans are the text true or false, so true equals "true"
+//   - numbers match text operands, such as wildcard(*5), in their canonical form
+//
+// The same rules apply to extended operands: semver_ and date operands accept numbers as text, and
+// in, not_in and inlist match numbers and numeric strings of the same value.
+type Coercion struct {
+	// Strict disables implicit conversion between strings and numbers. Text operands, lower, wildcard
+	// and regex, only match strings; gt, gte, lt and lte only match numbers; eq compares strings as text,
+	// numbers by value and booleans with true or false. semver_ operands only accept strings, date
+	// operands read numbers as timestamps but not numeric strings, and sets only match members of the
+	// same type.
+	Strict bool
+
+	// NoTrim keeps the spaces around string values
+	NoTrim bool
+
+	// NumbersAsText compares strings holding numbers as written rather than in canonical form: "5.0" no
+	// longer equals 5, "5" or the version 5.0.0, and gte(1.2) holds for the version "1.10". Number
+	// values are still compared by value.
+	NumbersAsText bool
+
+	// ParseBooleans reads strings such as "TRUE" and " False" as booleans, and eq operands such as
+	// True as true or false. Extended operands see them as the strings true and false.
+	ParseBooleans bool
+}
+
+// valueKind is the type of a custom variable after coercion
+type valueKind int
+
+const (
+	valueNone valueKind = iota
+	valueString
+	valueNumber
+	valueBool
+	valueOther
+)
+
+// coerced is a custom variable converted for the operands of the SDK
+type coerced struct {
+	text     string
+	kind     valueKind
+	number   float64
+	numberOK bool
+}
+
+// convert returns the text and type of a custom variable, the text being what the SDK compares
+func (c Coercion) convert(actual interface{}) coerced {
+	switch v := actual.(type) {
+	case nil:
+		return coerced{kind: valueNone}
+	case bool:
+		return coerced{text: strconv.FormatBool(v), kind: valueBool}
+	case string:
+		if b, ok := c.boolean(v); ok {
+			return coerced{text: strconv.FormatBool(b), kind: valueBool}
+		}
+		return coerced{text: c.trim(v), kind: valueString}
+	case float64:
+		return coerced{text: canonicalNumber(v), kind: valueNumber, number: v, numberOK: true}
+	case float32, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, json.Number:
+		text := fmt.Sprint(v)
+		number, ok := parseNumber(text)
+		return coerced{text: text, kind: valueNumber, number: number, numberOK: ok}
+	}
+	return coerced{text: strings.TrimSpace(fmt.Sprint(actual)), kind: valueOther}
+}
+
+// value converts a custom variable for the extended operands: strings are trimmed and, with
+// ParseBooleans, booleans are written true or false
+func (c Coercion) value(actual interface{}) interface{} {
+	s, ok := actual.(string)
+	if !ok {
+		return actual
+	}
+	if b, ok := c.boolean(s); ok {
+		return strconv.FormatBool(b)
+	}
+	return c.trim(s)
+}
+
+// wrap applies the coercion to the values matched by an extended operand
+func (c Coercion) wrap(match matcher) matcher {
+	return func(actual interface{}) (bool, error) {
+		return match(c.value(actual))
+	}
+}
+
+// stringValue returns the text of a string or, unless strict, number custom variable
+func (c Coercion) stringValue(value interface{}) (string, bool) {
+	if _, ok := value.(string); !ok && c.Strict {
+		return "", false
+	}
+	return stringValue(value)
+}
+
+// boolean parses a string as a boolean when ParseBooleans is set
+func (c Coercion) boolean(s string) (bool, bool) {
+	if !c.ParseBooleans {
+		return false, false
+	}
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "true":
+		return true, true
+	case "false":
+		return false, true
+	}
+	return false, false
+}
+
+// trim applies the trimming policy to a string
+func (c Coercion) trim(s string) string {
+	if c.NoTrim {
+		return s
+	}
+	return strings.TrimSpace(s)
+}
@@ -0,0 +1,340 @@
+/**
+ * Copyright 2025 Wingify Software Pvt. Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package segmentation
+
+import (
+	"fmt"
+	"strings"
+)
+
+// OperatorUser targets users by id
+const OperatorUser = "user"
+
+// userIDVariable is the custom variable the SDK sets to the id of the user for user conditions
+const userIDVariable = "_wingifyUserId"
+
+// Segment is a segment DSL compiled once: regular expressions are compiled and numbers parsed,
+// so that evaluating it neither decodes JSON nor parses operands. A Segment is safe for concurrent use.
+type Segment struct {
+	evaluator *Evaluator
+	root      node
+	variables []string
+}
+
+// evaluation is the state of one evaluation of a segment
+type evaluation struct {
+	*Evaluator
+	regex regexRun
+}
+
+// evaluation starts an evaluation
+func (e *Evaluator) evaluation() *evaluation {
+	return &evaluation{Evaluator: e, regex: regexRun{limits: e.regex}}
+}
+
+// node is a node of a compiled segment
+type node interface {
+	eval(e *evaluation, customVariables map[string]interface{}) Truth
+}
+
+type (
+	// andNode matches when all its children match
+	andNode []node
+
+	// orNode matches when any of its children matches
+	orNode []node
+
+	// notNode inverts its child
+	notNode struct{ child node }
+
+	// constNode is a condition the SDK never or always matches, such as an and without a list
+	constNode bool
+
+	// extendedNode is a condition on an operand of the Evaluator
+	extendedNode struct {
+		key   string
+		match matcher
+	}
+
+	// builtinNode is a condition on an operand of the SDK
+	builtinNode struct {
+		key     string
+		operand *builtinOperand
+	}
+
+	// userNode matches a list of user ids
+	userNode []string
+
+	// sdkNode is evaluated by the SDK, such as location and user agent conditions, which it groups together
+	sdkNode map[string]interface{}
+)
+
+// Compile compiles a segment DSL, given as a JSON string or as decoded JSON
+func (e *Evaluator) Compile(dsl interface{}) (*Segment, error) {
+	decoded, err := decodeDSL(dsl)
+	if err != nil {
+		return nil, err
+	}
+	variables := make(map[string]bool)
+	collectVariables(decoded, variables)
+	delete(variables, CurrentTime)
+	return &Segment{evaluator: e, root: e.compileNode(decoded), variables: sortedKeys(variables)}, nil
+}
+
+// Validate returns whether custom variables satisfy the segment
+func (s *Segment) Validate(customVariables map[string]interface{}) bool {
+	truth, err := s.Evaluate(customVariables)
+	return err == nil && truth == True
+}
+
+// Evaluate returns the three-valued result of the segment, see Evaluator.Evaluate
+func (s *Segment) Evaluate(customVariables map[string]interface{}) (Truth, error) {
+	if s.evaluator.strict {
+		if missing := s.Missing(customVariables); len(missing) > 0 {
+			return Unknown, missingError(missing)
+		}
+	}
+	return s.root.eval(s.evaluator.evaluation(), customVariables), nil
+}
+
+// Variables returns the names of the custom variables the segment uses, sorted
+func (s *Segment) Variables() []string {
+	return append([]string(nil), s.variables...)
+}
+
+// Missing returns the custom variables the segment uses that are not set, sorted
+func (s *Segment) Missing(customVariables map[string]interface{}) []string {
+	var missing []string
+	for _, name := range s.variables {
+		if _, ok := customVariables[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	return missing
+}
+
+// collectVariables adds the names of the custom variables of a decoded DSL to variables
+func collectVariables(dsl interface{}, variables map[string]bool) {
+	switch n := dsl.(type) {
+	case map[string]interface{}:
+		for operator, value := range n {
+			if condition, ok := value.(map[string]interface{}); ok && operator == OperatorCustomVariable {
+				for name := range condition {
+					variables[name] = true
+				}
+				continue
+			}
+			collectVariables(value, variables)
+		}
+	case []interface{}:
+		for _, child := range n {
+			collectVariables(child, variables)
+		}
+	}
+}
+
+// compileNode compiles a node of a segment DSL
+func (e *Evaluator) compileNode(dsl map[string]interface{}) node {
+	operator, value, ok := single(dsl)
+	if !ok {
+		if e.extended(dsl) {
+			return constNode(false)
+		}
+		return sdkNode(dsl)
+	}
+
+	switch operator {
+	case OperatorAnd, OperatorOr:
+		children, ok := value.([]interface{})
+		if !ok {
+			return constNode(false)
+		}
+		return e.compileList(dsl, operator, children)
+	case OperatorNot:
+		child, ok := value.(map[string]interface{})
+		if !ok {
+			return constNode(false)
+		}
+		return notNode{child: e.compileNode(child)}
+	case OperatorCustomVariable:
+		condition, ok := value.(map[string]interface{})
+		if !ok {
+			return constNode(false)
+		}
+		key, operand, ok := single(condition)
+		if !ok {
+			return sdkNode(dsl)
+		}
+		if match, ok := e.operand(operand); ok {
+			return extendedNode{key: key, match: match}
+		}
+		text := fmt.Sprint(operand)
+		if strings.Contains(text, "inlist") {
+			// attribute lists are checked through the gateway service
+			return sdkNode(dsl)
+		}
+		return builtinNode{key: key, operand: compileBuiltin(text, e.coercion, e.regex)}
+	case OperatorUser:
+		ids, ok := value.(string)
+		if !ok {
+			return constNode(false)
+		}
+		var users userNode
+		for _, id := range strings.Split(ids, ",") {
+			users = append(users, strings.TrimSpace(strings.ReplaceAll(id, "\"", "")))
+		}
+		return users
+	}
+	if e.extended(dsl) {
+		return constNode(false)
+	}
+	return sdkNode(dsl)
+}
+
+// compileList compiles an and or an or. The SDK evaluates some conditions of a list together, such as
+// locations, so a list with such conditions is left to the SDK, but for the conditions on extended operands.
+func (e *Evaluator) compileList(dsl map[string]interface{}, operator string, children []interface{}) node {
+	native := true
+	for _, child := range children {
+		if !e.native(child) {
+			native = false
+			break
+		}
+	}
+	if !native && !e.extended(children) {
+		return sdkNode(dsl)
+	}
+
+	var compiled []node
+	var rest []interface{}
+	for _, child := range children {
+		childNode, _ := child.(map[string]interface{})
+		if native || e.extended(childNode) {
+			compiled = append(compiled, e.compileNode(childNode))
+		} else {
+			rest = append(rest, child)
+		}
+	}
+	if len(rest) > 0 {
+		compiled = append(compiled, sdkNode{operator: rest})
+	}
+	if operator == OperatorAnd {
+		return andNode(compiled)
+	}
+	return orNode(compiled)
+}
+
+// native returns whether a child of a list is evaluated the same way on its own as within the list
+func (e *Evaluator) native(child interface{}) bool {
+	childNode, ok := child.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	operator, _, ok := single(childNode)
+	if !ok {
+		return false
+	}
+	switch operator {
+	case OperatorAnd, OperatorOr, OperatorNot, OperatorCustomVariable, OperatorUser:
+		return true
+	}
+	return false
+}
+
+func (n andNode) eval(e *evaluation, customVariables map[string]interface{}) Truth {
+	result := True
+	for _, child := range n {
+		switch child.eval(e, customVariables) {
+		case False:
+			return False
+		case Unknown:
+			result = Unknown
+		}
+	}
+	return result
+}
+
+func (n orNode) eval(e *evaluation, customVariables map[string]interface{}) Truth {
+	result := False
+	for _, child := range n {
+		switch child.eval(e, customVariables) {
+		case True:
+			return True
+		case Unknown:
+			result = Unknown
+		}
+	}
+	return result
+}
+
+func (n notNode) eval(e *evaluation, customVariables map[string]interface{}) Truth {
+	switch n.child.eval(e, customVariables) {
+	case True:
+		return False
+	case False:
+		return True
+	}
+	return Unknown
+}
+
+func (n constNode) eval(*evaluation, map[string]interface{}) Truth {
+	return truthOf(bool(n))
+}
+
+func (n extendedNode) eval(e *evaluation, customVariables map[string]interface{}) Truth {
+	actual, ok := e.variable(customVariables, n.key)
+	if !ok {
+		return e.unknown.leaf()
+	}
+	result, err := n.match(actual)
+	return truthOf(err == nil && result)
+}
+
+func (n builtinNode) eval(e *evaluation, customVariables map[string]interface{}) Truth {
+	actual, ok := e.variable(customVariables, n.key)
+	if !ok {
+		return e.unknown.leaf()
+	}
+	return truthOf(n.operand.match(e.coercion, &e.regex, actual))
+}
+
+func (n userNode) eval(e *evaluation, customVariables map[string]interface{}) Truth {
+	userID, ok := customVariables[userIDVariable]
+	if !ok {
+		return e.unknown.leaf()
+	}
+	id := fmt.Sprint(userID)
+	for _, user := range n {
+		if user == id {
+			return True
+		}
+	}
+	return False
+}
+
+func (n sdkNode) eval(e *evaluation, customVariables map[string]interface{}) Truth {
+	return truthOf(e.sdkValidate(n, e.withCurrentTime(customVariables)))
+}
+
+// variable returns the value of a custom variable, CurrentTime coming from the clock
+func (e *Evaluator) variable(customVariables map[string]interface{}, key string) (interface{}, bool) {
+	if key == CurrentTime {
+		return e.clock.Now().Unix(), true
+	}
+	value, ok := customVariables[key]
+	return value, ok
+}
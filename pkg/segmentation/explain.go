@@ -0,0 +1,124 @@
+/**
+ * Copyright 2025 Wingify Software Pvt. Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package segmentation
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Explanation is the evaluation of a node of a segment and the reason for its result
+type Explanation struct {
+	Node Node
+
+	// Result is whether the node matched; Truth tells a mismatch from an unknown result
+	Result bool
+	Truth  Truth
+
+	Reason   string
+	Children []*Explanation
+}
+
+// Explain evaluates a segment DSL like Validate and explains the result of every node. Every condition
+// is evaluated, including those Validate skips once the result is known.
+func (e *Evaluator) Explain(dsl interface{}, customVariables map[string]interface{}) (*Explanation, error) {
+	root, err := Parse(dsl)
+	if err != nil {
+		return nil, err
+	}
+	return e.evaluation().explain(root, customVariables), nil
+}
+
+// explain evaluates a node and its children
+func (e *evaluation) explain(n Node, customVariables map[string]interface{}) *Explanation {
+	truth := e.compileNode(n.DSL()).eval(e, customVariables)
+	x := &Explanation{Node: n, Result: truth == True, Truth: truth}
+	for _, child := range Children(n) {
+		x.Children = append(x.Children, e.explain(child, customVariables))
+	}
+
+	matched := 0
+	for _, child := range x.Children {
+		if child.Result {
+			matched++
+		}
+	}
+	switch v := n.(type) {
+	case *And, *Or:
+		x.Reason = fmt.Sprintf("%s: %d of %d conditions matched", n, matched, len(x.Children))
+	case *Not:
+		switch x.Children[0].Truth {
+		case True:
+			x.Reason = "not: its condition matched"
+		case Unknown:
+			x.Reason = "not: its condition is unknown"
+		default:
+			x.Reason = "not: its condition did not match"
+		}
+	case *CustomVariable:
+		x.Reason = e.explainVariable(v, customVariables, x.Result)
+	case *User:
+		userID, ok := customVariables[userIDVariable]
+		switch {
+		case !ok:
+			x.Reason = fmt.Sprintf("%s: the user id is not set", n)
+		case x.Result:
+			x.Reason = fmt.Sprintf("%s matched '%v'", n, userID)
+		default:
+			x.Reason = fmt.Sprintf("%s did not match '%v'", n, userID)
+		}
+	default:
+		x.Reason = fmt.Sprintf("%s: evaluated by the SDK with the user context", n)
+	}
+	return x
+}
+
+// explainVariable returns the reason for the result of a custom variable condition
+func (e *Evaluator) explainVariable(v *CustomVariable, customVariables map[string]interface{}, result bool) string {
+	actual, ok := e.variable(customVariables, v.Name)
+	if !ok {
+		return fmt.Sprintf("%s %s is not set", OperatorCustomVariable, v.Name)
+	}
+	if match, ok := e.operand(v.Operand.String()); ok {
+		if _, err := match(actual); err != nil {
+			return fmt.Sprintf("%s failed on '%v': %v", v, actual, err)
+		}
+	}
+	if result {
+		return fmt.Sprintf("%s matched '%v'", v, actual)
+	}
+	return fmt.Sprintf("%s did not match '%v'", v, actual)
+}
+
+// String prints the explanation as an indented tree, each line starting with the result of its node
+func (x *Explanation) String() string {
+	var b strings.Builder
+	x.format(&b, 0)
+	return b.String()
+}
+
+// format writes an explanation and its children
+func (x *Explanation) format(b *strings.Builder, depth int) {
+	result := x.Truth.String()
+	if x.Truth == True {
+		result += " "
+	}
+	fmt.Fprintf(b, "%s%s %s\n", strings.Repeat("  ", depth), result, x.Reason)
+	for _, child := range x.Children {
+		child.format(b, depth+1)
+	}
+}
@@ -0,0 +1,116 @@
+/**
+ * Copyright 2025 Wingify Software Pvt. Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package segmentation
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sync"
+)
+
+// Errors of RegisterOperand
+var (
+	ErrInvalidOperand = errors.New("segmentation: invalid operand")
+	ErrOperandExists  = errors.New("segmentation: operand already exists")
+)
+
+// sdkOperands are the operands of the SDK, which custom operands cannot replace
+var sdkOperands = []string{"lower", "wildcard", "regex", "gt", "gte", "lt", "lte", "inlist"}
+
+// operandName is the syntax of operand names
+var operandName = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]OperandFunc)
+)
+
+// RegisterOperand adds a custom operand to the Evaluators created afterwards, so register operands
+// before calling New, typically in an init function. Segments reference it as name(argument) in a
+// custom_variable condition, such as {"custom_variable":{"tier":"tier_at_least(gold)"}}; fn receives
+// the argument and the value of the custom variable, converted by the Coercion of the Evaluator.
+// An error or a panic of fn makes the condition not match.
+//
+// The SDK itself does not know custom operands and compares them as plain values. They take effect
+// in GetFlag only for settings and contexts passed through Evaluator.Prepare.
+//
+// Names are lowercase letters, digits and underscores, starting with a letter. A name cannot
+// replace an operand of the SDK, an operand of this package or an operand already registered:
+// RegisterOperand then returns an error wrapping ErrOperandExists.
+func RegisterOperand(name string, fn OperandFunc) error {
+	if !operandName.MatchString(name) {
+		return fmt.Errorf("%w: name %q is not lowercase letters, digits and underscores", ErrInvalidOperand, name)
+	}
+	if fn == nil {
+		return fmt.Errorf("%w: %s has no function", ErrInvalidOperand, name)
+	}
+	for _, sdk := range sdkOperands {
+		if name == sdk {
+			return fmt.Errorf("%w: %s is an operand of the SDK", ErrOperandExists, name)
+		}
+	}
+	if _, ok := packageOperands()[name]; ok {
+		return fmt.Errorf("%w: %s is an operand of the segmentation package", ErrOperandExists, name)
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if _, ok := registry[name]; ok {
+		return fmt.Errorf("%w: %s is already registered", ErrOperandExists, name)
+	}
+	registry[name] = recoverOperand(name, fn)
+	return nil
+}
+
+// packageOperands returns the names of the operands of this package
+func packageOperands() map[string]bool {
+	names := make(map[string]bool)
+	for name := range semverOperands(Coercion{}) {
+		names[name] = true
+	}
+	for name := range timeOperands(systemClock{}, Coercion{}) {
+		names[name] = true
+	}
+	for name := range compiledOperands(Coercion{}) {
+		names[name] = true
+	}
+	return names
+}
+
+// registeredOperands returns a copy of the registered operands
+func registeredOperands() map[string]OperandFunc {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	operands := make(map[string]OperandFunc, len(registry))
+	for name, fn := range registry {
+		operands[name] = fn
+	}
+	return operands
+}
+
+// recoverOperand turns the panics of a custom operand into errors
+func recoverOperand(name string, fn OperandFunc) OperandFunc {
+	return func(expected string, actual interface{}) (matched bool, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				matched, err = false, fmt.Errorf("segmentation: operand %s panicked: %v", name, r)
+			}
+		}()
+		return fn(expected, actual)
+	}
+}
@@ -0,0 +1,205 @@
+/**
+ * Copyright 2025 Wingify Software Pvt. Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package segmentation
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"strings"
+)
+
+// BindingPrefix starts the names of the custom variables Prepare substitutes for extended conditions
+const BindingPrefix = "vwo_segment_"
+
+// SettingsLists is the settings field holding the lists of inlist operands, by id
+const SettingsLists = "lists"
+
+// Prepared holds settings rewritten for the SDK and the conditions it cannot evaluate itself
+type Prepared struct {
+	settings    string
+	bindings    map[string]binding
+	clock       Clock
+	currentTime bool
+	coercion    Coercion
+	regex       RegexLimits
+}
+
+// binding is a condition replaced by a synthetic custom variable: an extended condition, or a regular
+// expression, which runs within the regex limits
+type binding struct {
+	key     string
+	operand string
+	match   matcher
+	builtin *builtinOperand
+}
+
+// matches returns whether the value of a custom variable satisfies the condition
+func (b binding) matches(c Coercion, r *regexRun, actual interface{}) bool {
+	if b.builtin != nil {
+		return b.builtin.match(c, r, actual)
+	}
+	matched, err := b.match(actual)
+	return err == nil && matched
+}
+
+// Prepare rewrites the segments of settings: every condition using an extended operand or a regular
+// expression becomes an equality on a synthetic custom variable, which Context sets to the result of
+// the condition.
+// Pass Settings to the SDK and every user context through Context. Lists found in the SettingsLists
+// field take precedence over those of the ListProvider.
+func (e *Evaluator) Prepare(settings string) (*Prepared, error) {
+	decoder := json.NewDecoder(bytes.NewReader([]byte(settings)))
+	decoder.UseNumber()
+	var decoded map[string]interface{}
+	if err := decoder.Decode(&decoded); err != nil {
+		return nil, err
+	}
+	if lists, ok := decoded[SettingsLists].(map[string]interface{}); ok && len(lists) > 0 {
+		sets := make(Lists, len(lists))
+		for id, values := range lists {
+			members, _ := values.([]interface{})
+			sets[id] = NewSet(members...)
+		}
+		e = e.withLists(sets)
+	}
+
+	p := &Prepared{settings: settings, bindings: make(map[string]binding), clock: e.clock, coercion: e.coercion, regex: e.regex}
+	campaigns, _ := decoded["campaigns"].([]interface{})
+	for _, c := range campaigns {
+		campaign, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if segments, ok := campaign["segments"]; ok {
+			campaign["segments"] = e.rewrite(segments, p)
+		}
+		variations, _ := campaign["variations"].([]interface{})
+		for _, v := range variations {
+			if variation, ok := v.(map[string]interface{}); ok {
+				if segments, ok := variation["segments"]; ok {
+					variation["segments"] = e.rewrite(segments, p)
+				}
+			}
+		}
+	}
+
+	if len(p.bindings) > 0 {
+		rewritten, err := json.Marshal(decoded)
+		if err != nil {
+			return nil, err
+		}
+		p.settings = string(rewritten)
+	}
+	return p, nil
+}
+
+// rewrite replaces the extended conditions of a segment DSL
+func (e *Evaluator) rewrite(node interface{}, p *Prepared) interface{} {
+	switch n := node.(type) {
+	case map[string]interface{}:
+		for operator, value := range n {
+			if operator != OperatorCustomVariable {
+				n[operator] = e.rewrite(value, p)
+				continue
+			}
+			condition, _ := value.(map[string]interface{})
+			key, operand, ok := single(condition)
+			if !ok {
+				continue
+			}
+			if key == CurrentTime {
+				p.currentTime = true
+			}
+			text, _ := operand.(string)
+			b := binding{key: key, operand: text}
+			if match, ok := e.operand(operand); ok {
+				b.match = match
+			} else if builtin := compileBuiltin(text, e.coercion, e.regex); builtin.kind == kindRegex && !strings.Contains(text, "inlist") {
+				b.builtin = builtin
+			} else {
+				continue
+			}
+			name := bindingName(key, text)
+			p.bindings[name] = b
+			n[operator] = map[string]interface{}{name: "true"}
+		}
+	case []interface{}:
+		for i, child := range n {
+			n[i] = e.rewrite(child, p)
+		}
+	}
+	return node
+}
+
+// Settings returns the settings to pass to the SDK
+func (p *Prepared) Settings() string {
+	return p.settings
+}
+
+// Bindings returns the names of the synthetic custom variables, sorted
+func (p *Prepared) Bindings() []string {
+	names := make([]string, 0, len(p.bindings))
+	for name := range p.bindings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Context returns a copy of a user context whose custom variables hold the results of the extended conditions,
+// and CurrentTime when segments use it
+func (p *Prepared) Context(context map[string]interface{}) map[string]interface{} {
+	if len(p.bindings) == 0 && !p.currentTime {
+		return context
+	}
+	customVariables, _ := context["customVariables"].(map[string]interface{})
+	variables := make(map[string]interface{}, len(customVariables)+len(p.bindings)+1)
+	for key, value := range customVariables {
+		variables[key] = value
+	}
+	if p.currentTime {
+		variables[CurrentTime] = p.clock.Now().Unix()
+	}
+	run := regexRun{limits: p.regex}
+	for name, b := range p.bindings {
+		result := false
+		if actual, ok := variables[b.key]; ok {
+			result = b.matches(p.coercion, &run, actual)
+		}
+		if result {
+			variables[name] = "true"
+		} else {
+			variables[name] = "false"
+		}
+	}
+
+	prepared := make(map[string]interface{}, len(context)+1)
+	for key, value := range context {
+		prepared[key] = value
+	}
+	prepared["customVariables"] = variables
+	return prepared
+}
+
+// bindingName returns the name of the synthetic custom variable of a condition
+func bindingName(key string, operand string) string {
+	sum := sha256.Sum256([]byte(key + "\x00" + operand))
+	return BindingPrefix + hex.EncodeToString(sum[:6])
+}
@@ -0,0 +1,180 @@
+/**
+ * Copyright 2025 Wingify Software Pvt. Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package segmentation
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Defaults of RegexLimits
+const (
+	DefaultMaxPatternLength = 1024
+	DefaultMaxInputLength   = 8192
+)
+
+// ErrInvalidRegex is wrapped by the errors of ValidateSettings for regular expressions that do not
+// compile or exceed RegexLimits.MaxPatternLength
+var ErrInvalidRegex = errors.New("segmentation: invalid regex")
+
+// RegexLimits bounds the regular expressions of regex operands, and of wildcard operands without stars,
+// which come from settings and run on user input. Matching takes time linear in the input, so the
+// limits bound the work of each evaluation.
+type RegexLimits struct {
+	// MaxPatternLength bounds the length of patterns: longer ones never match. DefaultMaxPatternLength
+	// when zero, unlimited when negative.
+	MaxPatternLength int
+
+	// MaxInputLength bounds the length of the values matched: longer ones do not match.
+	// DefaultMaxInputLength when zero, unlimited when negative.
+	MaxInputLength int
+
+	// Budget bounds the time an evaluation spends matching regular expressions. Once it is spent, the
+	// remaining regular expressions of the evaluation do not match. Zero does not bound it.
+	Budget time.Duration
+}
+
+// maxPatternLength returns the bound of patterns, negative when unlimited
+func (l RegexLimits) maxPatternLength() int {
+	if l.MaxPatternLength == 0 {
+		return DefaultMaxPatternLength
+	}
+	return l.MaxPatternLength
+}
+
+// maxInputLength returns the bound of values, negative when unlimited
+func (l RegexLimits) maxInputLength() int {
+	if l.MaxInputLength == 0 {
+		return DefaultMaxInputLength
+	}
+	return l.MaxInputLength
+}
+
+// compile compiles a pattern within the limits
+func (l RegexLimits) compile(pattern string) (*regexp.Regexp, error) {
+	if max := l.maxPatternLength(); max >= 0 && len(pattern) > max {
+		return nil, fmt.Errorf("pattern of %d bytes exceeds %d", len(pattern), max)
+	}
+	return regexp.Compile(pattern)
+}
+
+// regexRun tracks the regular expressions matched by one evaluation
+type regexRun struct {
+	limits RegexLimits
+	spent  time.Duration
+}
+
+// match matches a value within the limits and the budget left
+func (r *regexRun) match(re *regexp.Regexp, s string) bool {
+	if re == nil {
+		return false
+	}
+	if max := r.limits.maxInputLength(); max >= 0 && len(s) > max {
+		return false
+	}
+	if r.limits.Budget <= 0 {
+		return re.MatchString(s)
+	}
+	if r.spent >= r.limits.Budget {
+		return false
+	}
+	start := time.Now()
+	matched := re.MatchString(s)
+	r.spent += time.Since(start)
+	return matched
+}
+
+// ValidateSettings checks the regular expressions of the segments of settings, which the SDK and
+// Validate treat as never matching when they are invalid. The error wraps ErrInvalidRegex and lists
+// every invalid operand with its campaign, variation and path in the segment.
+func (e *Evaluator) ValidateSettings(settings string) error {
+	decoder := json.NewDecoder(bytes.NewReader([]byte(settings)))
+	decoder.UseNumber()
+	var decoded map[string]interface{}
+	if err := decoder.Decode(&decoded); err != nil {
+		return err
+	}
+
+	var problems []string
+	campaigns, _ := decoded["campaigns"].([]interface{})
+	for _, c := range campaigns {
+		campaign, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		where := fmt.Sprintf("campaign %v", campaign["id"])
+		problems = e.checkRegexes(campaign["segments"], where+" $", problems)
+		variations, _ := campaign["variations"].([]interface{})
+		for _, v := range variations {
+			if variation, ok := v.(map[string]interface{}); ok {
+				problems = e.checkRegexes(variation["segments"], fmt.Sprintf("%s variation %v $", where, variation["id"]), problems)
+			}
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("%w: %s", ErrInvalidRegex, strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// checkRegexes appends the invalid regular expressions of a segment DSL to problems
+func (e *Evaluator) checkRegexes(node interface{}, path string, problems []string) []string {
+	switch n := node.(type) {
+	case map[string]interface{}:
+		for _, key := range fieldNames(n) {
+			value := n[key]
+			if key != OperatorCustomVariable {
+				problems = e.checkRegexes(value, path+"."+key, problems)
+				continue
+			}
+			condition, _ := value.(map[string]interface{})
+			for _, name := range fieldNames(condition) {
+				operand, ok := condition[name].(string)
+				if !ok {
+					continue
+				}
+				if _, ok := e.operand(operand); ok {
+					continue
+				}
+				if err := compileBuiltin(operand, e.coercion, e.regex).regexErr(); err != nil {
+					problems = append(problems, fmt.Sprintf("%s.%s.%s: %s: %v", path, key, name, operand, err))
+				}
+			}
+		}
+	case []interface{}:
+		for i, child := range n {
+			problems = e.checkRegexes(child, fmt.Sprintf("%s[%d]", path, i), problems)
+		}
+	}
+	return problems
+}
+
+// fieldNames returns the names of the fields of a JSON object, sorted
+func fieldNames(object map[string]interface{}) []string {
+	names := make([]string, 0, len(object))
+	for name := range object {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
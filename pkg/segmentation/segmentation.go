@@ -0,0 +1,359 @@
+/**
+ * Copyright 2025 Wingify Software Pvt. Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package segmentation evaluates segment DSL, the conditions of campaign segments, with operands
+// beyond those of the SDK.
+//
+// The SDK evaluates segments with its own engine, which cannot be extended and parses every segment on
+// each evaluation. An Evaluator compiles segments once, evaluating the operands of the SDK as the SDK
+// does, and hands the conditions that need a user context, such as locations, to that engine. Prepare
+// rewrites settings so that GetFlag honours the extended operands: each condition using one becomes
+// a condition on a synthetic custom variable, which Context computes.
+package segmentation
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sync"
+
+	loggerCore "github.com/wingify/wingify-fme-go-sdk/pkg/packages/logger/core"
+	segmentationCore "github.com/wingify/wingify-fme-go-sdk/pkg/packages/segmentation_evaluator/core"
+)
+
+// Operators of the segment DSL
+const (
+	OperatorAnd            = "and"
+	OperatorOr             = "or"
+	OperatorNot            = "not"
+	OperatorCustomVariable = "custom_variable"
+)
+
+// OperandFunc matches the value of a custom variable against the argument of an operand, the text
+// between its parentheses
+type OperandFunc func(expected string, actual interface{}) (bool, error)
+
+// matcher matches the value of a custom variable against an operand whose argument is already parsed
+type matcher func(actual interface{}) (bool, error)
+
+// Bounds of the caches of an Evaluator
+const (
+	maxCachedOperands = 10000
+	maxCachedSegments = 1000
+)
+
+// semverOperands returns the version operands
+func semverOperands(c Coercion) map[string]OperandFunc {
+	return map[string]OperandFunc{
+		"semver_eq":  semverOperand(c, func(comparison int) bool { return comparison == 0 }),
+		"semver_gt":  semverOperand(c, func(comparison int) bool { return comparison > 0 }),
+		"semver_gte": semverOperand(c, func(comparison int) bool { return comparison >= 0 }),
+		"semver_lt":  semverOperand(c, func(comparison int) bool { return comparison < 0 }),
+		"semver_lte": semverOperand(c, func(comparison int) bool { return comparison <= 0 }),
+	}
+}
+
+// compiledOperands returns the operands that parse their argument once per segment rather than on every evaluation
+func compiledOperands(c Coercion) map[string]func(expected string) (matcher, error) {
+	return map[string]func(expected string) (matcher, error){
+		"in":     setOperand(true, c),
+		"not_in": setOperand(false, c),
+	}
+}
+
+// operandPattern splits an operand such as semver_gt(2.1.0) into its name and argument
+var operandPattern = regexp.MustCompile(`(?s)^\s*([a-z][a-z0-9_]*)\((.*)\)\s*$`)
+
+// Options configures an Evaluator
+type Options struct {
+	// Clock provides the current time of the date and time operands and of CurrentTime; the system clock when nil
+	Clock Clock
+
+	// Lists provides the lists of inlist operands. When nil, inlist is left to the SDK, which checks lists
+	// through the gateway service.
+	Lists ListProvider
+
+	// Unknown decides the result of conditions on custom variables that are not set; UnknownIsFalse,
+	// the behaviour of the SDK, by default
+	Unknown UnknownPolicy
+
+	// Strict makes Validate fail, and Evaluate report ErrMissingVariables, when custom variables used
+	// by a segment are not set, whether or not the result depends on them
+	Strict bool
+
+	// Coercion decides how custom variables are converted before operands compare them; the zero
+	// value converts them as the SDK does
+	Coercion Coercion
+
+	// Regex bounds the regular expressions of regex and wildcard operands
+	Regex RegexLimits
+}
+
+// Evaluator evaluates segment DSL
+type Evaluator struct {
+	sdk       *segmentationCore.SegmentationManager
+	clock     Clock
+	operands  map[string]OperandFunc
+	lists     ListProvider
+	unknown   UnknownPolicy
+	strict    bool
+	coercion  Coercion
+	regex     RegexLimits
+	compilers map[string]func(expected string) (matcher, error)
+
+	mu       sync.RWMutex
+	compiled map[string]compiledOperand
+	segments map[string]*Segment
+}
+
+// compiledOperand is a parsed operand; extended is false for operands left to the SDK
+type compiledOperand struct {
+	match    matcher
+	extended bool
+}
+
+// New creates an Evaluator
+func New(opts Options) *Evaluator {
+	clock := opts.Clock
+	if clock == nil {
+		clock = systemClock{}
+	}
+
+	operands := semverOperands(opts.Coercion)
+	for name, fn := range timeOperands(clock, opts.Coercion) {
+		operands[name] = fn
+	}
+	for name, fn := range registeredOperands() {
+		operands[name] = fn
+	}
+	return &Evaluator{
+		sdk:       segmentationCore.NewSegmentationManagerWithEvaluator(loggerCore.NewLogManager(nil), true),
+		clock:     clock,
+		operands:  operands,
+		lists:     opts.Lists,
+		unknown:   opts.Unknown,
+		strict:    opts.Strict,
+		coercion:  opts.Coercion,
+		regex:     opts.Regex,
+		compilers: compiledOperands(opts.Coercion),
+		compiled:  make(map[string]compiledOperand),
+		segments:  make(map[string]*Segment),
+	}
+}
+
+// withLists returns an Evaluator that also looks inlist lists up in lists, before its own provider
+func (e *Evaluator) withLists(lists ListProvider) *Evaluator {
+	if e.lists != nil {
+		lists = chainedLists{lists, e.lists}
+	}
+	return &Evaluator{
+		sdk:       e.sdk,
+		clock:     e.clock,
+		operands:  e.operands,
+		lists:     lists,
+		unknown:   e.unknown,
+		strict:    e.strict,
+		coercion:  e.coercion,
+		regex:     e.regex,
+		compilers: e.compilers,
+		compiled:  make(map[string]compiledOperand),
+		segments:  make(map[string]*Segment),
+	}
+}
+
+// Validate returns whether custom variables satisfy a segment DSL, given as a JSON string or as decoded JSON.
+// DSL strings are compiled on first use and reused until they change.
+func (e *Evaluator) Validate(dsl interface{}, customVariables map[string]interface{}) bool {
+	segment, err := e.segment(dsl)
+	if err != nil {
+		return false
+	}
+	return segment.Validate(customVariables)
+}
+
+// Evaluate returns the three-valued result of a segment DSL under the unknown policy. In strict mode, it
+// returns an error wrapping ErrMissingVariables with the custom variables the segment uses that are not set.
+func (e *Evaluator) Evaluate(dsl interface{}, customVariables map[string]interface{}) (Truth, error) {
+	segment, err := e.segment(dsl)
+	if err != nil {
+		return False, err
+	}
+	return segment.Evaluate(customVariables)
+}
+
+// segment returns the compiled segment of a DSL, from the cache for DSL strings
+func (e *Evaluator) segment(dsl interface{}) (*Segment, error) {
+	var key string
+	switch v := dsl.(type) {
+	case string:
+		key = v
+	case []byte:
+		key = string(v)
+	default:
+		return e.Compile(dsl)
+	}
+
+	e.mu.RLock()
+	segment, ok := e.segments[key]
+	e.mu.RUnlock()
+	if ok {
+		return segment, nil
+	}
+	segment, err := e.Compile(key)
+	if err != nil {
+		return nil, err
+	}
+	e.mu.Lock()
+	if len(e.segments) >= maxCachedSegments {
+		e.segments = make(map[string]*Segment)
+	}
+	e.segments[key] = segment
+	e.mu.Unlock()
+	return segment, nil
+}
+
+// withCurrentTime returns a copy of custom variables holding CurrentTime
+func (e *Evaluator) withCurrentTime(customVariables map[string]interface{}) map[string]interface{} {
+	variables := make(map[string]interface{}, len(customVariables)+1)
+	for key, value := range customVariables {
+		variables[key] = value
+	}
+	variables[CurrentTime] = e.clock.Now().Unix()
+	return variables
+}
+
+// extended returns whether a node uses an extended operand
+func (e *Evaluator) extended(node interface{}) bool {
+	switch n := node.(type) {
+	case map[string]interface{}:
+		for operator, value := range n {
+			if operator == OperatorCustomVariable {
+				if condition, ok := value.(map[string]interface{}); ok {
+					for _, operand := range condition {
+						if _, ok := e.operand(operand); ok {
+							return true
+						}
+					}
+				}
+				continue
+			}
+			if e.extended(value) {
+				return true
+			}
+		}
+	case []interface{}:
+		for _, child := range n {
+			if e.extended(child) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// operand returns the matcher of an extended operand, parsing it on first use
+func (e *Evaluator) operand(operand interface{}) (matcher, bool) {
+	s, ok := operand.(string)
+	if !ok {
+		return nil, false
+	}
+	e.mu.RLock()
+	c, ok := e.compiled[s]
+	e.mu.RUnlock()
+	if ok {
+		return c.match, c.extended
+	}
+
+	c = e.compile(s)
+	e.mu.Lock()
+	if len(e.compiled) >= maxCachedOperands {
+		e.compiled = make(map[string]compiledOperand)
+	}
+	e.compiled[s] = c
+	e.mu.Unlock()
+	return c.match, c.extended
+}
+
+// compile parses an operand
+func (e *Evaluator) compile(operand string) compiledOperand {
+	parts := operandPattern.FindStringSubmatch(operand)
+	if parts == nil {
+		return compiledOperand{}
+	}
+	name, expected := parts[1], parts[2]
+
+	compiler, ok := e.compilers[name]
+	if name == "inlist" && e.lists != nil {
+		compiler, ok = listOperand(e.lists, e.coercion), true
+	}
+	if ok {
+		match, err := compiler(expected)
+		if err != nil {
+			match = func(interface{}) (bool, error) { return false, err }
+		}
+		return compiledOperand{match: e.coercion.wrap(match), extended: true}
+	}
+	if fn, ok := e.operands[name]; ok {
+		match := func(actual interface{}) (bool, error) { return fn(expected, actual) }
+		return compiledOperand{match: e.coercion.wrap(match), extended: true}
+	}
+	return compiledOperand{}
+}
+
+// sdkValidate evaluates a node with the engine of the SDK
+func (e *Evaluator) sdkValidate(node map[string]interface{}, customVariables map[string]interface{}) (result bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			result = false
+		}
+	}()
+	return e.sdk.ValidateSegmentation(node, customVariables)
+}
+
+// decodeDSL decodes a segment DSL given as a JSON string, bytes or decoded JSON
+func decodeDSL(dsl interface{}) (map[string]interface{}, error) {
+	var raw []byte
+	switch v := dsl.(type) {
+	case map[string]interface{}:
+		return v, nil
+	case string:
+		raw = []byte(v)
+	case []byte:
+		raw = v
+	default:
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		raw = encoded
+	}
+	var node map[string]interface{}
+	if err := json.Unmarshal(raw, &node); err != nil {
+		return nil, fmt.Errorf("segmentation: invalid DSL: %w", err)
+	}
+	return node, nil
+}
+
+// single returns the only entry of a node
+func single(node map[string]interface{}) (string, interface{}, bool) {
+	if len(node) != 1 {
+		return "", nil, false
+	}
+	for key, value := range node {
+		return key, value, true
+	}
+	return "", nil, false
+}
@@ -0,0 +1,144 @@
+/**
+ * Copyright 2025 Wingify Software Pvt. Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package segmentcmd implements the commands of the vwo-segment tool, which prints and explains segment DSL.
+package segmentcmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/wingify/vwo-fme-go-sdk/pkg/segmentation"
+)
+
+// ErrUsage is returned when the arguments are invalid; the usage has already been printed
+var ErrUsage = errors.New("invalid usage")
+
+// usage describes the commands
+const usage = `usage: %[1]s <command> [flags]
+
+commands:
+  format  [-dsl DSL | -in FILE] [-json]    print a segment as an indented tree, or as canonical JSON
+  explain [-dsl DSL | -in FILE] -vars JSON explain the result of each condition for custom variables
+
+The segment is read from stdin when neither -dsl nor -in is given.
+`
+
+// Main runs the command of os.Args and exits with a non-zero status on failure
+func Main() {
+	err := Run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr)
+	switch {
+	case errors.Is(err, ErrUsage):
+		os.Exit(2)
+	case err != nil:
+		fmt.Fprintf(os.Stderr, "%s: %v\n", os.Args[0], err)
+		os.Exit(1)
+	}
+}
+
+// Run runs one command
+func Run(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) error {
+	name := "vwo-segment"
+	if len(args) == 0 {
+		fmt.Fprintf(stderr, usage, name)
+		return ErrUsage
+	}
+
+	command := args[0]
+	flags := flag.NewFlagSet(name+" "+command, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	dsl := flags.String("dsl", "", "segment DSL")
+	in := flags.String("in", "", "file holding the segment DSL")
+	var asJSON *bool
+	var vars *string
+	switch command {
+	case "format":
+		asJSON = flags.Bool("json", false, "print canonical JSON")
+	case "explain":
+		vars = flags.String("vars", "", "custom variables, as a JSON object")
+	default:
+		fmt.Fprintf(stderr, usage, name)
+		return ErrUsage
+	}
+	if err := flags.Parse(args[1:]); err != nil {
+		return ErrUsage
+	}
+	if *dsl != "" && *in != "" || command == "explain" && *vars == "" {
+		fmt.Fprintf(stderr, "%s: -dsl and -in are exclusive, and explain needs -vars\n", flags.Name())
+		return ErrUsage
+	}
+
+	source, err := readDSL(*dsl, *in, stdin)
+	if err != nil {
+		return err
+	}
+
+	switch command {
+	case "format":
+		return format(source, *asJSON, stdout)
+	default:
+		return explain(source, *vars, stdout)
+	}
+}
+
+// readDSL returns the segment DSL of -dsl, of the file -in, or of stdin
+func readDSL(dsl string, path string, stdin io.Reader) ([]byte, error) {
+	switch {
+	case dsl != "":
+		return []byte(dsl), nil
+	case path != "":
+		return os.ReadFile(path)
+	default:
+		return io.ReadAll(stdin)
+	}
+}
+
+// format prints a segment as an indented tree or as canonical JSON
+func format(source []byte, asJSON bool, stdout io.Writer) error {
+	root, err := segmentation.Parse(bytes.TrimSpace(source))
+	if err != nil {
+		return err
+	}
+	if !asJSON {
+		_, err = io.WriteString(stdout, segmentation.Format(root))
+		return err
+	}
+	encoded, err := json.Marshal(root.DSL())
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(stdout, "%s\n", encoded)
+	return err
+}
+
+// explain prints the explanation of a segment for custom variables
+func explain(source []byte, vars string, stdout io.Writer) error {
+	var customVariables map[string]interface{}
+	if err := json.Unmarshal([]byte(vars), &customVariables); err != nil {
+		return fmt.Errorf("-vars: %w", err)
+	}
+	explanation, err := segmentation.New(segmentation.Options{}).Explain(bytes.TrimSpace(source), customVariables)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(stdout, explanation.String())
+	return err
+}
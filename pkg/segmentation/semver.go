@@ -0,0 +1,163 @@
+/**
+ * Copyright 2025 Wingify Software Pvt. Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package segmentation
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Version is a semantic version. Versions may omit their minor and patch numbers, as app versions
+// often do: 2.10 is 2.10.0.
+type Version struct {
+	Major, Minor, Patch uint64
+	Prerelease          []string
+}
+
+// ParseVersion parses a version such as 2.10.3, v2.10.3-beta.2 or 2.10.3+build.7; build metadata is ignored
+func ParseVersion(s string) (Version, error) {
+	var v Version
+	text := strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(s), "v"), "V")
+	if i := strings.IndexByte(text, '+'); i >= 0 {
+		text = text[:i]
+	}
+	if i := strings.IndexByte(text, '-'); i >= 0 {
+		for _, identifier := range strings.Split(text[i+1:], ".") {
+			if identifier == "" {
+				return Version{}, fmt.Errorf("segmentation: invalid version %q", s)
+			}
+			v.Prerelease = append(v.Prerelease, identifier)
+		}
+		text = text[:i]
+	}
+
+	parts := strings.Split(text, ".")
+	if len(parts) > 3 {
+		return Version{}, fmt.Errorf("segmentation: invalid version %q", s)
+	}
+	numbers := []*uint64{&v.Major, &v.Minor, &v.Patch}
+	for i, part := range parts {
+		n, err := strconv.ParseUint(part, 10, 64)
+		if err != nil {
+			return Version{}, fmt.Errorf("segmentation: invalid version %q", s)
+		}
+		*numbers[i] = n
+	}
+	return v, nil
+}
+
+// Compare returns -1, 0 or 1 as v is lower than, equal to or greater than other, following the
+// precedence of Semantic Versioning 2.0: a pre-release is lower than its release
+func (v Version) Compare(other Version) int {
+	if c := compareUint(v.Major, other.Major); c != 0 {
+		return c
+	}
+	if c := compareUint(v.Minor, other.Minor); c != 0 {
+		return c
+	}
+	if c := compareUint(v.Patch, other.Patch); c != 0 {
+		return c
+	}
+
+	switch {
+	case len(v.Prerelease) == 0 && len(other.Prerelease) == 0:
+		return 0
+	case len(v.Prerelease) == 0:
+		return 1
+	case len(other.Prerelease) == 0:
+		return -1
+	}
+	for i := 0; i < len(v.Prerelease) && i < len(other.Prerelease); i++ {
+		if c := compareIdentifiers(v.Prerelease[i], other.Prerelease[i]); c != 0 {
+			return c
+		}
+	}
+	return compareUint(uint64(len(v.Prerelease)), uint64(len(other.Prerelease)))
+}
+
+// String returns the version as major.minor.patch[-prerelease]
+func (v Version) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if len(v.Prerelease) > 0 {
+		s += "-" + strings.Join(v.Prerelease, ".")
+	}
+	return s
+}
+
+// compareIdentifiers compares pre-release identifiers: numeric ones numerically and below alphanumeric ones
+func compareIdentifiers(a, b string) int {
+	na, errA := strconv.ParseUint(a, 10, 64)
+	nb, errB := strconv.ParseUint(b, 10, 64)
+	switch {
+	case errA == nil && errB == nil:
+		return compareUint(na, nb)
+	case errA == nil:
+		return -1
+	case errB == nil:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+// compareUint compares two numbers
+func compareUint(a, b uint64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// semverOperand returns an operand comparing the version of a custom variable with the expected version
+func semverOperand(c Coercion, accept func(comparison int) bool) OperandFunc {
+	return func(expected string, actual interface{}) (bool, error) {
+		want, err := ParseVersion(expected)
+		if err != nil {
+			return false, err
+		}
+		s, ok := c.stringValue(actual)
+		if !ok {
+			return false, fmt.Errorf("segmentation: %v is not a version", actual)
+		}
+		got, err := ParseVersion(s)
+		if err != nil {
+			return false, err
+		}
+		return accept(got.Compare(want)), nil
+	}
+}
+
+// stringValue returns the text of a string or number custom variable
+func stringValue(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		return v, true
+	case json.Number:
+		return v.String(), true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32), true
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprint(v), true
+	}
+	return "", false
+}
@@ -0,0 +1,223 @@
+/**
+ * Copyright 2025 Wingify Software Pvt. Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package segmentation
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Set is a hashed set of custom variable values. Strings match exactly; numbers match by value,
+// so 42, 42.0 and "42" are the same member. Strings holding only digits and dots also match by value,
+// so "5.0" matches 5 and "5", unless the Coercion is Strict or NumbersAsText.
+type Set struct {
+	// members maps the key of each member to the types it was added with
+	members map[string]valueKinds
+
+	// numeric holds the numberKey of the string members holding only digits and dots
+	numeric map[string]bool
+}
+
+// valueKinds is a set of value types
+type valueKinds uint8
+
+// NewSet creates a Set of strings and numbers; other values are skipped
+func NewSet(values ...interface{}) *Set {
+	s := newSet(len(values))
+	for _, value := range values {
+		if key, kind, ok := setKey(value); ok {
+			s.add(key, kind)
+		}
+	}
+	return s
+}
+
+// newSet creates an empty Set sized for n members
+func newSet(n int) *Set {
+	return &Set{members: make(map[string]valueKinds, n), numeric: make(map[string]bool)}
+}
+
+// add adds a member by its key and type
+func (s *Set) add(key string, kind valueKinds) {
+	s.members[key] |= kind
+	if kind == 1<<valueString && isNumericText(key) {
+		s.numeric[numberKey(key)] = true
+	}
+}
+
+// Contains returns whether a value is a member of the set
+func (s *Set) Contains(value interface{}) bool {
+	return s.contains(value, Coercion{})
+}
+
+// contains returns whether a value is a member of the set under a coercion; when strict, a member of the same type
+func (s *Set) contains(value interface{}, c Coercion) bool {
+	if s == nil {
+		return false
+	}
+	key, kind, ok := setKey(value)
+	if !ok {
+		return false
+	}
+	kinds, ok := s.members[key]
+	if c.Strict {
+		return ok && kinds&kind != 0
+	}
+	if ok || c.NumbersAsText {
+		return ok
+	}
+	// numbers and numeric strings also match the numeric strings and numbers of the same value
+	if kind == 1<<valueString {
+		if !isNumericText(key) {
+			return false
+		}
+		key = numberKey(key)
+		if s.members[key]&(1<<valueNumber) != 0 {
+			return true
+		}
+	}
+	return s.numeric[key]
+}
+
+// Len returns the number of members
+func (s *Set) Len() int {
+	if s == nil {
+		return 0
+	}
+	return len(s.members)
+}
+
+// ListProvider returns the named lists of inlist operands. A nil Set with a nil error is an unknown list.
+type ListProvider interface {
+	List(id string) (*Set, error)
+}
+
+// Lists is a ListProvider for a fixed set of lists
+type Lists map[string]*Set
+
+// List returns the list with the given id
+func (l Lists) List(id string) (*Set, error) {
+	return l[id], nil
+}
+
+// chainedLists looks lists up in each provider in turn
+type chainedLists []ListProvider
+
+func (c chainedLists) List(id string) (*Set, error) {
+	for _, provider := range c {
+		set, err := provider.List(id)
+		if err != nil || set != nil {
+			return set, err
+		}
+	}
+	return nil, nil
+}
+
+// setOperand returns the compiler of in or, when member is false, of not_in
+func setOperand(member bool, c Coercion) func(expected string) (matcher, error) {
+	return func(expected string) (matcher, error) {
+		set, err := parseSet(expected)
+		if err != nil {
+			return nil, err
+		}
+		return func(actual interface{}) (bool, error) {
+			if _, _, ok := setKey(actual); !ok {
+				return false, fmt.Errorf("segmentation: %v cannot be a set member", actual)
+			}
+			return set.contains(actual, c) == member, nil
+		}, nil
+	}
+}
+
+// listOperand returns the compiler of inlist, which looks lists up when evaluated so that providers may reload them
+func listOperand(lists ListProvider, c Coercion) func(expected string) (matcher, error) {
+	return func(expected string) (matcher, error) {
+		id := strings.TrimSpace(expected)
+		return func(actual interface{}) (bool, error) {
+			set, err := lists.List(id)
+			if err != nil {
+				return false, err
+			}
+			if set == nil {
+				return false, fmt.Errorf("segmentation: unknown list %q", id)
+			}
+			return set.contains(actual, c), nil
+		}, nil
+	}
+}
+
+// parseSet parses the argument of in and not_in: a JSON array, or values separated by commas in brackets
+func parseSet(expected string) (*Set, error) {
+	expected = strings.TrimSpace(expected)
+	if !strings.HasPrefix(expected, "[") || !strings.HasSuffix(expected, "]") {
+		return nil, fmt.Errorf("segmentation: set %q is not in brackets", expected)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader([]byte(expected)))
+	decoder.UseNumber()
+	var values []interface{}
+	if err := decoder.Decode(&values); err == nil {
+		return NewSet(values...), nil
+	}
+
+	set := newSet(0)
+	for _, value := range strings.Split(expected[1:len(expected)-1], ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			set.add(value, 1<<valueString)
+		}
+	}
+	return set, nil
+}
+
+// setKey returns the key of a value in a Set, strings as is and numbers as by numberKey, with its type
+func setKey(value interface{}) (string, valueKinds, bool) {
+	if s, ok := value.(string); ok {
+		return s, 1 << valueString, true
+	}
+	s, ok := stringValue(value)
+	if !ok {
+		return "", 0, false
+	}
+	return numberKey(s), 1 << valueNumber, true
+}
+
+// numberKey returns the key of a number: integers by their exact decimal text, so that values above
+// 2^53 stay distinct, and other numbers in their shortest decimal form
+func numberKey(s string) string {
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return strconv.FormatInt(i, 10)
+	}
+	if u, err := strconv.ParseUint(s, 10, 64); err == nil {
+		return strconv.FormatUint(u, 10)
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return strconv.FormatFloat(f, 'f', -1, 64)
+	}
+	return s
+}
+
+// isNumericText returns whether a string member holds a number written with only digits and dots
+func isNumericText(s string) bool {
+	if s == "" || !numericChars(s) {
+		return false
+	}
+	_, ok := parseNumber(s)
+	return ok
+}
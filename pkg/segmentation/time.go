@@ -0,0 +1,167 @@
+/**
+ * Copyright 2025 Wingify Software Pvt. Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package segmentation
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CurrentTime is the pseudo custom variable holding the current time, in Unix seconds.
+// The Evaluator sets it from its Clock, replacing any value passed by the caller.
+const CurrentTime = "current_time"
+
+// millisecondThreshold separates Unix timestamps in seconds from timestamps in milliseconds:
+// a timestamp of at least 1e12 is read as milliseconds
+const millisecondThreshold = 1e12
+
+// Clock provides the current time
+type Clock interface {
+	Now() time.Time
+}
+
+// ClockFunc adapts a function to a Clock
+type ClockFunc func() time.Time
+
+// Now returns the time returned by f
+func (f ClockFunc) Now() time.Time {
+	return f()
+}
+
+// systemClock is the Clock of the system
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// timeOperands returns the date and time operands, relative to clock
+func timeOperands(clock Clock, c Coercion) map[string]OperandFunc {
+	return map[string]OperandFunc{
+		"before": timeOperand(c, func(got, want time.Time) bool { return got.Before(want) }),
+		"after":  timeOperand(c, func(got, want time.Time) bool { return got.After(want) }),
+		"between": func(expected string, actual interface{}) (bool, error) {
+			bounds := strings.Split(expected, ",")
+			if len(bounds) != 2 {
+				return false, fmt.Errorf("segmentation: between needs two times, got %q", expected)
+			}
+			start, err := ParseTime(bounds[0])
+			if err != nil {
+				return false, err
+			}
+			end, err := ParseTime(bounds[1])
+			if err != nil {
+				return false, err
+			}
+			got, err := timeValue(actual, c.Strict)
+			if err != nil {
+				return false, err
+			}
+			return !got.Before(start) && !got.After(end), nil
+		},
+		"within_days": daysOperand(clock, c, func(got, since, now time.Time) bool {
+			return !got.Before(since) && !got.After(now)
+		}),
+		"older_than_days": daysOperand(clock, c, func(got, since, now time.Time) bool {
+			return got.Before(since)
+		}),
+	}
+}
+
+// timeOperand returns an operand comparing the time of a custom variable with the expected time
+func timeOperand(c Coercion, accept func(got, want time.Time) bool) OperandFunc {
+	return func(expected string, actual interface{}) (bool, error) {
+		want, err := ParseTime(expected)
+		if err != nil {
+			return false, err
+		}
+		got, err := timeValue(actual, c.Strict)
+		if err != nil {
+			return false, err
+		}
+		return accept(got, want), nil
+	}
+}
+
+// maxDays is the largest whole number of days a time.Duration can hold
+const maxDays = math.MaxInt64 / int64(24*time.Hour)
+
+// daysOperand returns an operand comparing the time of a custom variable with a number of days before now
+func daysOperand(clock Clock, c Coercion, accept func(got, since, now time.Time) bool) OperandFunc {
+	return func(expected string, actual interface{}) (bool, error) {
+		// the days must fit in a time.Duration; NaN fails both comparisons
+		days, err := strconv.ParseFloat(strings.TrimSpace(expected), 64)
+		if err != nil || !(days >= 0 && days <= float64(maxDays)) {
+			return false, fmt.Errorf("segmentation: invalid number of days %q", expected)
+		}
+		got, err := timeValue(actual, c.Strict)
+		if err != nil {
+			return false, err
+		}
+		now := clock.Now()
+		return accept(got, now.Add(-time.Duration(days*float64(24*time.Hour))), now), nil
+	}
+}
+
+// ParseTime parses an RFC3339 time, a date such as 2026-01-01 (midnight UTC), or a Unix timestamp
+// in seconds or, from 1e12, in milliseconds
+func ParseTime(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if seconds, err := strconv.ParseFloat(s, 64); err == nil {
+		return unixTime(seconds)
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("segmentation: invalid time %q", s)
+}
+
+// unixTime converts a Unix timestamp in seconds or milliseconds
+func unixTime(timestamp float64) (time.Time, error) {
+	if math.IsNaN(timestamp) || math.IsInf(timestamp, 0) {
+		return time.Time{}, fmt.Errorf("segmentation: invalid timestamp %v", timestamp)
+	}
+	if math.Abs(timestamp) >= millisecondThreshold {
+		timestamp /= 1000
+	}
+	seconds, fraction := math.Modf(timestamp)
+	return time.Unix(int64(seconds), int64(fraction*1e9)).UTC(), nil
+}
+
+// timeValue returns the time of a custom variable holding a time.Time, a time string or a Unix timestamp;
+// when strict, timestamps must be numbers
+func timeValue(value interface{}, strict bool) (time.Time, error) {
+	if t, ok := value.(time.Time); ok {
+		return t, nil
+	}
+	if s, ok := value.(string); ok && strict {
+		if _, err := strconv.ParseFloat(strings.TrimSpace(s), 64); err == nil {
+			return time.Time{}, fmt.Errorf("segmentation: %q is a number, not a time", s)
+		}
+	}
+	s, ok := stringValue(value)
+	if !ok {
+		return time.Time{}, fmt.Errorf("segmentation: %v is not a time", value)
+	}
+	return ParseTime(s)
+}
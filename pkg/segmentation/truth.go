@@ -0,0 +1,100 @@
+/**
+ * Copyright 2025 Wingify Software Pvt. Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package segmentation
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ErrMissingVariables is returned by Evaluate in strict mode when custom variables used by a segment are not set
+var ErrMissingVariables = errors.New("segmentation: missing custom variables")
+
+// Truth is the result of a three-valued evaluation
+type Truth int
+
+// Truth values
+const (
+	False Truth = iota
+	True
+	// Unknown is the result of a condition on a custom variable that is not set
+	Unknown
+)
+
+// String returns true, false or unknown
+func (t Truth) String() string {
+	switch t {
+	case True:
+		return "true"
+	case Unknown:
+		return "unknown"
+	}
+	return "false"
+}
+
+// truthOf converts a bool
+func truthOf(b bool) Truth {
+	if b {
+		return True
+	}
+	return False
+}
+
+// UnknownPolicy decides the result of conditions on custom variables that are not set
+type UnknownPolicy int
+
+// Unknown policies
+const (
+	// UnknownIsFalse makes these conditions false, as the SDK does: a not over one of them is true
+	UnknownIsFalse UnknownPolicy = iota
+
+	// UnknownPropagates keeps them unknown: a not over one of them is unknown, an and is unknown
+	// unless another condition is false, and an or is unknown unless another condition is true.
+	// Validate does not match segments that are unknown.
+	UnknownPropagates
+
+	// UnknownIsTrue makes these conditions true
+	UnknownIsTrue
+)
+
+// leaf returns the result of a condition on a custom variable that is not set
+func (p UnknownPolicy) leaf() Truth {
+	switch p {
+	case UnknownPropagates:
+		return Unknown
+	case UnknownIsTrue:
+		return True
+	}
+	return False
+}
+
+// missingError reports the custom variables a segment uses that are not set
+func missingError(missing []string) error {
+	return fmt.Errorf("%w: %s", ErrMissingVariables, strings.Join(missing, ", "))
+}
+
+// sortedKeys returns the keys of a set, sorted
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,131 @@
+/**
+ * Copyright 2025 Wingify Software Pvt. Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Errors returned when a connector lacks an optional extension
+var (
+	ErrScanUnsupported   = errors.New("storage: connector does not support Scan")
+	ErrDeleteUnsupported = errors.New("storage: connector does not support Delete")
+)
+
+// Scanner is an optional extension of Connector for backends that can enumerate stored decisions
+type Scanner interface {
+	// Scan calls fn for every stored decision of userID, or of every user when userID is empty.
+	// An error returned by fn stops the scan and is returned.
+	Scan(ctx context.Context, userID string, fn func(data map[string]interface{}) error) error
+}
+
+// Compacter is implemented by connectors that keep deleted decisions until they are compacted
+type Compacter interface {
+	Compact() error
+}
+
+// Wrapper is implemented by connectors that wrap another connector
+type Wrapper interface {
+	Unwrap() Connector
+}
+
+// Admin answers data subject requests and moves decisions between backends
+type Admin struct {
+	c Connector
+}
+
+// NewAdmin creates an Admin for c, which must implement Scanner and, to forget users, Deleter
+func NewAdmin(c Connector) *Admin {
+	return &Admin{c: c}
+}
+
+// ListUser returns every stored decision of a user
+func (a *Admin) ListUser(ctx context.Context, userID string) ([]map[string]interface{}, error) {
+	if userID == "" {
+		return nil, fmt.Errorf("storage: user id is required")
+	}
+	var decisions []map[string]interface{}
+	err := scanFrom(ctx, a.c, userID, func(data map[string]interface{}) error {
+		decisions = append(decisions, data)
+		return nil
+	})
+	return decisions, err
+}
+
+// ForgetUser deletes every stored decision of a user and returns how many were deleted.
+// Connectors that keep deleted data until compaction are compacted afterwards.
+func (a *Admin) ForgetUser(ctx context.Context, userID string) (int, error) {
+	decisions, err := a.ListUser(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	deleted := 0
+	for _, data := range decisions {
+		if err := ctx.Err(); err != nil {
+			return deleted, err
+		}
+		featureKey, _ := data["featureKey"].(string)
+		if err := deleteFrom(a.c, featureKey, userID); err != nil {
+			return deleted, fmt.Errorf("deleting %s: %w", featureKey, err)
+		}
+		deleted++
+	}
+
+	// compaction also removes expired decisions of the user that Scan no longer reports
+	if compacter, ok := findCompacter(a.c); ok {
+		if err := compacter.Compact(); err != nil {
+			return deleted, err
+		}
+	}
+	return deleted, nil
+}
+
+// scanFrom scans c, reporting ErrScanUnsupported when it is not a Scanner
+func scanFrom(ctx context.Context, c Connector, userID string, fn func(data map[string]interface{}) error) error {
+	scanner, ok := c.(Scanner)
+	if !ok {
+		return ErrScanUnsupported
+	}
+	return scanner.Scan(ctx, userID, fn)
+}
+
+// deleteFrom deletes a decision from c, reporting ErrDeleteUnsupported when it is not a Deleter
+func deleteFrom(c Connector, featureKey string, userID string) error {
+	deleter, ok := c.(Deleter)
+	if !ok {
+		return ErrDeleteUnsupported
+	}
+	return deleter.Delete(featureKey, userID)
+}
+
+// findCompacter finds the first Compacter in a chain of wrapped connectors
+func findCompacter(c Connector) (Compacter, bool) {
+	for c != nil {
+		if compacter, ok := c.(Compacter); ok {
+			return compacter, true
+		}
+		wrapper, ok := c.(Wrapper)
+		if !ok {
+			return nil, false
+		}
+		c = wrapper.Unwrap()
+	}
+	return nil, false
+}
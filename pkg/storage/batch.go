@@ -0,0 +1,246 @@
+/**
+ * Copyright 2025 Wingify Software Pvt. Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package storage
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/wingify/wingify-fme-go-sdk/pkg/enums"
+	"github.com/wingify/wingify-fme-go-sdk/pkg/models"
+)
+
+// BatchConnector is an optional extension of Connector for backends that can read and write
+// several decisions in one round trip
+type BatchConnector interface {
+	Connector
+
+	// GetMany returns the stored decisions of a user keyed by feature; features without one are absent
+	GetMany(userID string, featureKeys []string) (map[string]map[string]interface{}, error)
+
+	// SetMany stores several decisions
+	SetMany(records []map[string]interface{}) error
+}
+
+// GetMany reads the decisions of a user with one GetMany call when c is a BatchConnector,
+// and with one Get call per feature otherwise
+func GetMany(c Connector, userID string, featureKeys []string) (map[string]map[string]interface{}, error) {
+	if batch, ok := c.(BatchConnector); ok {
+		result, err := batch.GetMany(userID, featureKeys)
+		if err == nil && result == nil {
+			// callers add to the result, so a connector returning no map gets an empty one
+			result = map[string]map[string]interface{}{}
+		}
+		return result, err
+	}
+
+	result := make(map[string]map[string]interface{}, len(featureKeys))
+	for _, featureKey := range featureKeys {
+		value, err := c.Get(featureKey, userID)
+		if err != nil {
+			return nil, err
+		}
+		if decision, ok := value.(map[string]interface{}); ok {
+			result[featureKey] = decision
+		}
+	}
+	return result, nil
+}
+
+// SetMany writes decisions with one SetMany call when c is a BatchConnector,
+// and with one Set call per decision otherwise, returning the first error
+func SetMany(c Connector, records []map[string]interface{}) error {
+	if batch, ok := c.(BatchConnector); ok {
+		return batch.SetMany(records)
+	}
+
+	var firstErr error
+	for _, data := range records {
+		if err := c.Set(data); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// BatchingConnector serves prefetched decisions and defers writes so that evaluating several
+// flags for one user costs a single GetMany and a single SetMany on the inner connector
+type BatchingConnector struct {
+	inner Connector
+
+	mu     sync.Mutex
+	owners map[string]*prefetch
+}
+
+// prefetch holds the decisions loaded for one bulk evaluation
+type prefetch struct {
+	decisions map[string]map[string]interface{}
+	pending   []map[string]interface{}
+}
+
+// Batched wraps inner so that GetFlags and Prefetch can batch its reads and writes
+func Batched(inner Connector) *BatchingConnector {
+	return &BatchingConnector{
+		inner:  inner,
+		owners: make(map[string]*prefetch),
+	}
+}
+
+// Get returns a prefetched decision, or reads it from the inner connector
+func (b *BatchingConnector) Get(featureKey string, userID string) (interface{}, error) {
+	b.mu.Lock()
+	if p, ok := b.owners[decisionKey(featureKey, userID)]; ok {
+		decision := copyDecision(p.decisions[featureKey])
+		b.mu.Unlock()
+		if decision == nil {
+			return nil, nil
+		}
+		return decision, nil
+	}
+	b.mu.Unlock()
+
+	return b.inner.Get(featureKey, userID)
+}
+
+// Set defers the write of a prefetched decision until the prefetch is flushed,
+// and writes any other decision to the inner connector
+func (b *BatchingConnector) Set(data map[string]interface{}) error {
+	key, err := keyOf(data)
+	if err == nil {
+		b.mu.Lock()
+		if p, ok := b.owners[key]; ok {
+			decision := copyDecision(data)
+			p.decisions[data["featureKey"].(string)] = decision
+			p.pending = append(p.pending, decision)
+			b.mu.Unlock()
+			return nil
+		}
+		b.mu.Unlock()
+	}
+
+	return b.inner.Set(data)
+}
+
+// GetMany reads the decisions of a user from the inner connector
+func (b *BatchingConnector) GetMany(userID string, featureKeys []string) (map[string]map[string]interface{}, error) {
+	return GetMany(b.inner, userID, featureKeys)
+}
+
+// SetMany writes decisions to the inner connector
+func (b *BatchingConnector) SetMany(records []map[string]interface{}) error {
+	return SetMany(b.inner, records)
+}
+
+// Delete deletes a decision from the inner connector and from any prefetch holding it
+func (b *BatchingConnector) Delete(featureKey string, userID string) error {
+	key := decisionKey(featureKey, userID)
+	b.mu.Lock()
+	if p, ok := b.owners[key]; ok {
+		delete(p.decisions, featureKey)
+		pending := p.pending[:0]
+		for _, data := range p.pending {
+			if pendingKey, _ := keyOf(data); pendingKey != key {
+				pending = append(pending, data)
+			}
+		}
+		p.pending = pending
+	}
+	b.mu.Unlock()
+
+	return deleteFrom(b.inner, featureKey, userID)
+}
+
+// Scan enumerates the decisions of the inner connector
+func (b *BatchingConnector) Scan(ctx context.Context, userID string, fn func(data map[string]interface{}) error) error {
+	return scanFrom(ctx, b.inner, userID, fn)
+}
+
+// Unwrap returns the inner connector
+func (b *BatchingConnector) Unwrap() Connector {
+	return b.inner
+}
+
+// Prefetch loads the decisions of a user for featureKeys with one read. Until flush is called,
+// Get serves them from memory and Set buffers them; flush writes the buffered decisions with one write.
+func (b *BatchingConnector) Prefetch(userID string, featureKeys []string) (flush func() error, err error) {
+	decisions, err := GetMany(b.inner, userID, featureKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &prefetch{decisions: decisions}
+	keys := make([]string, len(featureKeys))
+	b.mu.Lock()
+	for i, featureKey := range featureKeys {
+		keys[i] = decisionKey(featureKey, userID)
+		b.owners[keys[i]] = p
+	}
+	b.mu.Unlock()
+
+	var once sync.Once
+	return func() error {
+		var flushErr error
+		once.Do(func() {
+			b.mu.Lock()
+			for _, key := range keys {
+				if b.owners[key] == p {
+					delete(b.owners, key)
+				}
+			}
+			pending := p.pending
+			p.pending = nil
+			b.mu.Unlock()
+
+			if len(pending) > 0 {
+				flushErr = SetMany(b.inner, pending)
+			}
+		})
+		return flushErr
+	}, nil
+}
+
+// GetFlags evaluates several flags for one user, reading their stored decisions with one GetMany
+// and writing new decisions with one SetMany. Flags that fail to evaluate are left out of the
+// result and the first evaluation error is returned alongside the other flags.
+func (b *BatchingConnector) GetFlags(client FlagClient, featureKeys []string, context map[string]interface{}) (map[string]models.GetFlagResponse, error) {
+	flush := func() error { return nil }
+	if userID, ok := context[enums.ContextID.GetValue()].(string); ok && userID != "" {
+		// without a prefetch every flag still evaluates, reading storage one feature at a time
+		if prefetched, err := b.Prefetch(userID, featureKeys); err == nil {
+			flush = prefetched
+		}
+	}
+
+	flags := make(map[string]models.GetFlagResponse, len(featureKeys))
+	var firstErr error
+	for _, featureKey := range featureKeys {
+		flag, err := client.GetFlag(featureKey, context)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("%s: %w", featureKey, err)
+			}
+			continue
+		}
+		flags[featureKey] = flag
+	}
+
+	if err := flush(); err != nil && firstErr == nil {
+		firstErr = err
+	}
+	return flags, firstErr
+}
@@ -0,0 +1,168 @@
+/**
+ * Copyright 2025 Wingify Software Pvt. Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package storage
+
+import (
+	"sync"
+	"time"
+)
+
+// Circuit breaker defaults used when BreakerOptions fields are left zero
+const (
+	DefaultFailureThreshold = 5
+	DefaultOpenDuration     = 30 * time.Second
+)
+
+// BreakerState is the state of a circuit breaker
+type BreakerState int
+
+// Circuit breaker states
+const (
+	// BreakerClosed lets every call through
+	BreakerClosed BreakerState = iota
+
+	// BreakerOpen rejects every call until the open duration has elapsed
+	BreakerOpen
+
+	// BreakerHalfOpen lets a single probe through to test whether the backend recovered
+	BreakerHalfOpen
+)
+
+// String returns the name of the state
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerClosed:
+		return "closed"
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	}
+	return "unknown"
+}
+
+// BreakerOptions configures a circuit breaker
+type BreakerOptions struct {
+	// FailureThreshold is the number of consecutive failures that opens the breaker
+	FailureThreshold int
+
+	// OpenDuration is how long the breaker stays open before probing the backend
+	OpenDuration time.Duration
+}
+
+// circuitBreaker tracks consecutive failures and rejects calls while the backend is considered down
+type circuitBreaker struct {
+	mu       sync.Mutex
+	opts     BreakerOptions
+	state    BreakerState
+	failures int
+	openedAt time.Time
+	onChange func(from BreakerState, to BreakerState)
+}
+
+// newCircuitBreaker creates a closed breaker
+func newCircuitBreaker(opts BreakerOptions, onChange func(from BreakerState, to BreakerState)) *circuitBreaker {
+	if opts.FailureThreshold <= 0 {
+		opts.FailureThreshold = DefaultFailureThreshold
+	}
+	if opts.OpenDuration <= 0 {
+		opts.OpenDuration = DefaultOpenDuration
+	}
+	return &circuitBreaker{opts: opts, onChange: onChange}
+}
+
+// allow returns whether a call may proceed, moving an expired open breaker to half-open for a probe
+func (b *circuitBreaker) allow() bool {
+	b.mu.Lock()
+	var change *stateChange
+	allowed := false
+	switch b.state {
+	case BreakerClosed:
+		allowed = true
+	case BreakerOpen:
+		if time.Since(b.openedAt) >= b.opts.OpenDuration {
+			change = b.transition(BreakerHalfOpen)
+			allowed = true
+		}
+	}
+	b.mu.Unlock()
+
+	b.notify(change)
+	return allowed
+}
+
+// rejecting returns whether the next call would be rejected without reaching the backend
+func (b *circuitBreaker) rejecting() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case BreakerOpen:
+		return time.Since(b.openedAt) < b.opts.OpenDuration
+	case BreakerHalfOpen:
+		return true
+	}
+	return false
+}
+
+// record updates the breaker with the outcome of a call that was allowed through
+func (b *circuitBreaker) record(success bool) {
+	b.mu.Lock()
+	var change *stateChange
+	if success {
+		b.failures = 0
+		if b.state != BreakerClosed {
+			change = b.transition(BreakerClosed)
+		}
+	} else {
+		b.failures++
+		if b.state == BreakerHalfOpen || (b.state == BreakerClosed && b.failures >= b.opts.FailureThreshold) {
+			b.openedAt = time.Now()
+			change = b.transition(BreakerOpen)
+		}
+	}
+	b.mu.Unlock()
+
+	b.notify(change)
+}
+
+// current returns the state of the breaker
+func (b *circuitBreaker) current() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+// stateChange is a transition recorded under the lock and reported after it is released
+type stateChange struct {
+	from BreakerState
+	to   BreakerState
+}
+
+// transition changes the state and returns the change to report; the caller holds the lock
+func (b *circuitBreaker) transition(to BreakerState) *stateChange {
+	from := b.state
+	b.state = to
+	return &stateChange{from: from, to: to}
+}
+
+// notify reports a change to the listener; the caller must not hold the lock so the listener can query the breaker
+func (b *circuitBreaker) notify(change *stateChange) {
+	if change != nil && b.onChange != nil {
+		b.onChange(change.from, change.to)
+	}
+}
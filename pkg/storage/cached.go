@@ -0,0 +1,380 @@
+/**
+ * Copyright 2025 Wingify Software Pvt. Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package storage
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultCacheSize is the cache size used when Cached is given a non-positive size
+const DefaultCacheSize = 10000
+
+// errLoadPanicked is returned to the lookups waiting for an inner call that panicked
+var errLoadPanicked = errors.New("storage: inner connector panicked")
+
+// CacheStats holds the counters of a CachedConnector
+type CacheStats struct {
+	// Hits counts lookups answered from the cache, including negative hits
+	Hits uint64
+
+	// NegativeHits counts lookups answered by a cached "no decision"
+	NegativeHits uint64
+
+	// Misses counts lookups sent to the inner connector
+	Misses uint64
+
+	// Coalesced counts lookups that waited for an identical lookup already in flight
+	Coalesced uint64
+
+	// Evictions counts entries dropped to respect the cache size
+	Evictions uint64
+
+	// Errors counts failed calls to the inner connector
+	Errors uint64
+}
+
+// CachedConnector is an in-process LRU cache in front of another connector
+type CachedConnector struct {
+	// counters are accessed atomically and kept first for 64-bit alignment
+	hits, negativeHits, misses, coalesced, evictions, errors uint64
+
+	inner Connector
+	size  int
+	ttl   time.Duration
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List
+	flights map[string]*flight
+}
+
+// cacheEntry is a cached lookup; a nil decision records that none is stored
+type cacheEntry struct {
+	key      string
+	decision map[string]interface{}
+	expires  time.Time
+}
+
+// flight is a lookup in progress shared by concurrent callers
+type flight struct {
+	wg       sync.WaitGroup
+	decision map[string]interface{}
+	err      error
+	stale    bool
+}
+
+// Cached wraps inner with an LRU cache of size entries that expire after ttl.
+// Lookups that find no decision are cached too; a ttl of zero never expires entries.
+func Cached(inner Connector, size int, ttl time.Duration) *CachedConnector {
+	if size <= 0 {
+		size = DefaultCacheSize
+	}
+	return &CachedConnector{
+		inner:   inner,
+		size:    size,
+		ttl:     ttl,
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+		flights: make(map[string]*flight),
+	}
+}
+
+// Get returns the cached decision or loads it from the inner connector.
+// Concurrent lookups of the same decision share a single call to the inner connector.
+func (c *CachedConnector) Get(featureKey string, userID string) (interface{}, error) {
+	key := decisionKey(featureKey, userID)
+
+	c.mu.Lock()
+	if decision, ok := c.lookup(key, time.Now()); ok {
+		c.mu.Unlock()
+		atomic.AddUint64(&c.hits, 1)
+		if decision == nil {
+			atomic.AddUint64(&c.negativeHits, 1)
+			return nil, nil
+		}
+		return copyDecision(decision), nil
+	}
+
+	if f, ok := c.flights[key]; ok {
+		c.mu.Unlock()
+		atomic.AddUint64(&c.coalesced, 1)
+		f.wg.Wait()
+		return resultOf(f)
+	}
+
+	// the error stands until the load returns, so waiters fail instead of hanging if it panics
+	f := &flight{err: errLoadPanicked}
+	f.wg.Add(1)
+	c.flights[key] = f
+	c.mu.Unlock()
+	atomic.AddUint64(&c.misses, 1)
+
+	defer c.land(map[string]*flight{key: f})
+	f.decision, f.err = c.load(featureKey, userID)
+	return resultOf(f)
+}
+
+// Set writes the decision to the inner connector and, when that succeeds, to the cache
+func (c *CachedConnector) Set(data map[string]interface{}) error {
+	key, keyErr := keyOf(data)
+
+	err := c.inner.Set(data)
+	if keyErr != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if f, ok := c.flights[key]; ok {
+		f.stale = true
+	}
+	if err != nil {
+		atomic.AddUint64(&c.errors, 1)
+		c.remove(key)
+		return err
+	}
+	c.store(key, copyDecision(data))
+	return nil
+}
+
+// GetMany answers from the cache where possible and reads the remaining decisions with one batch call.
+// Concurrent Get calls for the missing decisions wait for the batch instead of reading them again.
+func (c *CachedConnector) GetMany(userID string, featureKeys []string) (map[string]map[string]interface{}, error) {
+	result := make(map[string]map[string]interface{}, len(featureKeys))
+	var missing []string
+	flights := make(map[string]*flight)
+
+	c.mu.Lock()
+	now := time.Now()
+	for _, featureKey := range featureKeys {
+		key := decisionKey(featureKey, userID)
+		decision, ok := c.lookup(key, now)
+		if !ok {
+			missing = append(missing, featureKey)
+			if _, inFlight := c.flights[key]; !inFlight {
+				f := &flight{err: errLoadPanicked}
+				f.wg.Add(1)
+				c.flights[key] = f
+				flights[key] = f
+			}
+			continue
+		}
+		atomic.AddUint64(&c.hits, 1)
+		if decision == nil {
+			atomic.AddUint64(&c.negativeHits, 1)
+			continue
+		}
+		result[featureKey] = copyDecision(decision)
+	}
+	c.mu.Unlock()
+
+	if len(missing) == 0 {
+		return result, nil
+	}
+	atomic.AddUint64(&c.misses, uint64(len(missing)))
+
+	defer c.land(flights)
+	loaded, err := GetMany(c.inner, userID, missing)
+	if err != nil {
+		atomic.AddUint64(&c.errors, 1)
+	}
+	for _, featureKey := range missing {
+		if f, owned := flights[decisionKey(featureKey, userID)]; owned {
+			f.decision, f.err = loaded[featureKey], err
+		}
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	for featureKey, decision := range loaded {
+		result[featureKey] = copyDecision(decision)
+	}
+	return result, nil
+}
+
+// SetMany writes the decisions to the inner connector with one batch call and caches them when it succeeds
+func (c *CachedConnector) SetMany(records []map[string]interface{}) error {
+	err := SetMany(c.inner, records)
+	if err != nil {
+		atomic.AddUint64(&c.errors, 1)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, data := range records {
+		key, keyErr := keyOf(data)
+		if keyErr != nil {
+			continue
+		}
+		if f, ok := c.flights[key]; ok {
+			f.stale = true
+		}
+		if err != nil {
+			c.remove(key)
+		} else {
+			c.store(key, copyDecision(data))
+		}
+	}
+	return err
+}
+
+// Delete deletes the decision from the inner connector and drops it from the cache
+func (c *CachedConnector) Delete(featureKey string, userID string) error {
+	err := deleteFrom(c.inner, featureKey, userID)
+	c.Invalidate(featureKey, userID)
+	return err
+}
+
+// Scan enumerates the decisions of the inner connector
+func (c *CachedConnector) Scan(ctx context.Context, userID string, fn func(data map[string]interface{}) error) error {
+	return scanFrom(ctx, c.inner, userID, fn)
+}
+
+// Unwrap returns the inner connector
+func (c *CachedConnector) Unwrap() Connector {
+	return c.inner
+}
+
+// Invalidate drops the cached decision of a user for a feature
+func (c *CachedConnector) Invalidate(featureKey string, userID string) {
+	key := decisionKey(featureKey, userID)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if f, ok := c.flights[key]; ok {
+		f.stale = true
+	}
+	c.remove(key)
+}
+
+// Purge drops every cached decision
+func (c *CachedConnector) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, f := range c.flights {
+		f.stale = true
+	}
+	c.entries = make(map[string]*list.Element)
+	c.order.Init()
+}
+
+// Len returns the number of cached entries
+func (c *CachedConnector) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// Stats returns a snapshot of the cache counters
+func (c *CachedConnector) Stats() CacheStats {
+	return CacheStats{
+		Hits:         atomic.LoadUint64(&c.hits),
+		NegativeHits: atomic.LoadUint64(&c.negativeHits),
+		Misses:       atomic.LoadUint64(&c.misses),
+		Coalesced:    atomic.LoadUint64(&c.coalesced),
+		Evictions:    atomic.LoadUint64(&c.evictions),
+		Errors:       atomic.LoadUint64(&c.errors),
+	}
+}
+
+// load reads a decision from the inner connector
+func (c *CachedConnector) load(featureKey string, userID string) (map[string]interface{}, error) {
+	result, err := c.inner.Get(featureKey, userID)
+	if err != nil {
+		atomic.AddUint64(&c.errors, 1)
+		return nil, err
+	}
+	decision, _ := result.(map[string]interface{})
+	return decision, nil
+}
+
+// land ends the flights owned by a lookup, caching their results; it runs deferred so that it also
+// releases the waiting lookups when the inner connector panics
+func (c *CachedConnector) land(flights map[string]*flight) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key, f := range flights {
+		delete(c.flights, key)
+		if f.err == nil && !f.stale {
+			c.store(key, f.decision)
+		}
+		f.wg.Done()
+	}
+}
+
+// lookup returns the cached decision of key when present and fresh
+func (c *CachedConnector) lookup(key string, now time.Time) (map[string]interface{}, bool) {
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := element.Value.(*cacheEntry)
+	if c.ttl > 0 && now.After(entry.expires) {
+		c.order.Remove(element)
+		delete(c.entries, key)
+		return nil, false
+	}
+	c.order.MoveToFront(element)
+	return entry.decision, true
+}
+
+// store caches a decision, evicting the least recently used entries beyond the cache size
+func (c *CachedConnector) store(key string, decision map[string]interface{}) {
+	entry := &cacheEntry{key: key, decision: decision}
+	if c.ttl > 0 {
+		entry.expires = time.Now().Add(c.ttl)
+	}
+
+	if element, ok := c.entries[key]; ok {
+		element.Value = entry
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(entry)
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+		atomic.AddUint64(&c.evictions, 1)
+	}
+}
+
+// remove drops the cached entry of key
+func (c *CachedConnector) remove(key string) {
+	if element, ok := c.entries[key]; ok {
+		c.order.Remove(element)
+		delete(c.entries, key)
+	}
+}
+
+// resultOf converts the outcome of a flight to the Get return values
+func resultOf(f *flight) (interface{}, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	if f.decision == nil {
+		return nil, nil
+	}
+	return copyDecision(f.decision), nil
+}
@@ -0,0 +1,59 @@
+/**
+ * Copyright 2025 Wingify Software Pvt. Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package storage provides composable wrappers around storage connectors.
+//
+// Every wrapper implements Connector and can be passed as the "storage" option of Init.
+package storage
+
+import (
+	"fmt"
+
+	"github.com/wingify/wingify-fme-go-sdk/pkg/packages/storage"
+)
+
+// Connector is the storage connector contract of the SDK
+type Connector = storage.Connector
+
+// decisionKey returns the "featureKey:userId" key of a decision
+func decisionKey(featureKey string, userID string) string {
+	return featureKey + ":" + userID
+}
+
+// keyOf returns the key of a decision passed to Set
+func keyOf(data map[string]interface{}) (string, error) {
+	featureKey, ok := data["featureKey"].(string)
+	if !ok {
+		return "", fmt.Errorf("featureKey not found or not a string")
+	}
+	userID, ok := data["userId"].(string)
+	if !ok {
+		return "", fmt.Errorf("userId not found or not a string")
+	}
+	return decisionKey(featureKey, userID), nil
+}
+
+// copyDecision returns a shallow copy of a decision so that callers cannot mutate cached state
+func copyDecision(data map[string]interface{}) map[string]interface{} {
+	if data == nil {
+		return nil
+	}
+	copied := make(map[string]interface{}, len(data))
+	for key, value := range data {
+		copied[key] = value
+	}
+	return copied
+}
@@ -0,0 +1,404 @@
+/**
+ * Copyright 2025 Wingify Software Pvt. Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package storage
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// MinKeySize is the minimum length of a Key secret
+const MinKeySize = 16
+
+// Fields of the records an EncryptedConnector writes to the inner connector
+const (
+	FieldKeyID   = "keyId"
+	FieldPayload = "payload"
+)
+
+// Errors reported by an EncryptedConnector
+var (
+	ErrNoKeys     = errors.New("storage: key provider returned no keys")
+	ErrUnknownKey = errors.New("storage: record encrypted with an unknown key")
+)
+
+// Key is a secret used to hash user ids and encrypt decisions
+type Key struct {
+	// ID identifies the key in stored records; it is not secret
+	ID string
+
+	// Secret is at least MinKeySize random bytes
+	Secret []byte
+}
+
+// KeyProvider returns the keys of an EncryptedConnector: the first key encrypts new records,
+// the others are only used to read records written before a rotation
+type KeyProvider interface {
+	Keys() ([]Key, error)
+}
+
+// StaticKeys is a KeyProvider for a fixed list of keys, current key first
+type StaticKeys []Key
+
+// Keys returns the keys
+func (k StaticKeys) Keys() ([]Key, error) {
+	return k, nil
+}
+
+// EncryptedConnector stores decisions under a keyed hash of the user id with an AES-GCM encrypted payload.
+// The inner connector must store every field of a decision.
+type EncryptedConnector struct {
+	inner    Connector
+	provider KeyProvider
+
+	mu      sync.Mutex
+	derived map[string]*derivedKey
+}
+
+// derivedKey holds the hashing and encryption keys derived from a Key
+type derivedKey struct {
+	id     string
+	secret []byte
+	mac    []byte
+	aead   cipher.AEAD
+}
+
+// Encrypted wraps inner so that user ids and decisions are never stored in clear
+func Encrypted(inner Connector, provider KeyProvider) *EncryptedConnector {
+	return &EncryptedConnector{
+		inner:    inner,
+		provider: provider,
+		derived:  make(map[string]*derivedKey),
+	}
+}
+
+// Get reads a decision, falling back to the older keys. A decision found under an older key
+// is rewritten under the current key, and the old record is deleted when the inner connector supports it.
+func (e *EncryptedConnector) Get(featureKey string, userID string) (interface{}, error) {
+	keys, err := e.keys()
+	if err != nil {
+		return nil, err
+	}
+
+	for i, key := range keys {
+		hashedID := key.hash(userID)
+		result, err := e.inner.Get(featureKey, hashedID)
+		if err != nil {
+			return nil, err
+		}
+		record, ok := result.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		decision, err := e.openAt(record, keys, featureKey, hashedID)
+		if err != nil {
+			return nil, err
+		}
+		if i > 0 {
+			e.rotate(keys[0], decision, featureKey, hashedID)
+		}
+		return decision, nil
+	}
+	return nil, nil
+}
+
+// Set encrypts a decision with the current key and writes it to the inner connector
+func (e *EncryptedConnector) Set(data map[string]interface{}) error {
+	keys, err := e.keys()
+	if err != nil {
+		return err
+	}
+	record, err := e.seal(keys[0], data)
+	if err != nil {
+		return err
+	}
+	return e.inner.Set(record)
+}
+
+// GetMany reads several decisions of a user with one batch call per key, stopping at the first key
+// that finds them all. Decisions found under older keys are rewritten under the current key.
+func (e *EncryptedConnector) GetMany(userID string, featureKeys []string) (map[string]map[string]interface{}, error) {
+	keys, err := e.keys()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]map[string]interface{}, len(featureKeys))
+	missing := featureKeys
+	for i, key := range keys {
+		if len(missing) == 0 {
+			break
+		}
+		hashedID := key.hash(userID)
+		records, err := GetMany(e.inner, hashedID, missing)
+		if err != nil {
+			return nil, err
+		}
+
+		var stillMissing []string
+		for _, featureKey := range missing {
+			record, ok := records[featureKey]
+			if !ok {
+				stillMissing = append(stillMissing, featureKey)
+				continue
+			}
+			decision, err := e.openAt(record, keys, featureKey, hashedID)
+			if err != nil {
+				return nil, err
+			}
+			if i > 0 {
+				e.rotate(keys[0], decision, featureKey, hashedID)
+			}
+			result[featureKey] = decision
+		}
+		missing = stillMissing
+	}
+	return result, nil
+}
+
+// SetMany encrypts several decisions with the current key and writes them with one batch call
+func (e *EncryptedConnector) SetMany(records []map[string]interface{}) error {
+	keys, err := e.keys()
+	if err != nil {
+		return err
+	}
+	sealed := make([]map[string]interface{}, len(records))
+	for i, data := range records {
+		if sealed[i], err = e.seal(keys[0], data); err != nil {
+			return err
+		}
+	}
+	return SetMany(e.inner, sealed)
+}
+
+// Delete deletes a decision stored under any of the keys
+func (e *EncryptedConnector) Delete(featureKey string, userID string) error {
+	keys, err := e.keys()
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		if err := deleteFrom(e.inner, featureKey, key.hash(userID)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Scan calls fn with the decrypted decisions of userID, or of every user when userID is empty
+func (e *EncryptedConnector) Scan(ctx context.Context, userID string, fn func(data map[string]interface{}) error) error {
+	keys, err := e.keys()
+	if err != nil {
+		return err
+	}
+	visit := func(record map[string]interface{}) error {
+		decision, err := e.open(record, keys)
+		if err != nil {
+			return err
+		}
+		return fn(decision)
+	}
+
+	if userID == "" {
+		return scanFrom(ctx, e.inner, "", visit)
+	}
+	for _, key := range keys {
+		if err := scanFrom(ctx, e.inner, key.hash(userID), visit); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Unwrap returns the inner connector
+func (e *EncryptedConnector) Unwrap() Connector {
+	return e.inner
+}
+
+// HashUserID returns the user id as stored by the inner connector under the current key
+func (e *EncryptedConnector) HashUserID(userID string) (string, error) {
+	keys, err := e.keys()
+	if err != nil {
+		return "", err
+	}
+	return keys[0].hash(userID), nil
+}
+
+// seal returns the record stored for a decision
+func (e *EncryptedConnector) seal(key *derivedKey, data map[string]interface{}) (map[string]interface{}, error) {
+	featureKey, ok := data["featureKey"].(string)
+	if !ok {
+		return nil, fmt.Errorf("featureKey not found or not a string")
+	}
+	userID, ok := data["userId"].(string)
+	if !ok {
+		return nil, fmt.Errorf("userId not found or not a string")
+	}
+
+	plaintext, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	hashedID := key.hash(userID)
+	nonce := make([]byte, key.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	sealed := key.aead.Seal(nonce, nonce, plaintext, recordAAD(featureKey, hashedID))
+
+	return map[string]interface{}{
+		"featureKey": featureKey,
+		"userId":     hashedID,
+		FieldKeyID:   key.id,
+		FieldPayload: base64.StdEncoding.EncodeToString(sealed),
+	}, nil
+}
+
+// openAt decrypts a record read under featureKey and hashedID, rejecting a record copied from another key
+func (e *EncryptedConnector) openAt(record map[string]interface{}, keys []*derivedKey, featureKey string, hashedID string) (map[string]interface{}, error) {
+	if record["featureKey"] != featureKey || record["userId"] != hashedID {
+		return nil, fmt.Errorf("storage: encrypted record %s does not belong to its key", featureKey)
+	}
+	return e.open(record, keys)
+}
+
+// open decrypts a stored record with the key it names
+func (e *EncryptedConnector) open(record map[string]interface{}, keys []*derivedKey) (map[string]interface{}, error) {
+	featureKey, _ := record["featureKey"].(string)
+	hashedID, _ := record["userId"].(string)
+	keyID, _ := record[FieldKeyID].(string)
+	payload, _ := record[FieldPayload].(string)
+
+	var key *derivedKey
+	for _, k := range keys {
+		if k.id == keyID {
+			key = k
+			break
+		}
+	}
+	if key == nil {
+		return nil, fmt.Errorf("%w %q", ErrUnknownKey, keyID)
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(payload)
+	if err != nil || len(sealed) < key.aead.NonceSize() {
+		return nil, fmt.Errorf("storage: malformed encrypted record %s", featureKey)
+	}
+	nonceSize := key.aead.NonceSize()
+	plaintext, err := key.aead.Open(nil, sealed[:nonceSize], sealed[nonceSize:], recordAAD(featureKey, hashedID))
+	if err != nil {
+		return nil, fmt.Errorf("storage: decrypting record %s: %w", featureKey, err)
+	}
+
+	var decision map[string]interface{}
+	if err := json.Unmarshal(plaintext, &decision); err != nil {
+		return nil, err
+	}
+	if userID, _ := decision["userId"].(string); decision["featureKey"] != featureKey || key.hash(userID) != hashedID {
+		return nil, fmt.Errorf("storage: encrypted record %s does not match its payload", featureKey)
+	}
+	return decision, nil
+}
+
+// rotate rewrites a decision read with an older key under the current key and deletes the old record.
+// Failures are ignored: the decision stays readable through the older key.
+func (e *EncryptedConnector) rotate(current *derivedKey, decision map[string]interface{}, featureKey string, oldHashedID string) {
+	record, err := e.seal(current, decision)
+	if err != nil || e.inner.Set(record) != nil {
+		return
+	}
+	_ = deleteFrom(e.inner, featureKey, oldHashedID)
+}
+
+// keys returns the derived keys of the provider, current key first
+func (e *EncryptedConnector) keys() ([]*derivedKey, error) {
+	keys, err := e.provider.Keys()
+	if err != nil {
+		return nil, err
+	}
+	if len(keys) == 0 {
+		return nil, ErrNoKeys
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	derived := make([]*derivedKey, len(keys))
+	for i, key := range keys {
+		d, ok := e.derived[key.ID]
+		if !ok || !hmac.Equal(d.secret, key.Secret) {
+			if d, err = deriveKey(key); err != nil {
+				return nil, err
+			}
+			e.derived[key.ID] = d
+		}
+		derived[i] = d
+	}
+	return derived, nil
+}
+
+// deriveKey derives independent hashing and encryption keys from a Key
+func deriveKey(key Key) (*derivedKey, error) {
+	if key.ID == "" {
+		return nil, fmt.Errorf("storage: key id is required")
+	}
+	if len(key.Secret) < MinKeySize {
+		return nil, fmt.Errorf("storage: key %q is shorter than %d bytes", key.ID, MinKeySize)
+	}
+
+	block, err := aes.NewCipher(hmacSHA256(key.Secret, "vwo-fme-storage-encryption"))
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &derivedKey{
+		id:     key.ID,
+		secret: append([]byte(nil), key.Secret...),
+		mac:    hmacSHA256(key.Secret, "vwo-fme-storage-user-id"),
+		aead:   aead,
+	}, nil
+}
+
+// hash returns the keyed hash of a user id
+func (k *derivedKey) hash(userID string) string {
+	return hex.EncodeToString(hmacSHA256(k.mac, userID))
+}
+
+// hmacSHA256 returns the HMAC-SHA256 of message under key
+func hmacSHA256(key []byte, message string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(message))
+	return mac.Sum(nil)
+}
+
+// recordAAD binds a payload to its key so that records cannot be swapped between users or features
+func recordAAD(featureKey string, hashedID string) []byte {
+	return []byte(featureKey + ":" + hashedID)
+}
@@ -0,0 +1,153 @@
+/**
+ * Copyright 2025 Wingify Software Pvt. Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Export format identifiers written in the header line of an export
+const (
+	ExportFormat  = "vwo-fme-storage"
+	ExportVersion = 1
+)
+
+// ImportBatchSize is the number of decisions Import writes per SetMany call
+const ImportBatchSize = 500
+
+// ExportHeader is the first line of an export
+type ExportHeader struct {
+	Format     string    `json:"format"`
+	Version    int       `json:"version"`
+	ExportedAt time.Time `json:"exportedAt"`
+	UserID     string    `json:"userId,omitempty"`
+}
+
+// Export writes the stored decisions of userID, or of every user when userID is empty, to w as
+// JSON Lines: a header line followed by one decision per line. It returns the number of decisions written.
+func (a *Admin) Export(ctx context.Context, w io.Writer, userID string) (int, error) {
+	out := bufio.NewWriter(w)
+	encoder := json.NewEncoder(out)
+
+	header := ExportHeader{Format: ExportFormat, Version: ExportVersion, ExportedAt: time.Now().UTC(), UserID: userID}
+	if err := encoder.Encode(header); err != nil {
+		return 0, err
+	}
+
+	exported := 0
+	err := scanFrom(ctx, a.c, userID, func(data map[string]interface{}) error {
+		if err := encoder.Encode(data); err != nil {
+			return err
+		}
+		exported++
+		return nil
+	})
+	if err != nil {
+		return exported, err
+	}
+	return exported, out.Flush()
+}
+
+// Import reads an export from r and stores its decisions, ImportBatchSize at a time.
+// It returns the number of decisions stored.
+func (a *Admin) Import(ctx context.Context, r io.Reader) (int, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16<<20)
+
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return 0, err
+		}
+		return 0, fmt.Errorf("storage: empty export")
+	}
+	var header ExportHeader
+	if err := json.Unmarshal(scanner.Bytes(), &header); err != nil || header.Format != ExportFormat {
+		return 0, fmt.Errorf("storage: not a %s export", ExportFormat)
+	}
+	if header.Version > ExportVersion {
+		return 0, fmt.Errorf("storage: unsupported export version %d", header.Version)
+	}
+
+	imported := 0
+	batch := make([]map[string]interface{}, 0, ImportBatchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := SetMany(a.c, batch); err != nil {
+			return err
+		}
+		imported += len(batch)
+		batch = batch[:0]
+		return nil
+	}
+
+	for line := 2; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return imported, err
+		}
+		data, err := decodeDecision(scanner.Bytes())
+		if err != nil {
+			return imported, fmt.Errorf("storage: line %d: %w", line, err)
+		}
+		batch = append(batch, data)
+		if len(batch) == ImportBatchSize {
+			if err := flush(); err != nil {
+				return imported, err
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return imported, err
+	}
+	return imported, flush()
+}
+
+// decodeDecision parses an exported decision, keeping integral numbers as int like the SDK writes them
+func decodeDecision(line []byte) (map[string]interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(line))
+	decoder.UseNumber()
+	var data map[string]interface{}
+	if err := decoder.Decode(&data); err != nil {
+		return nil, err
+	}
+	if _, err := keyOf(data); err != nil {
+		return nil, err
+	}
+
+	for key, value := range data {
+		number, ok := value.(json.Number)
+		if !ok {
+			continue
+		}
+		if i, err := number.Int64(); err == nil {
+			data[key] = int(i)
+		} else if f, err := number.Float64(); err == nil {
+			data[key] = f
+		}
+	}
+	return data, nil
+}
@@ -0,0 +1,265 @@
+/**
+ * Copyright 2025 Wingify Software Pvt. Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package filestore
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// headerSize is the size of the length, payload checksum and header checksum preceding every record
+const headerSize = 12
+
+// maxRecordSize bounds the length read from a header so that a corrupt header cannot trigger a huge allocation
+const maxRecordSize = 16 << 20
+
+// compactSuffix is appended to the path of the file written during compaction
+const compactSuffix = ".compact"
+
+// record is a decision as written to the file; a record without a value deletes the decision
+type record struct {
+	Key     string          `json:"k"`
+	Value   json.RawMessage `json:"v"`
+	Updated int64           `json:"t"`
+}
+
+// isTombstone returns whether a record deletes its decision
+func isTombstone(r record) bool {
+	return len(r.Value) == 0 || string(r.Value) == "null"
+}
+
+// encodeRecord frames a record as length, CRC-32 checksum of the payload, CRC-32 checksum of the
+// first two fields and JSON payload
+func encodeRecord(r record) ([]byte, error) {
+	payload, err := json.Marshal(r)
+	if err != nil {
+		return nil, err
+	}
+	frame := make([]byte, headerSize+len(payload))
+	binary.BigEndian.PutUint32(frame[0:4], uint32(len(payload)))
+	binary.BigEndian.PutUint32(frame[4:8], crc32.ChecksumIEEE(payload))
+	binary.BigEndian.PutUint32(frame[8:12], crc32.ChecksumIEEE(frame[0:8]))
+	copy(frame[headerSize:], payload)
+	return frame, nil
+}
+
+// decodeRecords returns the valid records of data, the number of complete but corrupt records it
+// skipped and the offset after the last complete record. Decoding stops at an incomplete final
+// record, which a crash may leave behind, and fails on a damaged header, as the records after it
+// cannot be located.
+func decodeRecords(data []byte) ([]record, int, int, error) {
+	var records []record
+	skipped := 0
+	offset := 0
+	for len(data)-offset >= headerSize {
+		header := data[offset : offset+headerSize]
+		if crc32.ChecksumIEEE(header[0:8]) != binary.BigEndian.Uint32(header[8:12]) {
+			return nil, 0, 0, fmt.Errorf("%w: bad record header at offset %d", ErrCorrupt, offset)
+		}
+		length := int(binary.BigEndian.Uint32(header[0:4]))
+		checksum := binary.BigEndian.Uint32(header[4:8])
+		if length > maxRecordSize {
+			return nil, 0, 0, fmt.Errorf("%w: record length %d at offset %d", ErrCorrupt, length, offset)
+		}
+		// a valid header whose payload runs past the end can only be the last write, torn by a crash
+		if len(data)-offset-headerSize < length {
+			break
+		}
+
+		payload := data[offset+headerSize : offset+headerSize+length]
+		offset += headerSize + length
+
+		var r record
+		if crc32.ChecksumIEEE(payload) != checksum || json.Unmarshal(payload, &r) != nil {
+			skipped++
+			continue
+		}
+		records = append(records, r)
+	}
+	return records, skipped, offset, nil
+}
+
+// load opens the file, rebuilds the index and truncates a record torn by a crash
+func (s *Store) load() error {
+	// a leftover compaction file belongs to a compaction that never completed
+	_ = os.Remove(s.path + compactSuffix)
+
+	file, err := os.OpenFile(s.path, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	records, skipped, offset, err := decodeRecords(data)
+	if err != nil {
+		file.Close()
+		return err
+	}
+	if offset < len(data) {
+		if err := file.Truncate(int64(offset)); err != nil {
+			file.Close()
+			return err
+		}
+		if err := file.Sync(); err != nil {
+			file.Close()
+			return err
+		}
+	}
+	if _, err := file.Seek(int64(offset), io.SeekStart); err != nil {
+		file.Close()
+		return err
+	}
+
+	now := time.Now()
+	for _, r := range records {
+		if isTombstone(r) {
+			delete(s.entries, r.Key)
+			continue
+		}
+		e := entry{payload: r.Value, updated: time.Unix(0, r.Updated)}
+		if s.isStale(e, now) {
+			delete(s.entries, r.Key)
+			continue
+		}
+		s.entries[r.Key] = e
+	}
+
+	s.file = file
+	// skipped records count as garbage so that the next compaction removes them
+	s.records = len(records) + skipped
+	return nil
+}
+
+// append writes a record at the end of the file, rolling back a partial write
+func (s *Store) append(r record) error {
+	frame, err := encodeRecord(r)
+	if err != nil {
+		return err
+	}
+
+	offset, err := s.file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	if _, err := s.file.Write(frame); err != nil {
+		if s.file.Truncate(offset) == nil {
+			_, _ = s.file.Seek(offset, io.SeekStart)
+		}
+		return err
+	}
+	if !s.opts.NoSync {
+		if err := s.file.Sync(); err != nil {
+			return err
+		}
+	}
+	s.records++
+	return nil
+}
+
+// compact writes the live decisions to a new file and renames it over the current one
+func (s *Store) compact() error {
+	keys := make([]string, 0, len(s.entries))
+	for key := range s.entries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var data []byte
+	for _, key := range keys {
+		e := s.entries[key]
+		frame, err := encodeRecord(record{Key: key, Value: e.payload, Updated: e.updated.UnixNano()})
+		if err != nil {
+			return err
+		}
+		data = append(data, frame...)
+	}
+
+	tmpPath := s.path + compactSuffix
+	if err := writeFileSync(tmpPath, data); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+
+	// the current file is closed before the rename as some platforms cannot replace an open file
+	if err := s.file.Close(); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	renameErr := os.Rename(tmpPath, s.path)
+	if renameErr == nil {
+		syncDir(filepath.Dir(s.path))
+	} else {
+		os.Remove(tmpPath)
+	}
+
+	// without a file to append to the store cannot continue, so a failed reopen closes it
+	file, err := os.OpenFile(s.path, os.O_RDWR, 0600)
+	if err == nil {
+		if _, err = file.Seek(0, io.SeekEnd); err != nil {
+			file.Close()
+		}
+	}
+	if err != nil {
+		s.file = nil
+		s.closed = true
+		return fmt.Errorf("filestore: reopening after compaction: %w", err)
+	}
+	s.file = file
+
+	if renameErr != nil {
+		return renameErr
+	}
+	s.records = len(keys)
+	return nil
+}
+
+// writeFileSync writes data to a new file and flushes it to disk
+func writeFileSync(path string, data []byte) error {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// syncDir flushes a directory entry so that a rename survives a crash; not every platform supports it
+func syncDir(dir string) {
+	if d, err := os.Open(dir); err == nil {
+		_ = d.Sync()
+		d.Close()
+	}
+}
@@ -0,0 +1,305 @@
+/**
+ * Copyright 2025 Wingify Software Pvt. Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package filestore provides a storage connector persisted in a single local file.
+//
+// Decisions are appended to the file as checksummed records and indexed in memory.
+// A record torn by a crash is detected on open and truncated, so the file always
+// reflects the last completed write. A complete record with a bad checksum is skipped
+// and removed by the next compaction. Compact rewrites the live decisions to a new
+// file and atomically replaces the old one.
+//
+// A file must only be opened by one Store at a time.
+package filestore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Errors of a Store
+var (
+	// ErrClosed is returned by operations on a closed store
+	ErrClosed = errors.New("filestore: store is closed")
+
+	// ErrCorrupt is returned by Open when a record header fails its checksum, so the records after it cannot be located
+	ErrCorrupt = errors.New("filestore: corrupt file")
+)
+
+// Options configures the store
+type Options struct {
+	// MaxAge evicts decisions not written for longer than this; zero keeps them forever
+	MaxAge time.Duration
+
+	// CompactInterval evicts stale decisions and compacts the file periodically; zero disables it
+	CompactInterval time.Duration
+
+	// NoSync skips the fsync after every write, trading durability for throughput
+	NoSync bool
+}
+
+// Store implements storage.Connector on top of an append-only file
+type Store struct {
+	mu      sync.Mutex
+	path    string
+	opts    Options
+	file    *os.File
+	entries map[string]entry
+	records int
+	closed  bool
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// entry is the in-memory index of a stored decision
+type entry struct {
+	payload json.RawMessage
+	updated time.Time
+}
+
+// Open opens the store at path, creating the file if needed
+func Open(path string, opts Options) (*Store, error) {
+	s := &Store{
+		path:    path,
+		opts:    opts,
+		entries: make(map[string]entry),
+	}
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+
+	if opts.CompactInterval > 0 {
+		s.stop = make(chan struct{})
+		s.done = make(chan struct{})
+		go s.compactLoop()
+	}
+	return s, nil
+}
+
+// Set stores a decision
+func (s *Store) Set(data map[string]interface{}) error {
+	featureKey, ok := data["featureKey"].(string)
+	if !ok {
+		return fmt.Errorf("featureKey not found or not a string")
+	}
+	userID, ok := data["userId"].(string)
+	if !ok {
+		return fmt.Errorf("userId not found or not a string")
+	}
+
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return ErrClosed
+	}
+
+	r := record{Key: featureKey + ":" + userID, Value: payload, Updated: time.Now().UnixNano()}
+	if err := s.append(r); err != nil {
+		return err
+	}
+	s.entries[r.Key] = entry{payload: payload, updated: time.Unix(0, r.Updated)}
+	return nil
+}
+
+// Get retrieves a decision, returning nil when none is stored or it is stale
+func (s *Store) Get(featureKey string, userID string) (interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil, ErrClosed
+	}
+
+	key := featureKey + ":" + userID
+	e, exists := s.entries[key]
+	if !exists {
+		return nil, nil
+	}
+	if s.isStale(e, time.Now()) {
+		delete(s.entries, key)
+		return nil, nil
+	}
+
+	var data map[string]interface{}
+	if err := json.Unmarshal(e.payload, &data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// Delete removes a decision. The file keeps the deleted data until the next compaction.
+func (s *Store) Delete(featureKey string, userID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return ErrClosed
+	}
+
+	key := featureKey + ":" + userID
+	if _, exists := s.entries[key]; !exists {
+		return nil
+	}
+	if err := s.append(record{Key: key, Updated: time.Now().UnixNano()}); err != nil {
+		return err
+	}
+	delete(s.entries, key)
+	return nil
+}
+
+// Scan calls fn for every live decision of userID, or of every user when userID is empty, in key order
+func (s *Store) Scan(ctx context.Context, userID string, fn func(data map[string]interface{}) error) error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return ErrClosed
+	}
+	now := time.Now()
+	keys := make([]string, 0, len(s.entries))
+	payloads := make(map[string]json.RawMessage, len(s.entries))
+	for key, e := range s.entries {
+		if userID != "" && !strings.HasSuffix(key, ":"+userID) || s.isStale(e, now) {
+			continue
+		}
+		keys = append(keys, key)
+		payloads[key] = e.payload
+	}
+	s.mu.Unlock()
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		var data map[string]interface{}
+		if err := json.Unmarshal(payloads[key], &data); err != nil {
+			return err
+		}
+		// a suffix match alone would also accept a user whose id ends with ":"+userID
+		if userID != "" && data["userId"] != userID {
+			continue
+		}
+		if err := fn(data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Evict drops stale decisions from the index and returns how many were dropped.
+// Their records stay in the file until the next compaction but are skipped on open.
+func (s *Store) Evict() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.evict(time.Now())
+}
+
+// Compact evicts stale decisions and rewrites the file with only the live ones
+func (s *Store) Compact() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return ErrClosed
+	}
+	s.evict(time.Now())
+	return s.compact()
+}
+
+// Len returns the number of decisions held by the store
+func (s *Store) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.entries)
+}
+
+// Garbage returns the number of records in the file that compaction would remove
+func (s *Store) Garbage() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.records - len(s.entries)
+}
+
+// Close stops the background compaction and closes the file
+func (s *Store) Close() error {
+	s.stopOnce.Do(func() {
+		if s.stop != nil {
+			close(s.stop)
+			<-s.done
+		}
+	})
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	// the file is already gone when Close runs twice or a compaction failed to reopen it
+	if s.file == nil {
+		return nil
+	}
+	err := s.file.Close()
+	s.file = nil
+	return err
+}
+
+// compactLoop periodically evicts stale decisions and compacts the file when it holds garbage
+func (s *Store) compactLoop() {
+	defer close(s.done)
+	ticker := time.NewTicker(s.opts.CompactInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			s.mu.Lock()
+			s.evict(time.Now())
+			if s.records > len(s.entries) {
+				// a failed compaction leaves the current file in place and is retried on the next tick
+				_ = s.compact()
+			}
+			s.mu.Unlock()
+		}
+	}
+}
+
+// evict drops stale decisions from the index
+func (s *Store) evict(now time.Time) int {
+	evicted := 0
+	for key, e := range s.entries {
+		if s.isStale(e, now) {
+			delete(s.entries, key)
+			evicted++
+		}
+	}
+	return evicted
+}
+
+// isStale returns whether a decision is older than MaxAge
+func (s *Store) isStale(e entry, now time.Time) bool {
+	return s.opts.MaxAge > 0 && now.Sub(e.updated) > s.opts.MaxAge
+}
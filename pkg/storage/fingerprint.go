@@ -0,0 +1,116 @@
+/**
+ * Copyright 2025 Wingify Software Pvt. Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package storage
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// campaignStatusRunning is the status of a campaign that still serves traffic
+const campaignStatusRunning = "RUNNING"
+
+// settingsIndex is the part of the settings needed to check stored decisions
+type settingsIndex struct {
+	features  map[int]bool
+	campaigns map[string]campaignIndex
+}
+
+// campaignIndex describes one campaign of the settings
+type campaignIndex struct {
+	active      bool
+	variations  map[int]bool
+	fingerprint string
+}
+
+// settingsDocument is the subset of the settings JSON read by newSettingsIndex
+type settingsDocument struct {
+	Features []struct {
+		ID int `json:"id"`
+	} `json:"features"`
+	Campaigns []struct {
+		ID         int    `json:"id"`
+		Key        string `json:"key"`
+		Type       string `json:"type"`
+		Status     string `json:"status"`
+		Variations []struct {
+			ID int `json:"id"`
+		} `json:"variations"`
+	} `json:"campaigns"`
+}
+
+// newSettingsIndex parses the settings JSON
+func newSettingsIndex(raw string) (*settingsIndex, error) {
+	var doc settingsDocument
+	if err := json.Unmarshal([]byte(raw), &doc); err != nil {
+		return nil, err
+	}
+
+	index := &settingsIndex{
+		features:  make(map[int]bool, len(doc.Features)),
+		campaigns: make(map[string]campaignIndex, len(doc.Campaigns)),
+	}
+	for _, feature := range doc.Features {
+		index.features[feature.ID] = true
+	}
+	for _, c := range doc.Campaigns {
+		ids := make([]int, len(c.Variations))
+		variations := make(map[int]bool, len(c.Variations))
+		for i, variation := range c.Variations {
+			ids[i] = variation.ID
+			variations[variation.ID] = true
+		}
+		index.campaigns[c.Key] = campaignIndex{
+			// settings without a status only list campaigns that are running
+			active:      c.Status == "" || strings.EqualFold(c.Status, campaignStatusRunning),
+			variations:  variations,
+			fingerprint: campaignFingerprint(c.ID, c.Key, c.Type, ids),
+		}
+	}
+	return index, nil
+}
+
+// campaignFingerprint identifies a campaign and its set of variations; traffic and weights are
+// left out so that re-balancing a campaign does not invalidate the users already bucketed
+func campaignFingerprint(id int, key string, campaignType string, variationIDs []int) string {
+	sort.Ints(variationIDs)
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%d|%s|%s|%v", id, key, campaignType, variationIDs)))
+	return hex.EncodeToString(sum[:8])
+}
+
+// toInt converts a number read back from a connector, where JSON backends return float64
+func toInt(value interface{}) int {
+	switch n := value.(type) {
+	case int:
+		return n
+	case int32:
+		return int(n)
+	case int64:
+		return int(n)
+	case float64:
+		return int(n)
+	case json.Number:
+		i, _ := n.Int64()
+		return int(i)
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,348 @@
+/**
+ * Copyright 2025 Wingify Software Pvt. Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package storage
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/wingify/wingify-fme-go-sdk/pkg/models"
+)
+
+// Errors reported for calls that did not complete
+var (
+	ErrCircuitOpen = errors.New("storage: circuit breaker is open")
+	ErrTimeout     = errors.New("storage: call timed out")
+)
+
+// FailureMode decides what a failed storage call means for flag evaluation
+type FailureMode int
+
+// Failure modes
+const (
+	// FailOpen treats a failed lookup as "no stored decision" so the flag is evaluated afresh
+	FailOpen FailureMode = iota
+
+	// FailClosed reports failures to the SDK and makes Guard return a disabled flag when a lookup
+	// for the user fails during GetFlag or while the breaker is open
+	FailClosed
+)
+
+// Logger receives the messages of a ResilientConnector; *log.Logger satisfies it
+type Logger interface {
+	Printf(format string, v ...interface{})
+}
+
+// FlagClient is the subset of VWOClient wrapped by Guard
+type FlagClient interface {
+	GetFlag(featureKey string, context map[string]interface{}) (models.GetFlagResponse, error)
+}
+
+// Policy configures a ResilientConnector
+type Policy struct {
+	// Mode decides how failures affect flag evaluation
+	Mode FailureMode
+
+	// Timeout bounds every call to the inner connector; zero disables the timeout
+	Timeout time.Duration
+
+	// Breaker configures the circuit breaker
+	Breaker BreakerOptions
+
+	// Logger receives failures and breaker state changes
+	Logger Logger
+
+	// OnStateChange is called when the breaker changes state; it must not block
+	OnStateChange func(from BreakerState, to BreakerState)
+}
+
+// ResilienceStats holds the counters of a ResilientConnector
+type ResilienceStats struct {
+	// State is the current breaker state
+	State BreakerState
+
+	// Successes counts calls completed by the inner connector
+	Successes uint64
+
+	// Failures counts calls that returned an error, including timeouts
+	Failures uint64
+
+	// Timeouts counts calls abandoned after Policy.Timeout
+	Timeouts uint64
+
+	// ShortCircuits counts calls rejected by the open breaker
+	ShortCircuits uint64
+
+	// Opens counts how many times the breaker opened
+	Opens uint64
+}
+
+// ResilientConnector applies a timeout, a circuit breaker and a failure policy to another connector
+type ResilientConnector struct {
+	// counters are accessed atomically and kept first for 64-bit alignment
+	successes, failures, timeouts, shortCircuits, opens uint64
+
+	inner   Connector
+	policy  Policy
+	breaker *circuitBreaker
+
+	// guarded tracks the users with a guarded GetFlag in flight, keyed by user id
+	mu      sync.Mutex
+	guarded map[string]*guardedCall
+}
+
+// guardedCall records whether a lookup failed while guarded GetFlag calls for a user were running
+type guardedCall struct {
+	running int
+	failed  bool
+}
+
+// Resilient wraps inner with the given failure policy
+func Resilient(inner Connector, policy Policy) *ResilientConnector {
+	r := &ResilientConnector{inner: inner, policy: policy, guarded: map[string]*guardedCall{}}
+	r.breaker = newCircuitBreaker(policy.Breaker, r.stateChanged)
+	return r
+}
+
+// Get retrieves a decision from the inner connector
+func (r *ResilientConnector) Get(featureKey string, userID string) (interface{}, error) {
+	var result interface{}
+	err := r.call("get", func() error {
+		var err error
+		result, err = r.inner.Get(featureKey, userID)
+		return err
+	})
+	if err != nil {
+		if r.policy.Mode == FailOpen {
+			return nil, nil
+		}
+		r.lookupFailed(userID)
+		return nil, err
+	}
+	return result, nil
+}
+
+// Set stores a decision in the inner connector
+func (r *ResilientConnector) Set(data map[string]interface{}) error {
+	err := r.call("set", func() error {
+		return r.inner.Set(data)
+	})
+	if err != nil && r.policy.Mode == FailOpen {
+		return nil
+	}
+	return err
+}
+
+// GetMany retrieves several decisions of a user from the inner connector as one call
+func (r *ResilientConnector) GetMany(userID string, featureKeys []string) (map[string]map[string]interface{}, error) {
+	var result map[string]map[string]interface{}
+	err := r.call("get", func() error {
+		var err error
+		result, err = GetMany(r.inner, userID, featureKeys)
+		return err
+	})
+	if err != nil {
+		if r.policy.Mode == FailOpen {
+			return map[string]map[string]interface{}{}, nil
+		}
+		r.lookupFailed(userID)
+		return nil, err
+	}
+	return result, nil
+}
+
+// SetMany stores several decisions in the inner connector as one call
+func (r *ResilientConnector) SetMany(records []map[string]interface{}) error {
+	err := r.call("set", func() error {
+		return SetMany(r.inner, records)
+	})
+	if err != nil && r.policy.Mode == FailOpen {
+		return nil
+	}
+	return err
+}
+
+// Delete deletes a decision from the inner connector. Failures are returned in both modes
+// so that an erasure is never reported as done when it was not.
+func (r *ResilientConnector) Delete(featureKey string, userID string) error {
+	return r.call("delete", func() error {
+		return deleteFrom(r.inner, featureKey, userID)
+	})
+}
+
+// Scan enumerates the decisions of the inner connector without the timeout or the breaker
+func (r *ResilientConnector) Scan(ctx context.Context, userID string, fn func(data map[string]interface{}) error) error {
+	return scanFrom(ctx, r.inner, userID, fn)
+}
+
+// Unwrap returns the inner connector
+func (r *ResilientConnector) Unwrap() Connector {
+	return r.inner
+}
+
+// State returns the current breaker state
+func (r *ResilientConnector) State() BreakerState {
+	return r.breaker.current()
+}
+
+// Stats returns a snapshot of the counters
+func (r *ResilientConnector) Stats() ResilienceStats {
+	return ResilienceStats{
+		State:         r.breaker.current(),
+		Successes:     atomic.LoadUint64(&r.successes),
+		Failures:      atomic.LoadUint64(&r.failures),
+		Timeouts:      atomic.LoadUint64(&r.timeouts),
+		ShortCircuits: atomic.LoadUint64(&r.shortCircuits),
+		Opens:         atomic.LoadUint64(&r.opens),
+	}
+}
+
+// Guard wraps client so that, in FailClosed mode, GetFlag returns a disabled flag without
+// evaluating while the breaker rejects calls, and discards the evaluation when a lookup for
+// the user fails while it runs. In FailOpen mode client is returned unchanged.
+func (r *ResilientConnector) Guard(client FlagClient) FlagClient {
+	if r.policy.Mode != FailClosed {
+		return client
+	}
+	return &guardedClient{client: client, connector: r}
+}
+
+// call runs fn through the breaker and the timeout
+func (r *ResilientConnector) call(operation string, fn func() error) error {
+	if !r.breaker.allow() {
+		atomic.AddUint64(&r.shortCircuits, 1)
+		return ErrCircuitOpen
+	}
+
+	err := r.withTimeout(fn)
+	r.breaker.record(err == nil)
+	if err != nil {
+		atomic.AddUint64(&r.failures, 1)
+		r.logf("storage %s failed: %v", operation, err)
+		return err
+	}
+	atomic.AddUint64(&r.successes, 1)
+	return nil
+}
+
+// withTimeout runs fn, abandoning it after Policy.Timeout.
+// An abandoned call keeps running in the background until the inner connector returns.
+func (r *ResilientConnector) withTimeout(fn func() error) error {
+	if r.policy.Timeout <= 0 {
+		return fn()
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- fn()
+	}()
+
+	timer := time.NewTimer(r.policy.Timeout)
+	defer timer.Stop()
+	select {
+	case err := <-done:
+		return err
+	case <-timer.C:
+		atomic.AddUint64(&r.timeouts, 1)
+		return ErrTimeout
+	}
+}
+
+// stateChanged counts openings, logs the change and notifies the listener
+func (r *ResilientConnector) stateChanged(from BreakerState, to BreakerState) {
+	if to == BreakerOpen {
+		atomic.AddUint64(&r.opens, 1)
+	}
+	r.logf("storage circuit breaker %s -> %s", from, to)
+	if r.policy.OnStateChange != nil {
+		r.policy.OnStateChange(from, to)
+	}
+}
+
+// beginGuarded registers a guarded GetFlag for userID
+func (r *ResilientConnector) beginGuarded(userID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	call, ok := r.guarded[userID]
+	if !ok {
+		call = &guardedCall{}
+		r.guarded[userID] = call
+	}
+	call.running++
+}
+
+// endGuarded unregisters a guarded GetFlag for userID and returns whether a lookup failed while it ran
+func (r *ResilientConnector) endGuarded(userID string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	call := r.guarded[userID]
+	failed := call.failed
+	call.running--
+	if call.running == 0 {
+		delete(r.guarded, userID)
+	}
+	return failed
+}
+
+// lookupFailed marks the guarded GetFlag calls of userID, if any, as failed
+func (r *ResilientConnector) lookupFailed(userID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if call, ok := r.guarded[userID]; ok {
+		call.failed = true
+	}
+}
+
+// logf writes to the configured logger
+func (r *ResilientConnector) logf(format string, v ...interface{}) {
+	if r.policy.Logger != nil {
+		r.policy.Logger.Printf(format, v...)
+	}
+}
+
+// guardedClient returns disabled flags while the storage breaker rejects calls or a lookup fails
+type guardedClient struct {
+	client    FlagClient
+	connector *ResilientConnector
+}
+
+// GetFlag evaluates the flag unless the storage is known to be down
+func (g *guardedClient) GetFlag(featureKey string, context map[string]interface{}) (models.GetFlagResponse, error) {
+	if g.connector.breaker.rejecting() {
+		return disabledFlag(), nil
+	}
+
+	// the SDK looks up stored decisions by the string id of the context
+	userID, _ := context["id"].(string)
+	g.connector.beginGuarded(userID)
+	flag, err := g.client.GetFlag(featureKey, context)
+	if g.connector.endGuarded(userID) {
+		return disabledFlag(), nil
+	}
+	return flag, err
+}
+
+// disabledFlag returns the flag reported when the storage cannot be trusted
+func disabledFlag() models.GetFlagResponse {
+	return models.NewGetFlag(false, []*models.Variable{}, "", 0)
+}
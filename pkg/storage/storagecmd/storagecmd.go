@@ -0,0 +1,179 @@
+/**
+ * Copyright 2025 Wingify Software Pvt. Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package storagecmd implements the commands of the vwo-storage tool.
+//
+// The commands work on any connector that implements storage.Scanner and, for
+// forget, storage.Deleter. A binary supplies an Opener for its backends and calls Main.
+package storagecmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+
+	"github.com/wingify/vwo-fme-go-sdk/pkg/storage"
+)
+
+// ErrUsage is returned when the arguments are invalid; the usage has already been printed
+var ErrUsage = errors.New("invalid usage")
+
+// Opener opens the connector named by the -store flag; close releases it
+type Opener func(store string) (connector storage.Connector, close func() error, err error)
+
+// usage describes the commands
+const usage = `usage: %[1]s <command> [flags]
+
+commands:
+  list   -store STORE -user ID              print the stored decisions of a user
+  export -store STORE [-user ID] [-out FILE] write decisions as JSON Lines
+  import -store STORE [-in FILE]            store the decisions of an export
+  forget -store STORE -user ID              delete every stored decision of a user
+`
+
+// Main runs the command of os.Args and exits with a non-zero status on failure
+func Main(open Opener) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	err := Run(ctx, os.Args[1:], os.Stdin, os.Stdout, os.Stderr, open)
+	stop()
+
+	switch {
+	case errors.Is(err, ErrUsage):
+		os.Exit(2)
+	case err != nil:
+		fmt.Fprintf(os.Stderr, "%s: %v\n", os.Args[0], err)
+		os.Exit(1)
+	}
+}
+
+// Run runs one command; stdin and stdout replace the files of import and export when none are given
+func Run(ctx context.Context, args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer, open Opener) error {
+	name := "vwo-storage"
+	if len(args) == 0 {
+		fmt.Fprintf(stderr, usage, name)
+		return ErrUsage
+	}
+
+	command := args[0]
+	flags := flag.NewFlagSet(name+" "+command, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	store := flags.String("store", "", "storage to operate on")
+	userID := flags.String("user", "", "user id")
+	var in, out *string
+	switch command {
+	case "export":
+		out = flags.String("out", "", "file to write, stdout when empty")
+	case "import":
+		in = flags.String("in", "", "file to read, stdin when empty")
+	case "list", "forget":
+	default:
+		fmt.Fprintf(stderr, usage, name)
+		return ErrUsage
+	}
+	if err := flags.Parse(args[1:]); err != nil {
+		return ErrUsage
+	}
+	if *store == "" || (command == "list" || command == "forget") && *userID == "" {
+		fmt.Fprintf(stderr, "%s: -store is required, and -user for %s\n", flags.Name(), command)
+		return ErrUsage
+	}
+
+	connector, closeStore, err := open(*store)
+	if err != nil {
+		return err
+	}
+	admin := storage.NewAdmin(connector)
+
+	switch command {
+	case "list":
+		err = list(ctx, admin, *userID, stdout)
+	case "export":
+		err = export(ctx, admin, *userID, *out, stdout, stderr)
+	case "import":
+		err = importFrom(ctx, admin, *in, stdin, stderr)
+	case "forget":
+		var deleted int
+		deleted, err = admin.ForgetUser(ctx, *userID)
+		fmt.Fprintf(stderr, "deleted %d decisions of %s\n", deleted, *userID)
+	}
+
+	if closeErr := closeStore(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// list prints the decisions of a user, one JSON object per line
+func list(ctx context.Context, admin *storage.Admin, userID string, stdout io.Writer) error {
+	decisions, err := admin.ListUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(stdout)
+	for _, data := range decisions {
+		if err := encoder.Encode(data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// export writes an export to path, or to stdout when path is empty
+func export(ctx context.Context, admin *storage.Admin, userID string, path string, stdout io.Writer, stderr io.Writer) error {
+	w := stdout
+	var file *os.File
+	if path != "" {
+		var err error
+		if file, err = os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600); err != nil {
+			return err
+		}
+		w = file
+	}
+
+	exported, err := admin.Export(ctx, w, userID)
+	if file != nil {
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(stderr, "exported %d decisions\n", exported)
+	return nil
+}
+
+// importFrom stores the decisions of the export at path, or of stdin when path is empty
+func importFrom(ctx context.Context, admin *storage.Admin, path string, stdin io.Reader, stderr io.Writer) error {
+	r := stdin
+	if path != "" {
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		r = file
+	}
+
+	imported, err := admin.Import(ctx, r)
+	fmt.Fprintf(stderr, "imported %d decisions\n", imported)
+	return err
+}
@@ -0,0 +1,390 @@
+/**
+ * Copyright 2025 Wingify Software Pvt. Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package storage
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RecordSchemaVersion is the schema version stamped on decisions written through a VersionedConnector
+const RecordSchemaVersion = 1
+
+// Fields added to stored decisions by a VersionedConnector
+const (
+	FieldSchemaVersion = "schemaVersion"
+	FieldFingerprint   = "fingerprint"
+)
+
+// DefaultReportSize is the number of invalidated records kept when VersionOptions.ReportSize is not set
+const DefaultReportSize = 100
+
+// StalePolicy decides what a VersionedConnector does with a stored decision that no longer matches the settings
+type StalePolicy int
+
+// Stale policies
+const (
+	// KeepStale returns the decision unchanged; the SDK still ignores variations it cannot find
+	KeepStale StalePolicy = iota
+
+	// ReevaluateStale hides the decision so the flag is evaluated afresh and the new decision overwrites it
+	ReevaluateStale
+
+	// DropStale hides the decision and deletes it when the inner connector is a Deleter
+	DropStale
+)
+
+// String returns the name of the policy
+func (p StalePolicy) String() string {
+	switch p {
+	case KeepStale:
+		return "keep"
+	case ReevaluateStale:
+		return "re-evaluate"
+	case DropStale:
+		return "drop"
+	default:
+		return "unknown"
+	}
+}
+
+// StaleReason explains why a stored decision was invalidated
+type StaleReason string
+
+// Stale reasons
+const (
+	ReasonFeatureRemoved   StaleReason = "feature-removed"
+	ReasonCampaignRemoved  StaleReason = "campaign-removed"
+	ReasonCampaignInactive StaleReason = "campaign-inactive"
+	ReasonVariationRemoved StaleReason = "variation-removed"
+	ReasonCampaignChanged  StaleReason = "campaign-changed"
+)
+
+// Deleter is an optional extension of Connector for backends that can delete a stored decision
+type Deleter interface {
+	Delete(featureKey string, userID string) error
+}
+
+// SettingsSource provides the current settings JSON; VWOClient satisfies it
+type SettingsSource interface {
+	GetOriginalSettings() string
+}
+
+// StaticSettings is a SettingsSource for a fixed settings JSON
+type StaticSettings string
+
+// GetOriginalSettings returns the settings JSON
+func (s StaticSettings) GetOriginalSettings() string {
+	return string(s)
+}
+
+// VersionOptions configures a VersionedConnector
+type VersionOptions struct {
+	// Settings provides the settings decisions are checked against; it can also be set later with UseSettings
+	Settings SettingsSource
+
+	// Policy applies to decisions whose feature, campaign or variation no longer exists or whose campaign is not running
+	Policy StalePolicy
+
+	// ChangedPolicy applies to decisions whose campaign still exists but whose variations changed since they were stored
+	ChangedPolicy StalePolicy
+
+	// ReportSize is the number of invalidated records kept by Report
+	ReportSize int
+
+	// OnInvalidated is called for every invalidated decision; it must not block
+	OnInvalidated func(record InvalidatedRecord)
+}
+
+// InvalidatedRecord describes a stored decision that no longer matched the settings
+type InvalidatedRecord struct {
+	FeatureKey    string
+	UserID        string
+	CampaignKey   string
+	VariationID   int
+	SchemaVersion int
+	Reason        StaleReason
+	Action        StalePolicy
+	At            time.Time
+}
+
+// VersionReport summarises the decisions checked by a VersionedConnector
+type VersionReport struct {
+	// Checked counts decisions checked against the settings
+	Checked uint64
+
+	// Legacy counts checked decisions stored without a schema version
+	Legacy uint64
+
+	// Invalidated counts invalidated decisions by reason
+	Invalidated map[StaleReason]uint64
+
+	// Dropped counts invalidated decisions deleted from the inner connector
+	Dropped uint64
+
+	// Records holds the most recent invalidated decisions, oldest first
+	Records []InvalidatedRecord
+}
+
+// VersionedConnector stamps stored decisions with a schema version and a campaign fingerprint,
+// and checks them against the current settings when they are read back
+type VersionedConnector struct {
+	inner Connector
+	opts  VersionOptions
+
+	mu       sync.Mutex
+	settings SettingsSource
+	raw      string
+	index    *settingsIndex
+	report   VersionReport
+}
+
+// Versioned wraps inner so that decisions are checked against the settings when read
+func Versioned(inner Connector, opts VersionOptions) *VersionedConnector {
+	if opts.ReportSize <= 0 {
+		opts.ReportSize = DefaultReportSize
+	}
+	return &VersionedConnector{
+		inner:    inner,
+		opts:     opts,
+		settings: opts.Settings,
+		report:   VersionReport{Invalidated: make(map[StaleReason]uint64)},
+	}
+}
+
+// Delete deletes a decision from the inner connector
+func (v *VersionedConnector) Delete(featureKey string, userID string) error {
+	return deleteFrom(v.inner, featureKey, userID)
+}
+
+// Scan enumerates the decisions of the inner connector as stored, without applying the stale policies
+func (v *VersionedConnector) Scan(ctx context.Context, userID string, fn func(data map[string]interface{}) error) error {
+	return scanFrom(ctx, v.inner, userID, fn)
+}
+
+// Unwrap returns the inner connector
+func (v *VersionedConnector) Unwrap() Connector {
+	return v.inner
+}
+
+// UseSettings sets the source of the settings decisions are checked against.
+// It is typically called with the client returned by Init, which needs the connector first.
+func (v *VersionedConnector) UseSettings(source SettingsSource) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.settings = source
+}
+
+// Get reads a decision from the inner connector and applies the stale policies to it
+func (v *VersionedConnector) Get(featureKey string, userID string) (interface{}, error) {
+	result, err := v.inner.Get(featureKey, userID)
+	if err != nil {
+		return nil, err
+	}
+	decision, ok := result.(map[string]interface{})
+	if !ok {
+		return result, nil
+	}
+	if decision = v.check(featureKey, userID, decision); decision == nil {
+		return nil, nil
+	}
+	return decision, nil
+}
+
+// Set stamps the decision and writes it to the inner connector
+func (v *VersionedConnector) Set(data map[string]interface{}) error {
+	return v.inner.Set(v.stamp(data, v.currentIndex()))
+}
+
+// GetMany reads several decisions of a user and applies the stale policies to each of them
+func (v *VersionedConnector) GetMany(userID string, featureKeys []string) (map[string]map[string]interface{}, error) {
+	decisions, err := GetMany(v.inner, userID, featureKeys)
+	if err != nil {
+		return nil, err
+	}
+	for featureKey, decision := range decisions {
+		if checked := v.check(featureKey, userID, decision); checked != nil {
+			decisions[featureKey] = checked
+		} else {
+			delete(decisions, featureKey)
+		}
+	}
+	return decisions, nil
+}
+
+// SetMany stamps several decisions and writes them to the inner connector
+func (v *VersionedConnector) SetMany(records []map[string]interface{}) error {
+	index := v.currentIndex()
+	stamped := make([]map[string]interface{}, len(records))
+	for i, data := range records {
+		stamped[i] = v.stamp(data, index)
+	}
+	return SetMany(v.inner, stamped)
+}
+
+// Report returns a snapshot of the decisions checked so far
+func (v *VersionedConnector) Report() VersionReport {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	report := v.report
+	report.Invalidated = make(map[StaleReason]uint64, len(v.report.Invalidated))
+	for reason, count := range v.report.Invalidated {
+		report.Invalidated[reason] = count
+	}
+	report.Records = append([]InvalidatedRecord(nil), v.report.Records...)
+	return report
+}
+
+// ResetReport clears the report
+func (v *VersionedConnector) ResetReport() {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.report = VersionReport{Invalidated: make(map[StaleReason]uint64)}
+}
+
+// stamp returns a copy of data with the schema version and, when the campaign is known, its fingerprint
+func (v *VersionedConnector) stamp(data map[string]interface{}, index *settingsIndex) map[string]interface{} {
+	stamped := copyDecision(data)
+	stamped[FieldSchemaVersion] = RecordSchemaVersion
+	delete(stamped, FieldFingerprint)
+	if index != nil {
+		if campaignKey, _ := referencedCampaign(stamped); campaignKey != "" {
+			if c, ok := index.campaigns[campaignKey]; ok {
+				stamped[FieldFingerprint] = c.fingerprint
+			}
+		}
+	}
+	return stamped
+}
+
+// check validates a decision and returns it, or nil when the policy hides it
+func (v *VersionedConnector) check(featureKey string, userID string, decision map[string]interface{}) map[string]interface{} {
+	index := v.currentIndex()
+	if index == nil {
+		return decision
+	}
+
+	version := toInt(decision[FieldSchemaVersion])
+	campaignKey, variationID := referencedCampaign(decision)
+	reason, policy := v.validate(index, decision, campaignKey, variationID)
+
+	v.mu.Lock()
+	v.report.Checked++
+	if version == 0 {
+		v.report.Legacy++
+	}
+	v.mu.Unlock()
+	if reason == "" {
+		return decision
+	}
+
+	record := InvalidatedRecord{
+		FeatureKey:    featureKey,
+		UserID:        userID,
+		CampaignKey:   campaignKey,
+		VariationID:   variationID,
+		SchemaVersion: version,
+		Reason:        reason,
+		Action:        policy,
+		At:            time.Now(),
+	}
+	dropped := policy == DropStale && deleteFrom(v.inner, featureKey, userID) == nil
+	v.invalidated(record, dropped)
+
+	if policy == KeepStale {
+		return decision
+	}
+	return nil
+}
+
+// validate returns why a decision no longer matches the settings and the policy that applies, or an empty reason
+func (v *VersionedConnector) validate(index *settingsIndex, decision map[string]interface{}, campaignKey string, variationID int) (StaleReason, StalePolicy) {
+	if featureID := toInt(decision["featureId"]); featureID != 0 && !index.features[featureID] {
+		return ReasonFeatureRemoved, v.opts.Policy
+	}
+	if campaignKey == "" {
+		return "", KeepStale
+	}
+
+	c, ok := index.campaigns[campaignKey]
+	switch {
+	case !ok:
+		return ReasonCampaignRemoved, v.opts.Policy
+	case !c.active:
+		return ReasonCampaignInactive, v.opts.Policy
+	case !c.variations[variationID]:
+		return ReasonVariationRemoved, v.opts.Policy
+	}
+
+	if fingerprint, ok := decision[FieldFingerprint].(string); ok && fingerprint != c.fingerprint {
+		return ReasonCampaignChanged, v.opts.ChangedPolicy
+	}
+	return "", KeepStale
+}
+
+// invalidated records an invalidated decision in the report and notifies the listener
+func (v *VersionedConnector) invalidated(record InvalidatedRecord, dropped bool) {
+	v.mu.Lock()
+	v.report.Invalidated[record.Reason]++
+	if dropped {
+		v.report.Dropped++
+	}
+	v.report.Records = append(v.report.Records, record)
+	if overflow := len(v.report.Records) - v.opts.ReportSize; overflow > 0 {
+		v.report.Records = append(v.report.Records[:0], v.report.Records[overflow:]...)
+	}
+	v.mu.Unlock()
+
+	if v.opts.OnInvalidated != nil {
+		v.opts.OnInvalidated(record)
+	}
+}
+
+// currentIndex returns the index of the current settings, rebuilding it when they changed
+func (v *VersionedConnector) currentIndex() *settingsIndex {
+	v.mu.Lock()
+	source := v.settings
+	v.mu.Unlock()
+	if source == nil {
+		return nil
+	}
+	raw := source.GetOriginalSettings()
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.index == nil || raw != v.raw {
+		index, err := newSettingsIndex(raw)
+		if err != nil {
+			// unreadable settings are not a reason to invalidate decisions
+			return nil
+		}
+		v.raw, v.index = raw, index
+	}
+	return v.index
+}
+
+// referencedCampaign returns the campaign and variation the SDK uses when it reads the decision back:
+// the experiment when an experiment variation is stored, the rollout otherwise
+func referencedCampaign(decision map[string]interface{}) (string, int) {
+	if variationID := toInt(decision["experimentVariationId"]); variationID != 0 {
+		key, _ := decision["experimentKey"].(string)
+		return key, variationID
+	}
+	key, _ := decision["rolloutKey"].(string)
+	return key, toInt(decision["rolloutVariationId"])
+}
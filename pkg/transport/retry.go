@@ -0,0 +1,263 @@
+/**
+ * Copyright 2025 Wingify Software Pvt. Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package transport
+
+import (
+	"context"
+	"io"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Defaults of a RetryPolicy
+const (
+	DefaultInitialDelay = 100 * time.Millisecond
+	DefaultMaxDelay     = 10 * time.Second
+	DefaultMultiplier   = 2
+)
+
+// DefaultRetryableStatus lists the status codes retried when a RetryPolicy sets none
+var DefaultRetryableStatus = []int{http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout}
+
+// Jitter selects how retry delays are randomized
+type Jitter int
+
+// Jitter strategies
+const (
+	// NoJitter waits InitialDelay * Multiplier^attempt
+	NoJitter Jitter = iota
+	// FullJitter waits a random delay between zero and the exponential delay
+	FullJitter
+	// DecorrelatedJitter waits a random delay between InitialDelay and three times the previous delay
+	DecorrelatedJitter
+)
+
+// RetryPolicy retries SDK requests that fail with a network error or a retryable status code.
+// The zero value does not retry.
+type RetryPolicy struct {
+	// MaxRetries is the number of retries after the first attempt
+	MaxRetries int
+
+	// InitialDelay is the delay before the first retry, DefaultInitialDelay when zero
+	InitialDelay time.Duration
+
+	// MaxDelay caps every delay, DefaultMaxDelay when zero. A Retry-After asking for a longer
+	// wait is not retried: the response is returned as is.
+	MaxDelay time.Duration
+
+	// Multiplier grows the delay after each retry, DefaultMultiplier when below 1
+	Multiplier float64
+
+	// Jitter randomizes the delays so that clients do not retry in lockstep
+	Jitter Jitter
+
+	// RetryableStatus lists the status codes to retry, DefaultRetryableStatus when empty.
+	// Other responses are returned without retrying.
+	RetryableStatus []int
+
+	// Budget limits retries across every policy sharing it; nil does not limit retries
+	Budget *RetryBudget
+}
+
+// RetryBudget limits retries when most requests fail, so that an outage does not turn into a retry storm.
+// Each retryable failure spends a token and each success earns Ratio tokens back; retries are
+// only allowed while more than half of the tokens are left. Share one budget across policies
+// to limit the retries of the whole process.
+type RetryBudget struct {
+	mu     sync.Mutex
+	max    float64
+	ratio  float64
+	tokens float64
+}
+
+// NewRetryBudget creates a full budget of maxTokens tokens earning ratio tokens per success
+func NewRetryBudget(maxTokens int, ratio float64) *RetryBudget {
+	return &RetryBudget{max: float64(maxTokens), ratio: ratio, tokens: float64(maxTokens)}
+}
+
+// Tokens returns the tokens left
+func (b *RetryBudget) Tokens() float64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.tokens
+}
+
+// failed spends a token and returns whether a retry is allowed
+func (b *RetryBudget) failed() bool {
+	if b == nil {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.tokens = math.Max(0, b.tokens-1)
+	return b.tokens > b.max/2
+}
+
+// succeeded earns tokens back
+func (b *RetryBudget) succeeded() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.tokens = math.Min(b.max, b.tokens+b.ratio)
+}
+
+// retryState tracks the retries of one request
+type retryState struct {
+	policy  RetryPolicy
+	attempt int
+	delay   time.Duration
+}
+
+// next returns the delay before retrying a request, or false when the outcome is final
+func (s *retryState) next(req *http.Request, resp *http.Response, err error) (time.Duration, bool) {
+	p := s.policy
+	if err != nil {
+		if req.Context().Err() != nil {
+			return 0, false
+		}
+	} else if !p.retryable(resp.StatusCode) {
+		p.Budget.succeeded()
+		return 0, false
+	}
+
+	if !p.Budget.failed() || s.attempt >= p.MaxRetries || (req.Body != nil && req.GetBody == nil) {
+		return 0, false
+	}
+	s.attempt++
+	s.delay = p.delay(s.attempt, s.delay)
+
+	if resp != nil {
+		if wait, ok := retryAfter(resp, time.Now()); ok {
+			if wait > p.maxDelay() {
+				return 0, false
+			}
+			if wait > s.delay {
+				s.delay = wait
+			}
+		}
+	}
+	return s.delay, true
+}
+
+// retryable returns whether a status code is retried
+func (p RetryPolicy) retryable(status int) bool {
+	codes := p.RetryableStatus
+	if len(codes) == 0 {
+		codes = DefaultRetryableStatus
+	}
+	for _, code := range codes {
+		if code == status {
+			return true
+		}
+	}
+	return false
+}
+
+// delay returns the delay before a retry, given the delay before the previous one
+func (p RetryPolicy) delay(attempt int, previous time.Duration) time.Duration {
+	initial, maxDelay := p.InitialDelay, p.maxDelay()
+	if initial <= 0 {
+		initial = DefaultInitialDelay
+	}
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = DefaultMultiplier
+	}
+
+	var delay float64
+	switch p.Jitter {
+	case DecorrelatedJitter:
+		if previous < initial {
+			previous = initial
+		}
+		delay = float64(initial) + randFloat()*float64(3*previous-initial)
+	case FullJitter:
+		delay = randFloat() * float64(initial) * math.Pow(multiplier, float64(attempt-1))
+	default:
+		delay = float64(initial) * math.Pow(multiplier, float64(attempt-1))
+	}
+	if delay > float64(maxDelay) {
+		return maxDelay
+	}
+	return time.Duration(delay)
+}
+
+// maxDelay returns the cap of the delays
+func (p RetryPolicy) maxDelay() time.Duration {
+	if p.MaxDelay <= 0 {
+		return DefaultMaxDelay
+	}
+	return p.MaxDelay
+}
+
+// retryAfter parses the Retry-After header of a response, in seconds or as an HTTP date
+func retryAfter(resp *http.Response, now time.Time) (time.Duration, bool) {
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if wait := date.Sub(now); wait > 0 {
+			return wait, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// sleep waits for delay or until ctx is done
+func sleep(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// discard drains and closes the body of a response that is retried, so its connection can be reused
+func discard(resp *http.Response) {
+	if resp == nil {
+		return
+	}
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+	resp.Body.Close()
+}
+
+var (
+	randMu  sync.Mutex
+	randSrc = rand.New(rand.NewSource(time.Now().UnixNano()))
+)
+
+// randFloat returns a random number in [0, 1) from a source seeded per process,
+// so that processes started together do not pick the same delays
+func randFloat() float64 {
+	randMu.Lock()
+	defer randMu.Unlock()
+	return randSrc.Float64()
+}
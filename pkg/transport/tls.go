@@ -0,0 +1,66 @@
+/**
+ * Copyright 2025 Wingify Software Pvt. Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package transport
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+)
+
+// TLSFiles names the PEM files of a TLS configuration; empty fields are left out
+type TLSFiles struct {
+	// CAFile holds extra certificate authorities trusted in addition to the system pool
+	CAFile string
+
+	// CertFile and KeyFile hold the client certificate presented for mutual TLS
+	CertFile string
+	KeyFile  string
+
+	// ServerName overrides the name verified in the server certificate
+	ServerName string
+}
+
+// LoadTLS builds a TLS configuration from PEM files, requiring TLS 1.2 or later
+func LoadTLS(files TLSFiles) (*tls.Config, error) {
+	config := &tls.Config{MinVersion: tls.VersionTLS12, ServerName: files.ServerName}
+
+	if files.CAFile != "" {
+		pem, err := os.ReadFile(files.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("transport: no certificates found in %s", files.CAFile)
+		}
+		config.RootCAs = pool
+	}
+
+	if files.CertFile != "" || files.KeyFile != "" {
+		certificate, err := tls.LoadX509KeyPair(files.CertFile, files.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+		config.Certificates = []tls.Certificate{certificate}
+	}
+	return config, nil
+}
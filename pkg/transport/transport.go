@@ -0,0 +1,370 @@
+/**
+ * Copyright 2025 Wingify Software Pvt. Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package transport routes the HTTP traffic of the SDK through a caller-provided transport.
+//
+// The SDK sends its requests with an http.Client that uses http.DefaultTransport and cannot be
+// replaced. Install therefore replaces http.DefaultTransport, until restored, with a switch that
+// forwards to the installed router: requests to the VWO hosts,
+// and to the gateway and proxy hosts listed in Config.Hosts, go through the configured transport
+// with its TLS settings, connection pool, request signing, per-endpoint timeouts and retries. All other
+// requests of the process keep using the original default transport.
+//
+// Warning: while a router is installed, http.DefaultTransport is not an *http.Transport, so code
+// asserting http.DefaultTransport.(*http.Transport) panics. Clone the transport before Install,
+// or use a checked type assertion. Restoring the router puts the original transport back.
+package transport
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Endpoint identifies the kind of SDK request
+type Endpoint string
+
+// Endpoints of the SDK
+const (
+	EndpointSettings    Endpoint = "settings"
+	EndpointEvents      Endpoint = "events"
+	EndpointBatchEvents Endpoint = "batch-events"
+	EndpointGateway     Endpoint = "gateway"
+	EndpointOther       Endpoint = "other"
+)
+
+// DefaultHosts are the hosts the SDK talks to when no gateway or proxy is configured
+var DefaultHosts = []string{"dev.visualwebsiteoptimizer.com", "edge.wingify.net", "collect.wingify.net"}
+
+// ErrInstalled is returned by Install when a router is already installed
+var ErrInstalled = errors.New("transport: already installed")
+
+// Pool sizes the connection pool of the transport built by New; zero values keep the defaults
+type Pool struct {
+	MaxIdleConns        int
+	MaxIdleConnsPerHost int
+	MaxConnsPerHost     int
+	IdleConnTimeout     time.Duration
+}
+
+// Config configures the transport of SDK requests
+type Config struct {
+	// Transport carries SDK requests; when nil, Client.Transport is used, and when that is nil too,
+	// a clone of http.DefaultTransport configured with TLS and Pool
+	Transport http.RoundTripper
+
+	// Client provides the transport and, through its Timeout, the default per-request timeout.
+	// Its redirect policy and cookie jar are not used.
+	Client *http.Client
+
+	// TLS configures the transport built when neither Transport nor Client.Transport is set
+	TLS *tls.Config
+
+	// Pool sizes the transport built when neither Transport nor Client.Transport is set
+	Pool Pool
+
+	// Timeouts bounds requests by endpoint; endpoints without an entry use Client.Timeout.
+	// The SDK bounds every request to 30 seconds on its own, so longer timeouts have no effect.
+	Timeouts map[Endpoint]time.Duration
+
+	// Retry retries SDK requests; the zero value does not retry. Disable the retries of the SDK
+	// with the retryConfig option so that failed requests are not retried twice.
+	Retry RetryPolicy
+
+	// Retries replaces Retry for some endpoints
+	Retries map[Endpoint]RetryPolicy
+
+	// Sign is called on a copy of every SDK request before it is sent, for example to add a signature header
+	Sign func(req *http.Request) error
+
+	// Hosts lists the hosts of SDK requests in addition to DefaultHosts, such as the gateway and proxy hosts.
+	// An entry starting with a dot matches every subdomain.
+	Hosts []string
+}
+
+// Router sends SDK requests through the configured transport and other requests through a fallback
+type Router struct {
+	sdk            http.RoundTripper
+	fallback       http.RoundTripper
+	hosts          map[string]bool
+	suffixes       []string
+	timeouts       map[Endpoint]time.Duration
+	defaultTimeout time.Duration
+	retry          RetryPolicy
+	retries        map[Endpoint]RetryPolicy
+	sign           func(req *http.Request) error
+}
+
+// New creates a Router that sends other requests through fallback, or through http.DefaultTransport when nil
+func New(cfg Config, fallback http.RoundTripper) *Router {
+	if fallback == nil {
+		fallback = http.DefaultTransport
+	}
+
+	r := &Router{
+		sdk:      cfg.Transport,
+		fallback: fallback,
+		hosts:    make(map[string]bool),
+		timeouts: cfg.Timeouts,
+		retry:    cfg.Retry,
+		retries:  cfg.Retries,
+		sign:     cfg.Sign,
+	}
+	if cfg.Client != nil {
+		r.defaultTimeout = cfg.Client.Timeout
+		if r.sdk == nil {
+			r.sdk = cfg.Client.Transport
+		}
+	}
+	if r.sdk == nil {
+		r.sdk = buildTransport(fallback, cfg.TLS, cfg.Pool)
+	}
+
+	for _, host := range append(append([]string(nil), DefaultHosts...), cfg.Hosts...) {
+		host = strings.ToLower(host)
+		if strings.HasPrefix(host, ".") {
+			r.suffixes = append(r.suffixes, host)
+		} else {
+			r.hosts[host] = true
+		}
+	}
+	return r
+}
+
+// RoundTrip implements http.RoundTripper
+func (r *Router) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !r.Matches(req) {
+		return r.fallback.RoundTrip(req)
+	}
+
+	endpoint := Classify(req)
+	state := &retryState{policy: r.retryFor(endpoint)}
+	for attempt := 0; ; attempt++ {
+		out, cancel, err := r.prepare(req, endpoint, attempt)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := r.sdk.RoundTrip(out)
+		if err != nil {
+			cancel()
+		} else {
+			// the deadline must cover reading the body, so it is released when the body is closed
+			resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+		}
+
+		delay, retry := state.next(req, resp, err)
+		if !retry {
+			return resp, err
+		}
+		discard(resp)
+		if err := sleep(req.Context(), delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// prepare returns the signed copy of a request sent by an attempt, bounded by the endpoint timeout
+func (r *Router) prepare(req *http.Request, endpoint Endpoint, attempt int) (*http.Request, context.CancelFunc, error) {
+	ctx := req.Context()
+	cancel := context.CancelFunc(func() {})
+	if timeout := r.timeoutFor(endpoint); timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+	}
+	out := req.Clone(ctx)
+	if attempt > 0 && req.Body != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			cancel()
+			return nil, nil, err
+		}
+		out.Body = body
+	}
+	if r.sign != nil {
+		if err := r.sign(out); err != nil {
+			cancel()
+			return nil, nil, err
+		}
+	}
+	return out, cancel, nil
+}
+
+// Matches returns whether a request goes to one of the SDK hosts
+func (r *Router) Matches(req *http.Request) bool {
+	host := strings.ToLower(req.URL.Hostname())
+	if r.hosts[host] {
+		return true
+	}
+	for _, suffix := range r.suffixes {
+		if strings.HasSuffix(host, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// timeoutFor returns the timeout of an endpoint
+func (r *Router) timeoutFor(endpoint Endpoint) time.Duration {
+	if timeout, ok := r.timeouts[endpoint]; ok {
+		return timeout
+	}
+	return r.defaultTimeout
+}
+
+// retryFor returns the retry policy of an endpoint
+func (r *Router) retryFor(endpoint Endpoint) RetryPolicy {
+	if policy, ok := r.retries[endpoint]; ok {
+		return policy
+	}
+	return r.retry
+}
+
+// Classify returns the endpoint of an SDK request from its path; gateway and proxy paths may carry a prefix
+func Classify(req *http.Request) Endpoint {
+	path := req.URL.Path
+	switch {
+	case strings.HasSuffix(path, "/server-side/v2-settings"), strings.HasSuffix(path, "/server-side/v2-pull"):
+		return EndpointSettings
+	case strings.HasSuffix(path, "/server-side/batch-events-v2"):
+		return EndpointBatchEvents
+	case strings.HasSuffix(path, "/events/t"):
+		return EndpointEvents
+	case strings.HasSuffix(path, "/get-user-details"), strings.HasSuffix(path, "/check-attribute"):
+		return EndpointGateway
+	default:
+		return EndpointOther
+	}
+}
+
+var (
+	installMu sync.Mutex
+	installed *Router
+	active    *switcher
+)
+
+// Install routes the requests of http.DefaultTransport through a Router built from cfg. It must be
+// called before Init, which fetches the settings. The first call replaces http.DefaultTransport with
+// a switch that stays in place; later calls and the returned restore function only change where the
+// switch forwards, so they never write http.DefaultTransport while SDK requests are in flight.
+// After restore, requests go to the previous default transport again.
+func Install(cfg Config) (restore func(), err error) {
+	installMu.Lock()
+	defer installMu.Unlock()
+	if installed != nil {
+		return nil, ErrInstalled
+	}
+
+	// the switch of an earlier Install is reused unless the default transport was replaced since
+	if active == nil || http.DefaultTransport != active.original && http.DefaultTransport != active {
+		active = &switcher{original: http.DefaultTransport}
+		active.use(nil)
+	}
+	http.DefaultTransport = active
+	router := New(cfg, active.original)
+	installed = router
+	active.use(router)
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			installMu.Lock()
+			defer installMu.Unlock()
+			if installed == router {
+				active.use(nil)
+				installed = nil
+				if http.DefaultTransport == active {
+					http.DefaultTransport = active.original
+				}
+			}
+		})
+	}, nil
+}
+
+// switcher replaces http.DefaultTransport and forwards to the installed router, or to the original transport
+type switcher struct {
+	original http.RoundTripper
+	current  atomic.Value
+}
+
+// route is the value held by a switcher; a nil router forwards to the original transport
+type route struct {
+	router *Router
+}
+
+// use makes the switcher forward to router, or to the original transport when router is nil
+func (s *switcher) use(router *Router) {
+	s.current.Store(route{router: router})
+}
+
+// RoundTrip implements http.RoundTripper
+func (s *switcher) RoundTrip(req *http.Request) (*http.Response, error) {
+	if router := s.current.Load().(route).router; router != nil {
+		return router.RoundTrip(req)
+	}
+	return s.original.RoundTrip(req)
+}
+
+// CloseIdleConnections closes the idle connections of the original transport
+func (s *switcher) CloseIdleConnections() {
+	if closer, ok := s.original.(interface{ CloseIdleConnections() }); ok {
+		closer.CloseIdleConnections()
+	}
+}
+
+// buildTransport clones base, or a new transport when base is not an *http.Transport, with the TLS and pool settings
+func buildTransport(base http.RoundTripper, tlsConfig *tls.Config, pool Pool) *http.Transport {
+	var t *http.Transport
+	if b, ok := base.(*http.Transport); ok {
+		t = b.Clone()
+	} else {
+		t = &http.Transport{Proxy: http.ProxyFromEnvironment, ForceAttemptHTTP2: true, TLSHandshakeTimeout: 10 * time.Second}
+	}
+
+	if tlsConfig != nil {
+		t.TLSClientConfig = tlsConfig.Clone()
+	}
+	if pool.MaxIdleConns > 0 {
+		t.MaxIdleConns = pool.MaxIdleConns
+	}
+	if pool.MaxIdleConnsPerHost > 0 {
+		t.MaxIdleConnsPerHost = pool.MaxIdleConnsPerHost
+	}
+	if pool.MaxConnsPerHost > 0 {
+		t.MaxConnsPerHost = pool.MaxConnsPerHost
+	}
+	if pool.IdleConnTimeout > 0 {
+		t.IdleConnTimeout = pool.IdleConnTimeout
+	}
+	return t
+}
+
+// cancelOnClose releases the request context when the response body is closed
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+// Close closes the body and releases the context
+func (c *cancelOnClose) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+	return err
+}
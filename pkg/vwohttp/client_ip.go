@@ -0,0 +1,115 @@
+/**
+ * Copyright 2025 Wingify Software Pvt. Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwohttp
+
+import (
+	"net"
+	"net/http"
+	"strings"
+)
+
+// headerForwardedFor is the header set by proxies with the chain of client addresses
+const headerForwardedFor = "X-Forwarded-For"
+
+// trustedProxy is a parsed entry of Options.TrustedProxies
+type trustedProxy struct {
+	network *net.IPNet
+}
+
+// parseTrustedProxies parses IPs and CIDR ranges, ignoring invalid entries
+func parseTrustedProxies(entries []string) []trustedProxy {
+	proxies := make([]trustedProxy, 0, len(entries))
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		if !strings.Contains(entry, "/") {
+			ip := net.ParseIP(entry)
+			if ip == nil {
+				continue
+			}
+			bits := 8 * net.IPv4len
+			if ip.To4() == nil {
+				bits = 8 * net.IPv6len
+			} else {
+				ip = ip.To4()
+			}
+			proxies = append(proxies, trustedProxy{network: &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}})
+			continue
+		}
+
+		_, network, err := net.ParseCIDR(entry)
+		if err != nil {
+			continue
+		}
+		proxies = append(proxies, trustedProxy{network: network})
+	}
+	return proxies
+}
+
+// isTrusted checks whether the ip belongs to one of the trusted proxies
+func isTrusted(ip net.IP, proxies []trustedProxy) bool {
+	for _, proxy := range proxies {
+		if proxy.network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// clientIP returns the address of the client that made the request.
+// X-Forwarded-For is only honoured when the direct peer is a trusted proxy; the chain is
+// then walked from the right and the first untrusted address is taken as the client.
+func clientIP(r *http.Request, proxies []trustedProxy) string {
+	remote := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(remote); err == nil {
+		remote = host
+	}
+	remoteIP := net.ParseIP(remote)
+	if remoteIP == nil {
+		return ""
+	}
+
+	if len(proxies) == 0 || !isTrusted(remoteIP, proxies) {
+		return remoteIP.String()
+	}
+
+	var chain []string
+	for _, header := range r.Header.Values(headerForwardedFor) {
+		for _, part := range strings.Split(header, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				chain = append(chain, part)
+			}
+		}
+	}
+
+	client := remoteIP
+	for i := len(chain) - 1; i >= 0; i-- {
+		ip := net.ParseIP(chain[i])
+		if ip == nil {
+			// a malformed hop cannot be trusted, stop at the last valid address
+			break
+		}
+		client = ip
+		if !isTrusted(ip, proxies) {
+			break
+		}
+	}
+	return client.String()
+}
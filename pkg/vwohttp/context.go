@@ -0,0 +1,60 @@
+/**
+ * Copyright 2025 Wingify Software Pvt. Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwohttp
+
+import (
+	"context"
+
+	"github.com/wingify/vwo-fme-go-sdk/pkg/flagcontext"
+	"github.com/wingify/wingify-fme-go-sdk/pkg/models"
+)
+
+// Client is the subset of VWOClient used by the middleware
+type Client = flagcontext.Client
+
+// Flags holds the user context of a request and the flags evaluated for it
+type Flags = flagcontext.Flags
+
+// NewFlags creates a new Flags instance for the given user context
+func NewFlags(client Client, userContext map[string]interface{}, onError func(featureKey string, err error)) *Flags {
+	return flagcontext.NewFlags(client, userContext, onError)
+}
+
+// NewContext returns a copy of ctx carrying the flags
+func NewContext(ctx context.Context, flags *Flags) context.Context {
+	return flagcontext.NewContext(ctx, flags)
+}
+
+// FromContext returns the flags stored in ctx by the middleware
+func FromContext(ctx context.Context) (*Flags, bool) {
+	return flagcontext.FromContext(ctx)
+}
+
+// UserContextFromContext returns the VWO user context built for the request
+func UserContextFromContext(ctx context.Context) (map[string]interface{}, bool) {
+	return flagcontext.UserContextFromContext(ctx)
+}
+
+// FlagFromContext returns the flag for the feature, or a disabled flag when the middleware did not run
+func FlagFromContext(ctx context.Context, featureKey string) models.GetFlagResponse {
+	return flagcontext.FlagFromContext(ctx, featureKey)
+}
+
+// IsEnabled returns whether the feature is enabled for the user of the request
+func IsEnabled(ctx context.Context, featureKey string) bool {
+	return flagcontext.IsEnabled(ctx, featureKey)
+}
@@ -0,0 +1,107 @@
+/**
+ * Copyright 2025 Wingify Software Pvt. Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package vwohttp provides net/http middleware that builds the VWO user context
+// for every incoming request and attaches the evaluated flags to the request context.
+package vwohttp
+
+import (
+	"net/http"
+
+	"github.com/wingify/wingify-fme-go-sdk/pkg/enums"
+)
+
+// Options configures the middleware
+type Options struct {
+	// UserIDSources are tried in order until one of them yields a user id
+	UserIDSources []UserIDSource
+
+	// CustomVariables optionally returns custom variables for the request
+	CustomVariables func(r *http.Request) map[string]interface{}
+
+	// TrustedProxies lists the IPs or CIDR ranges whose X-Forwarded-For entries are honoured
+	TrustedProxies []string
+
+	// Features are evaluated before the handler runs; other features are evaluated lazily
+	Features []string
+
+	// OnError is called when a flag evaluation fails
+	OnError func(r *http.Request, featureKey string, err error)
+}
+
+// Middleware returns a middleware that builds the user context and stores the flags in the request context.
+// Requests without a resolvable user id are passed through untouched.
+func Middleware(client Client, opts Options) func(http.Handler) http.Handler {
+	trusted := parseTrustedProxies(opts.TrustedProxies)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			userContext, ok := buildUserContext(r, opts, trusted)
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			var onError func(featureKey string, err error)
+			if opts.OnError != nil {
+				onError = func(featureKey string, err error) {
+					opts.OnError(r, featureKey, err)
+				}
+			}
+
+			flags := NewFlags(client, userContext, onError)
+			for _, featureKey := range opts.Features {
+				flags.Get(featureKey)
+			}
+
+			next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), flags)))
+		})
+	}
+}
+
+// buildUserContext creates the VWO context map for the request
+func buildUserContext(r *http.Request, opts Options, trusted []trustedProxy) (map[string]interface{}, bool) {
+	userID := ""
+	for _, source := range opts.UserIDSources {
+		if id, ok := source(r); ok && id != "" {
+			userID = id
+			break
+		}
+	}
+	if userID == "" {
+		return nil, false
+	}
+
+	userContext := map[string]interface{}{
+		enums.ContextID.GetValue(): userID,
+	}
+
+	if userAgent := r.UserAgent(); userAgent != "" {
+		userContext[enums.ContextUserAgent.GetValue()] = userAgent
+	}
+
+	if ipAddress := clientIP(r, trusted); ipAddress != "" {
+		userContext[enums.ContextIPAddress.GetValue()] = ipAddress
+	}
+
+	if opts.CustomVariables != nil {
+		if customVariables := opts.CustomVariables(r); len(customVariables) > 0 {
+			userContext[enums.ContextCustomVariables.GetValue()] = customVariables
+		}
+	}
+
+	return userContext, true
+}
@@ -0,0 +1,89 @@
+/**
+ * Copyright 2025 Wingify Software Pvt. Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vwohttp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// UserIDSource resolves the user id of an incoming request
+type UserIDSource func(r *http.Request) (string, bool)
+
+// ClaimsFunc returns the verified JWT claims of a request.
+// Token validation is left to the application. Decode the claims with DecodeClaims, or with
+// json.Decoder.UseNumber, so that numeric ids above 2^53 keep their exact value.
+type ClaimsFunc func(r *http.Request) (map[string]interface{}, error)
+
+// DecodeClaims decodes a JSON claims set, keeping numbers as json.Number
+func DecodeClaims(payload []byte) (map[string]interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(payload))
+	decoder.UseNumber()
+
+	var claims map[string]interface{}
+	if err := decoder.Decode(&claims); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// FromCookie reads the user id from the named cookie
+func FromCookie(name string) UserIDSource {
+	return func(r *http.Request) (string, bool) {
+		cookie, err := r.Cookie(name)
+		if err != nil || cookie.Value == "" {
+			return "", false
+		}
+		return cookie.Value, true
+	}
+}
+
+// FromHeader reads the user id from the named header
+func FromHeader(name string) UserIDSource {
+	return func(r *http.Request) (string, bool) {
+		value := strings.TrimSpace(r.Header.Get(name))
+		return value, value != ""
+	}
+}
+
+// FromJWTClaim reads the user id from a claim returned by the claims callback
+func FromJWTClaim(claim string, claims ClaimsFunc) UserIDSource {
+	return func(r *http.Request) (string, bool) {
+		values, err := claims(r)
+		if err != nil || values == nil {
+			return "", false
+		}
+
+		switch value := values[claim].(type) {
+		case nil:
+			return "", false
+		case string:
+			return value, value != ""
+		case json.Number:
+			return value.String(), true
+		case float64:
+			// claims decoded without UseNumber; integers above 2^53 have already lost precision
+			return strconv.FormatFloat(value, 'f', -1, 64), true
+		default:
+			return fmt.Sprint(value), true
+		}
+	}
+}
@@ -0,0 +1,79 @@
+/**
+ * Copyright 2025 Wingify Software Pvt. Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package webtesting
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// Names of the cookies the VWO web snippet sets for the variation of each campaign,
+// such as _vis_opt_exp_122_combi
+const (
+	CookiePrefix = "_vis_opt_exp_"
+	CookieSuffix = "_combi"
+)
+
+// FromCookies returns the campaigns recorded in VWO cookies; other cookies are ignored. Malformed
+// VWO cookies, and cookies giving a campaign two variations, are left out and reported by an error
+// wrapping ErrInvalidCampaigns, returned with the campaigns of the valid cookies.
+func FromCookies(cookies []*http.Cookie) (Campaigns, error) {
+	campaigns := make(Campaigns)
+	conflicts := make(map[string]bool)
+	var problems []string
+	for _, cookie := range cookies {
+		if !strings.HasPrefix(cookie.Name, CookiePrefix) || !strings.HasSuffix(cookie.Name, CookieSuffix) ||
+			len(cookie.Name) < len(CookiePrefix)+len(CookieSuffix) {
+			continue
+		}
+		campaignID := cookie.Name[len(CookiePrefix) : len(cookie.Name)-len(CookieSuffix)]
+		variationID := strings.TrimSpace(cookie.Value)
+		if !isID(campaignID) || !isID(variationID) {
+			problems = append(problems, fmt.Sprintf("%s=%s", cookie.Name, cookie.Value))
+			continue
+		}
+		if existing, ok := campaigns[campaignID]; (ok && existing != variationID) || conflicts[campaignID] {
+			if !conflicts[campaignID] {
+				problems = append(problems, fmt.Sprintf("campaign %s has several variations", campaignID))
+			}
+			conflicts[campaignID] = true
+			delete(campaigns, campaignID)
+			continue
+		}
+		campaigns[campaignID] = variationID
+	}
+
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return campaigns, fmt.Errorf("%w: %s", ErrInvalidCampaigns, strings.Join(problems, ", "))
+	}
+	return campaigns, nil
+}
+
+// FromRequest returns the campaigns recorded in the VWO cookies of a request, see FromCookies
+func FromRequest(req *http.Request) (Campaigns, error) {
+	return FromCookies(req.Cookies())
+}
+
+// ParseCookieHeader returns the campaigns recorded in the VWO cookies of a Cookie header value,
+// such as "_vis_opt_exp_122_combi=2; _vis_opt_exp_130_combi=1", see FromCookies
+func ParseCookieHeader(header string) (Campaigns, error) {
+	req := &http.Request{Header: http.Header{"Cookie": {header}}}
+	return FromCookies(req.Cookies())
+}
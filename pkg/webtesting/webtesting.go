@@ -0,0 +1,299 @@
+/**
+ * Copyright 2025 Wingify Software Pvt. Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package webtesting builds the Web Testing campaigns of a user context, which the campaignVariation
+// conditions of segments match.
+//
+// The SDK reads the campaigns from context.platformVariables.webTestingCampaigns, a map of campaign
+// ids to variation ids given as a map or as a JSON object. It logs and ignores a malformed map, so that
+// every campaignVariation condition fails without telling the caller. Campaigns is a typed map that
+// is valid by construction, Normalize validates maps from other sources, and FromCookies reads the
+// campaigns from the cookies the VWO web snippet sets in the browser.
+package webtesting
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Keys of the user context read by the campaignVariation operand
+const (
+	ContextPlatformVariables = "platformVariables"
+	ContextCampaigns         = "webTestingCampaigns"
+)
+
+// OperatorCampaignVariation is the operator of Web Testing conditions in segment DSL
+const OperatorCampaignVariation = "campaignVariation"
+
+// Errors of Normalize, ParseOperand and FromCookies
+var (
+	ErrInvalidCampaigns = errors.New("webtesting: invalid campaigns")
+	ErrInvalidOperand   = errors.New("webtesting: invalid campaignVariation operand")
+)
+
+// Campaigns maps the ids of the Web Testing campaigns of a user to the ids of their variations
+type Campaigns map[string]string
+
+// New creates an empty Campaigns
+func New() Campaigns {
+	return make(Campaigns)
+}
+
+// Assign records that the user is in a variation of a campaign and returns c
+func (c Campaigns) Assign(campaignID, variationID uint64) Campaigns {
+	c[strconv.FormatUint(campaignID, 10)] = strconv.FormatUint(variationID, 10)
+	return c
+}
+
+// Validate returns an error wrapping ErrInvalidCampaigns when an id is not made of digits only
+func (c Campaigns) Validate() error {
+	for campaignID, variationID := range c {
+		if !isID(campaignID) || !isID(variationID) {
+			return fmt.Errorf("%w: campaign %q has variation %q", ErrInvalidCampaigns, campaignID, variationID)
+		}
+	}
+	return nil
+}
+
+// Context returns a copy of a user context holding the campaigns in its platform variables. Other
+// platform variables are kept; the maps of context are not modified.
+func (c Campaigns) Context(context map[string]interface{}) map[string]interface{} {
+	platformVariables := make(map[string]interface{})
+	if existing, ok := context[ContextPlatformVariables].(map[string]interface{}); ok {
+		for key, value := range existing {
+			platformVariables[key] = value
+		}
+	}
+	campaigns := make(map[string]string, len(c))
+	for campaignID, variationID := range c {
+		campaigns[campaignID] = variationID
+	}
+	platformVariables[ContextCampaigns] = campaigns
+
+	prepared := make(map[string]interface{}, len(context)+1)
+	for key, value := range context {
+		prepared[key] = value
+	}
+	prepared[ContextPlatformVariables] = platformVariables
+	return prepared
+}
+
+// Match evaluates a campaignVariation operand as the SDK does
+func (c Campaigns) Match(operand string) (bool, error) {
+	o, err := ParseOperand(operand)
+	if err != nil {
+		return false, err
+	}
+	return o.Match(c), nil
+}
+
+// Normalize validates the campaigns of a user given as the SDK accepts them: a map with ids as keys
+// and values, or a JSON object. Ids are non-negative integers or strings of digits. Unlike the SDK,
+// which keeps the last of duplicate keys in a JSON object, Normalize rejects them.
+func Normalize(raw interface{}) (Campaigns, error) {
+	switch v := raw.(type) {
+	case Campaigns:
+		return v, v.Validate()
+	case map[string]string:
+		return Campaigns(v), Campaigns(v).Validate()
+	case string:
+		return normalizeJSON(v)
+	case nil:
+		return nil, fmt.Errorf("%w: no campaigns", ErrInvalidCampaigns)
+	}
+
+	value := reflect.ValueOf(raw)
+	if value.Kind() != reflect.Map {
+		return nil, fmt.Errorf("%w: %T is neither a map nor a JSON object", ErrInvalidCampaigns, raw)
+	}
+	campaigns := make(Campaigns, value.Len())
+	for _, key := range value.MapKeys() {
+		// the SDK reads keys as text, as does encoding/json
+		if err := campaigns.add(fmt.Sprint(key.Interface()), value.MapIndex(key).Interface()); err != nil {
+			return nil, err
+		}
+	}
+	return campaigns, nil
+}
+
+// normalizeJSON validates campaigns given as a JSON object
+func normalizeJSON(s string) (Campaigns, error) {
+	decoder := json.NewDecoder(bytes.NewReader([]byte(strings.TrimSpace(s))))
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return nil, fmt.Errorf("%w: not a JSON object", ErrInvalidCampaigns)
+	}
+	campaigns := make(Campaigns)
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidCampaigns, err)
+		}
+		campaignID := token.(string)
+		var variationID interface{}
+		if err := decoder.Decode(&variationID); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidCampaigns, err)
+		}
+		if _, ok := campaigns[campaignID]; ok {
+			return nil, fmt.Errorf("%w: duplicate campaign %q", ErrInvalidCampaigns, campaignID)
+		}
+		if err := campaigns.add(campaignID, variationID); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := decoder.Token(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCampaigns, err)
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, fmt.Errorf("%w: data after the JSON object", ErrInvalidCampaigns)
+	}
+	return campaigns, nil
+}
+
+// add validates and records a campaign
+func (c Campaigns) add(campaignID string, variationID interface{}) error {
+	variation, ok := idText(variationID)
+	if !isID(campaignID) || !ok {
+		return fmt.Errorf("%w: campaign %q has variation %v", ErrInvalidCampaigns, campaignID, variationID)
+	}
+	c[campaignID] = variation
+	return nil
+}
+
+// idText returns the text of an id accepted by the SDK: a non-negative integer, as a Go integer,
+// a whole float or a string of digits
+func idText(id interface{}) (string, bool) {
+	switch v := id.(type) {
+	case int:
+		return strconv.FormatInt(int64(v), 10), v >= 0
+	case int8:
+		return strconv.FormatInt(int64(v), 10), v >= 0
+	case int16:
+		return strconv.FormatInt(int64(v), 10), v >= 0
+	case int32:
+		return strconv.FormatInt(int64(v), 10), v >= 0
+	case int64:
+		return strconv.FormatInt(v, 10), v >= 0
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), v >= 0 && v == float64(int64(v))
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32), v >= 0 && v == float32(int32(v))
+	case string:
+		return v, isID(v)
+	}
+	return "", false
+}
+
+// isID returns whether s is a non-empty string of digits
+func isID(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// operandPattern matches the forms of campaignVariation operands: 122, 122_2, 122_!1 and !122
+var operandPattern = regexp.MustCompile(`^(!?)(\d+)(?:_(!?)(\d+))?$`)
+
+// Operand is a campaignVariation operand
+type Operand struct {
+	// CampaignID is the campaign the operand is about
+	CampaignID string
+
+	// VariationID, when set, is the variation of the campaign the user must be in or, when
+	// NotVariation is set, must not be in
+	VariationID  string
+	NotVariation bool
+
+	// NotCampaign matches users who are not in the campaign
+	NotCampaign bool
+}
+
+// InCampaign returns the operand matching users in any variation of a campaign, such as 122
+func InCampaign(campaignID uint64) Operand {
+	return Operand{CampaignID: strconv.FormatUint(campaignID, 10)}
+}
+
+// InVariation returns the operand matching users in a variation of a campaign, such as 122_2
+func InVariation(campaignID, variationID uint64) Operand {
+	return Operand{CampaignID: strconv.FormatUint(campaignID, 10), VariationID: strconv.FormatUint(variationID, 10)}
+}
+
+// NotInVariation returns the operand matching users in a campaign but not in one of its variations, such as 122_!1
+func NotInVariation(campaignID, variationID uint64) Operand {
+	o := InVariation(campaignID, variationID)
+	o.NotVariation = true
+	return o
+}
+
+// NotInCampaign returns the operand matching users who are not in a campaign, such as !122
+func NotInCampaign(campaignID uint64) Operand {
+	return Operand{CampaignID: strconv.FormatUint(campaignID, 10), NotCampaign: true}
+}
+
+// ParseOperand parses a campaignVariation operand; surrounding spaces are ignored, as by the SDK
+func ParseOperand(s string) (Operand, error) {
+	parts := operandPattern.FindStringSubmatch(strings.TrimSpace(s))
+	if parts == nil || (parts[1] != "" && parts[4] != "") {
+		return Operand{}, fmt.Errorf("%w: %q is none of 122, 122_2, 122_!1 and !122", ErrInvalidOperand, s)
+	}
+	return Operand{CampaignID: parts[2], VariationID: parts[4], NotVariation: parts[3] != "", NotCampaign: parts[1] != ""}, nil
+}
+
+// String returns the operand as written in segment DSL
+func (o Operand) String() string {
+	switch {
+	case o.NotCampaign:
+		return "!" + o.CampaignID
+	case o.VariationID == "":
+		return o.CampaignID
+	case o.NotVariation:
+		return o.CampaignID + "_!" + o.VariationID
+	}
+	return o.CampaignID + "_" + o.VariationID
+}
+
+// DSL returns the segment condition of the operand, such as {"campaignVariation":"122_2"}
+func (o Operand) DSL() map[string]interface{} {
+	return map[string]interface{}{OperatorCampaignVariation: o.String()}
+}
+
+// Match returns whether the campaigns of a user satisfy the operand
+func (o Operand) Match(c Campaigns) bool {
+	variationID, ok := c[o.CampaignID]
+	switch {
+	case o.NotCampaign:
+		return !ok
+	case !ok:
+		return false
+	case o.VariationID == "":
+		return true
+	case o.NotVariation:
+		return variationID != o.VariationID
+	}
+	return variationID == o.VariationID
+}
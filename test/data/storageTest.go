@@ -17,8 +17,10 @@
 package data
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"sort"
 	"sync"
 
 	storageModels "github.com/wingify/wingify-fme-go-sdk/pkg/models/storage"
@@ -71,6 +73,44 @@ func (s *StorageTest) Get(featureKey string, userID string) (interface{}, error)
 	return nil, nil
 }
 
+// Delete removes data from the test storage
+func (s *StorageTest) Delete(featureKey string, userID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.data, featureKey+":"+userID)
+	return nil
+}
+
+// Scan calls fn for every stored decision of userID, or of every user when userID is empty, in key order
+func (s *StorageTest) Scan(ctx context.Context, userID string, fn func(data map[string]interface{}) error) error {
+	s.mu.RLock()
+	keys := make([]string, 0, len(s.data))
+	for key, data := range s.data {
+		if userID == "" || data["userId"] == userID {
+			keys = append(keys, key)
+		}
+	}
+	s.mu.RUnlock()
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		s.mu.RLock()
+		data, exists := s.data[key]
+		s.mu.RUnlock()
+		if !exists {
+			continue
+		}
+		if err := fn(data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // GetStorageData retrieves and parses storage data as StorageData struct
 func (s *StorageTest) GetStorageData(featureKey string, userID string) (*storageModels.StorageData, error) {
 	data, err := s.Get(featureKey, userID)
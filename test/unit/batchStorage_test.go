@@ -0,0 +1,233 @@
+/**
+ * Copyright 2025 Wingify Software Pvt. Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package unit
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wingify/vwo-fme-go-sdk"
+	"github.com/wingify/vwo-fme-go-sdk/pkg/storage"
+	"github.com/wingify/vwo-fme-go-sdk/test/data"
+	"github.com/wingify/wingify-fme-go-sdk/pkg/enums"
+)
+
+// batchConnector is a countingConnector that also implements storage.BatchConnector
+type batchConnector struct {
+	*countingConnector
+	getManys    int64
+	setManys    int64
+	failGetMany error
+}
+
+func newBatchConnector() *batchConnector {
+	return &batchConnector{countingConnector: newCountingConnector()}
+}
+
+func (c *batchConnector) GetMany(userID string, featureKeys []string) (map[string]map[string]interface{}, error) {
+	atomic.AddInt64(&c.getManys, 1)
+	if c.failGetMany != nil {
+		return nil, c.failGetMany
+	}
+	result := map[string]map[string]interface{}{}
+	for _, featureKey := range featureKeys {
+		if value, _ := c.StorageTest.Get(featureKey, userID); value != nil {
+			result[featureKey] = value.(map[string]interface{})
+		}
+	}
+	return result, nil
+}
+
+func (c *batchConnector) SetMany(records []map[string]interface{}) error {
+	atomic.AddInt64(&c.setManys, 1)
+	for _, data := range records {
+		if err := c.StorageTest.Set(data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// nilBatchConnector returns no map from GetMany when it finds nothing
+type nilBatchConnector struct {
+	*batchConnector
+}
+
+func (c *nilBatchConnector) GetMany(userID string, featureKeys []string) (map[string]map[string]interface{}, error) {
+	return nil, nil
+}
+
+var (
+	_ storage.BatchConnector = (*batchConnector)(nil)
+	_ storage.BatchConnector = (*storage.CachedConnector)(nil)
+	_ storage.BatchConnector = (*storage.ResilientConnector)(nil)
+	_ storage.BatchConnector = (*storage.BatchingConnector)(nil)
+)
+
+func TestBatchStorageHelpers(t *testing.T) {
+	t.Run("UsesBatchConnector", func(t *testing.T) {
+		connector := newBatchConnector()
+		assert.NoError(t, storage.SetMany(connector, []map[string]interface{}{
+			storedDecision("feature1", "user-1", 1),
+			storedDecision("feature2", "user-1", 2),
+		}))
+
+		result, err := storage.GetMany(connector, "user-1", []string{"feature1", "feature2", "feature3"})
+		assert.NoError(t, err)
+		assert.Len(t, result, 2)
+		assert.Equal(t, int64(1), connector.getManys)
+		assert.Equal(t, int64(1), connector.setManys)
+		assert.Equal(t, int64(0), connector.gets)
+		assert.Equal(t, int64(0), connector.sets)
+	})
+
+	t.Run("NilResultIsEmpty", func(t *testing.T) {
+		inner := &nilBatchConnector{batchConnector: newBatchConnector()}
+		decisions, err := storage.GetMany(inner, "user-1", []string{"feature1"})
+		assert.NoError(t, err)
+		assert.NotNil(t, decisions)
+
+		batched := storage.Batched(inner)
+		flush, err := batched.Prefetch("user-1", []string{"feature1"})
+		assert.NoError(t, err)
+		assert.NoError(t, batched.Set(storedDecision("feature1", "user-1", 1)))
+		data, _ := batched.Get("feature1", "user-1")
+		assert.NotNil(t, data)
+		assert.NoError(t, flush())
+		assert.Equal(t, int64(1), inner.setManys)
+	})
+
+	t.Run("FallsBackToPerKeyCalls", func(t *testing.T) {
+		connector := newCountingConnector()
+		assert.NoError(t, storage.SetMany(connector, []map[string]interface{}{
+			storedDecision("feature1", "user-1", 1),
+			storedDecision("feature2", "user-1", 2),
+		}))
+
+		result, err := storage.GetMany(connector, "user-1", []string{"feature1", "feature2", "feature3"})
+		assert.NoError(t, err)
+		assert.Len(t, result, 2)
+		assert.Equal(t, int64(3), connector.gets)
+		assert.Equal(t, int64(2), connector.sets)
+
+		connector.failGet = errors.New("backend down")
+		_, err = storage.GetMany(connector, "user-1", []string{"feature1"})
+		assert.Error(t, err)
+	})
+
+	t.Run("CachedServesHitsAndBatchesMisses", func(t *testing.T) {
+		inner := newBatchConnector()
+		inner.StorageTest.Set(storedDecision("feature1", "user-1", 1))
+		inner.StorageTest.Set(storedDecision("feature2", "user-1", 2))
+		cached := storage.Cached(inner, 10, time.Minute)
+
+		cached.Get("feature1", "user-1")
+		result, err := cached.GetMany("user-1", []string{"feature1", "feature2", "feature3"})
+		assert.NoError(t, err)
+		assert.Len(t, result, 2)
+		assert.Equal(t, int64(1), inner.getManys)
+
+		result, _ = cached.GetMany("user-1", []string{"feature1", "feature2", "feature3"})
+		assert.Len(t, result, 2)
+		assert.Equal(t, int64(1), inner.getManys)
+		assert.Equal(t, uint64(1), cached.Stats().NegativeHits)
+
+		assert.NoError(t, cached.SetMany([]map[string]interface{}{storedDecision("feature3", "user-1", 3)}))
+		value, _ := cached.Get("feature3", "user-1")
+		assert.Equal(t, 3, value.(map[string]interface{})["rolloutVariationId"])
+		assert.Equal(t, int64(1), inner.setManys)
+	})
+
+	t.Run("ResilientFailOpen", func(t *testing.T) {
+		inner := newBatchConnector()
+		inner.failGetMany = errors.New("backend down")
+		resilient := storage.Resilient(inner, storage.Policy{Mode: storage.FailOpen})
+
+		result, err := resilient.GetMany("user-1", []string{"feature1"})
+		assert.NoError(t, err)
+		assert.Empty(t, result)
+		assert.Equal(t, uint64(1), resilient.Stats().Failures)
+	})
+}
+
+func TestBatchedGetFlags(t *testing.T) {
+	settings := data.NewDummySettingsReader().SettingsMap["SETTINGS_WITH_SAME_SALT"]
+
+	inner := newBatchConnector()
+	batched := storage.Batched(inner)
+	vwoClient, err := vwo.Init(map[string]interface{}{
+		enums.OptionSDKKey.GetValue():    "abcd",
+		enums.OptionAccountID.GetValue(): 12345,
+		enums.OptionSettings.GetValue():  settings,
+		enums.OptionStorage.GetValue():   batched,
+	})
+	assert.NoError(t, err)
+
+	context := map[string]interface{}{"id": "user-1"}
+
+	t.Run("FirstEvaluationWritesOnce", func(t *testing.T) {
+		flags, err := batched.GetFlags(vwoClient, []string{"feature1", "feature2"}, context)
+		assert.NoError(t, err)
+		assert.Len(t, flags, 2)
+
+		assert.Equal(t, int64(1), inner.getManys)
+		assert.Equal(t, int64(0), inner.gets)
+		assert.Equal(t, int64(0), inner.sets)
+		assert.Equal(t, int64(1), inner.setManys)
+
+		stored, _ := inner.StorageTest.Get("feature1", "user-1")
+		assert.NotNil(t, stored)
+		assert.Equal(t, flags["feature1"].IsEnabled(), stored != nil)
+	})
+
+	t.Run("StoredDecisionsAreReused", func(t *testing.T) {
+		first, _ := vwoClient.GetFlag("feature2", context)
+
+		flags, err := batched.GetFlags(vwoClient, []string{"feature1", "feature2"}, context)
+		assert.NoError(t, err)
+		assert.Equal(t, first.IsEnabled(), flags["feature2"].IsEnabled())
+		assert.Equal(t, int64(2), inner.getManys)
+	})
+
+	t.Run("OutsideGetFlagsUsesInner", func(t *testing.T) {
+		gets := inner.gets
+		vwoClient.GetFlag("feature1", context)
+		assert.Greater(t, inner.gets, gets)
+	})
+
+	t.Run("PrefetchFailureFallsBack", func(t *testing.T) {
+		inner.failGetMany = errors.New("backend down")
+		defer func() { inner.failGetMany = nil }()
+
+		flags, err := batched.GetFlags(vwoClient, []string{"feature1", "feature2"}, map[string]interface{}{"id": "user-2"})
+		assert.NoError(t, err)
+		assert.Len(t, flags, 2)
+	})
+
+	t.Run("ReportsEvaluationErrors", func(t *testing.T) {
+		client := newFakeFlagClient("feature1")
+		client.failing["feature2"] = true
+
+		flags, err := batched.GetFlags(client, []string{"feature1", "feature2"}, context)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "feature2")
+		assert.Len(t, flags, 1)
+	})
+}
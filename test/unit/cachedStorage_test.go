@@ -0,0 +1,300 @@
+/**
+ * Copyright 2025 Wingify Software Pvt. Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package unit
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wingify/vwo-fme-go-sdk/pkg/storage"
+	"github.com/wingify/vwo-fme-go-sdk/test/data"
+)
+
+// countingConnector wraps StorageTest, counting calls and optionally failing or blocking them
+type countingConnector struct {
+	*data.StorageTest
+	gets    int64
+	sets    int64
+	failGet error
+	failSet error
+	block   chan struct{}
+}
+
+func newCountingConnector() *countingConnector {
+	return &countingConnector{StorageTest: data.NewStorageTest()}
+}
+
+func (c *countingConnector) Get(featureKey string, userID string) (interface{}, error) {
+	atomic.AddInt64(&c.gets, 1)
+	if c.block != nil {
+		<-c.block
+	}
+	if c.failGet != nil {
+		return nil, c.failGet
+	}
+	return c.StorageTest.Get(featureKey, userID)
+}
+
+func (c *countingConnector) Set(data map[string]interface{}) error {
+	atomic.AddInt64(&c.sets, 1)
+	if c.failSet != nil {
+		return c.failSet
+	}
+	return c.StorageTest.Set(data)
+}
+
+func TestCachedStorage(t *testing.T) {
+	t.Run("CachesDecisions", func(t *testing.T) {
+		inner := newCountingConnector()
+		inner.StorageTest.Set(storedDecision("feature1", "user-1", 1))
+		cached := storage.Cached(inner, 10, time.Minute)
+
+		for i := 0; i < 3; i++ {
+			result, err := cached.Get("feature1", "user-1")
+			assert.NoError(t, err)
+			assert.Equal(t, 1, result.(map[string]interface{})["rolloutVariationId"])
+		}
+		assert.Equal(t, int64(1), inner.gets)
+		assert.Equal(t, storage.CacheStats{Hits: 2, Misses: 1}, cached.Stats())
+	})
+
+	t.Run("CachesMissingDecisions", func(t *testing.T) {
+		inner := newCountingConnector()
+		cached := storage.Cached(inner, 10, time.Minute)
+
+		for i := 0; i < 3; i++ {
+			result, err := cached.Get("feature1", "user-1")
+			assert.NoError(t, err)
+			assert.Nil(t, result)
+		}
+		assert.Equal(t, int64(1), inner.gets)
+		assert.Equal(t, uint64(2), cached.Stats().NegativeHits)
+	})
+
+	t.Run("WritesThrough", func(t *testing.T) {
+		inner := newCountingConnector()
+		cached := storage.Cached(inner, 10, time.Minute)
+
+		cached.Get("feature1", "user-1")
+		assert.NoError(t, cached.Set(storedDecision("feature1", "user-1", 2)))
+		assert.Equal(t, int64(1), inner.sets)
+
+		result, _ := cached.Get("feature1", "user-1")
+		assert.Equal(t, 2, result.(map[string]interface{})["rolloutVariationId"])
+		assert.Equal(t, int64(1), inner.gets)
+
+		stored, _ := inner.StorageTest.Get("feature1", "user-1")
+		assert.NotNil(t, stored)
+	})
+
+	t.Run("FailedSetDropsEntry", func(t *testing.T) {
+		inner := newCountingConnector()
+		inner.StorageTest.Set(storedDecision("feature1", "user-1", 1))
+		cached := storage.Cached(inner, 10, time.Minute)
+		cached.Get("feature1", "user-1")
+
+		inner.failSet = errors.New("backend down")
+		assert.Error(t, cached.Set(storedDecision("feature1", "user-1", 2)))
+		assert.Equal(t, 0, cached.Len())
+		assert.Equal(t, uint64(1), cached.Stats().Errors)
+	})
+
+	t.Run("DoesNotCacheErrors", func(t *testing.T) {
+		inner := newCountingConnector()
+		inner.failGet = errors.New("backend down")
+		cached := storage.Cached(inner, 10, time.Minute)
+
+		_, err := cached.Get("feature1", "user-1")
+		assert.Error(t, err)
+		_, err = cached.Get("feature1", "user-1")
+		assert.Error(t, err)
+		assert.Equal(t, int64(2), inner.gets)
+	})
+
+	t.Run("ReturnsCopies", func(t *testing.T) {
+		inner := newCountingConnector()
+		inner.StorageTest.Set(storedDecision("feature1", "user-1", 1))
+		cached := storage.Cached(inner, 10, time.Minute)
+
+		result, _ := cached.Get("feature1", "user-1")
+		result.(map[string]interface{})["rolloutVariationId"] = 99
+
+		result, _ = cached.Get("feature1", "user-1")
+		assert.Equal(t, 1, result.(map[string]interface{})["rolloutVariationId"])
+	})
+
+	t.Run("ExpiresEntries", func(t *testing.T) {
+		inner := newCountingConnector()
+		cached := storage.Cached(inner, 10, 20*time.Millisecond)
+
+		cached.Get("feature1", "user-1")
+		time.Sleep(40 * time.Millisecond)
+		cached.Get("feature1", "user-1")
+		assert.Equal(t, int64(2), inner.gets)
+	})
+
+	t.Run("EvictsLeastRecentlyUsed", func(t *testing.T) {
+		inner := newCountingConnector()
+		cached := storage.Cached(inner, 2, 0)
+
+		cached.Get("feature1", "user-1")
+		cached.Get("feature2", "user-1")
+		cached.Get("feature1", "user-1")
+		cached.Get("feature3", "user-1")
+		assert.Equal(t, 2, cached.Len())
+		assert.Equal(t, uint64(1), cached.Stats().Evictions)
+
+		cached.Get("feature1", "user-1")
+		assert.Equal(t, int64(3), inner.gets)
+		cached.Get("feature2", "user-1")
+		assert.Equal(t, int64(4), inner.gets)
+	})
+
+	t.Run("Invalidate", func(t *testing.T) {
+		inner := newCountingConnector()
+		cached := storage.Cached(inner, 10, 0)
+
+		cached.Get("feature1", "user-1")
+		cached.Get("feature2", "user-1")
+		cached.Invalidate("feature1", "user-1")
+		assert.Equal(t, 1, cached.Len())
+		cached.Purge()
+		assert.Equal(t, 0, cached.Len())
+	})
+
+	t.Run("CoalescesConcurrentLookups", func(t *testing.T) {
+		inner := newCountingConnector()
+		inner.StorageTest.Set(storedDecision("feature1", "user-1", 1))
+		inner.block = make(chan struct{})
+		cached := storage.Cached(inner, 10, time.Minute)
+
+		var wg sync.WaitGroup
+		results := make([]interface{}, 10)
+		for i := range results {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				results[i], _ = cached.Get("feature1", "user-1")
+			}(i)
+		}
+
+		assert.Eventually(t, func() bool {
+			stats := cached.Stats()
+			return stats.Misses+stats.Coalesced == 10
+		}, time.Second, time.Millisecond)
+		close(inner.block)
+		wg.Wait()
+
+		assert.Equal(t, int64(1), inner.gets)
+		assert.Equal(t, uint64(9), cached.Stats().Coalesced)
+		for _, result := range results {
+			assert.Equal(t, 1, result.(map[string]interface{})["rolloutVariationId"])
+		}
+	})
+
+	t.Run("SetDuringLookupWins", func(t *testing.T) {
+		inner := newCountingConnector()
+		inner.block = make(chan struct{})
+		cached := storage.Cached(inner, 10, time.Minute)
+
+		done := make(chan struct{})
+		go func() {
+			cached.Get("feature1", "user-1")
+			close(done)
+		}()
+		assert.Eventually(t, func() bool { return atomic.LoadInt64(&inner.gets) == 1 }, time.Second, time.Millisecond)
+
+		assert.NoError(t, cached.Set(storedDecision("feature1", "user-1", 2)))
+		close(inner.block)
+		<-done
+
+		result, _ := cached.Get("feature1", "user-1")
+		assert.Equal(t, 2, result.(map[string]interface{})["rolloutVariationId"])
+	})
+
+	t.Run("PanicReleasesLookup", func(t *testing.T) {
+		inner := &panickingConnector{batchConnector: newBatchConnector(), panics: 2}
+		inner.StorageTest.Set(storedDecision("feature1", "user-1", 1))
+		cached := storage.Cached(inner, 10, time.Minute)
+
+		assert.Panics(t, func() { cached.Get("feature1", "user-1") })
+		assert.Panics(t, func() { cached.GetMany("user-1", []string{"feature1"}) })
+
+		done := make(chan interface{})
+		go func() {
+			result, _ := cached.Get("feature1", "user-1")
+			done <- result
+		}()
+		select {
+		case result := <-done:
+			assert.NotNil(t, result)
+		case <-time.After(time.Second):
+			t.Fatal("lookup after a panic did not return")
+		}
+	})
+
+	t.Run("PanicFailsWaitingLookups", func(t *testing.T) {
+		inner := &panickingConnector{batchConnector: newBatchConnector(), panics: 1}
+		inner.block = make(chan struct{})
+		cached := storage.Cached(inner, 10, time.Minute)
+
+		go func() {
+			defer func() { recover() }()
+			cached.Get("feature1", "user-1")
+		}()
+		assert.Eventually(t, func() bool { return atomic.LoadInt64(&inner.gets) == 1 }, time.Second, time.Millisecond)
+
+		waiter := make(chan error)
+		go func() {
+			_, err := cached.Get("feature1", "user-1")
+			waiter <- err
+		}()
+		assert.Eventually(t, func() bool { return cached.Stats().Coalesced == 1 }, time.Second, time.Millisecond)
+		close(inner.block)
+		select {
+		case err := <-waiter:
+			assert.Error(t, err)
+		case <-time.After(time.Second):
+			t.Fatal("waiting lookup did not return")
+		}
+	})
+}
+
+// panickingConnector panics in its first Get and GetMany calls
+type panickingConnector struct {
+	*batchConnector
+	panics int32
+}
+
+func (c *panickingConnector) Get(featureKey string, userID string) (interface{}, error) {
+	result, err := c.batchConnector.Get(featureKey, userID)
+	if atomic.AddInt32(&c.panics, -1) >= 0 {
+		panic("connector failure")
+	}
+	return result, err
+}
+
+func (c *panickingConnector) GetMany(userID string, featureKeys []string) (map[string]map[string]interface{}, error) {
+	if atomic.AddInt32(&c.panics, -1) >= 0 {
+		panic("connector failure")
+	}
+	return c.batchConnector.GetMany(userID, featureKeys)
+}
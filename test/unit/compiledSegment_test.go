@@ -0,0 +1,164 @@
+/**
+ * Copyright 2025 Wingify Software Pvt. Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package unit
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wingify/vwo-fme-go-sdk/pkg/segmentation"
+	loggerCore "github.com/wingify/wingify-fme-go-sdk/pkg/packages/logger/core"
+	segmentationCore "github.com/wingify/wingify-fme-go-sdk/pkg/packages/segmentation_evaluator/core"
+)
+
+// allOperandsDSL combines the operands of the SDK, as in TestCombination
+const allOperandsDSL = `{"or":[{"or":[{"and":[{"or":[{"custom_variable":{"start_with":"wildcard(my_start_with_val*)"}}]},{"not":{"or":[{"custom_variable":{"neq":"not_eq_value"}}]}}]},{"or":[{"custom_variable":{"contain":"wildcard(*my_contain_val*)"}}]}]},{"and":[{"or":[{"custom_variable":{"eq":"eq_value"}}]},{"or":[{"custom_variable":{"reg":"regex(myregex+)"}},{"custom_variable":{"age":"gte(18)"}}]}]}]}`
+
+// safeValidate evaluates a segment with the SDK, which panics on conditions needing a user context
+// when used on its own
+func safeValidate(segmentationManager *segmentationCore.SegmentationManager, dsl interface{}, customVariables map[string]interface{}) (result bool) {
+	defer func() {
+		if recover() != nil {
+			result = false
+		}
+	}()
+	return segmentationManager.ValidateSegmentation(dsl, customVariables)
+}
+
+func TestCompiledSegmentConformance(t *testing.T) {
+	segmentationManager := segmentationCore.NewSegmentationManagerWithEvaluator(loggerCore.NewLogManager(nil), true)
+	evaluator := segmentation.New(segmentation.Options{})
+
+	operands := []interface{}{
+		"eq_value", " eq_value ", `"quoted"`, "5", "5.0", "05", "1.2.3", "true", "", 5.0, 5.5, true,
+		"lower(Eq_Value)", "lower(5)",
+		"wildcard(*val*)", "wildcard(val*)", "wildcard(*val)", "wildcard(va.l)", "wildcard(*)", "wildcard(5*)",
+		"regex(^v[a-z]+$)", "regex(5)", "regex([invalid)", "regex(.*)",
+		"gt(5)", "gte(5.0)", "lt(10.5)", "lte(1.2.3)", "gt(1.10)", "gt(abc)", "lte(007)",
+	}
+	values := []interface{}{
+		"eq_value", "EQ_VALUE", " eq_value ", "val", "value", "a_val", "quoted", `"quoted"`,
+		"5", "5.0", "05", " 5 ", "-5", "6", "10", "1.2.3", "1.10", "1.9", "1.2.10", "NaN", "007",
+		5, 5.0, 5.5, 6.0, 1e21, -1.0, int64(10), json.Number("5.00"), true, false, "true", nil, "",
+	}
+
+	for _, operand := range operands {
+		dsl := map[string]interface{}{"or": []interface{}{map[string]interface{}{"custom_variable": map[string]interface{}{"tag": operand}}}}
+		segment, err := evaluator.Compile(dsl)
+		assert.NoError(t, err)
+		for _, value := range values {
+			customVariables := map[string]interface{}{"tag": value}
+			assert.Equal(t, safeValidate(segmentationManager, dsl, customVariables), segment.Validate(customVariables), "%v on %#v", operand, value)
+		}
+	}
+
+	for _, dsl := range []string{
+		allOperandsDSL,
+		`{"and":[]}`,
+		`{"or":[]}`,
+		`{"and":{"custom_variable":{"tag":"5"}}}`,
+		`{"not":[]}`,
+		`{"and":[{"custom_variable":{"tag":"5"}},{"unknown":{"tag":"5"}}]}`,
+		`{"or":[{"custom_variable":{"tag":"5"}},{"unknown":{"tag":"5"}}]}`,
+		`{"and":[{"custom_variable":{"tag":"5"}},"text"]}`,
+		`{"and":[{"custom_variable":{"tag":"5"}},{"country":"IN"}]}`,
+		`{"or":[{"os":"wildcard(*Windows*)"},{"browser_string":"Chrome"}]}`,
+		`{"or":[{"user":"user-1, \"user-2\""}]}`,
+		`{"or":[{"user":5}]}`,
+		`{"custom_variable":{"tag":"inlist(list-1)"}}`,
+		`{"custom_variable":{}}`,
+		`{"unknown":{}}`,
+		`{}`,
+	} {
+		segment, err := evaluator.Compile(dsl)
+		assert.NoError(t, err)
+		for _, customVariables := range []map[string]interface{}{
+			{"tag": "5"},
+			{"tag": 4, "_wingifyUserId": "user-2"},
+			{"start_with": "my_start_with_val1", "neq": "x", "contain": 1, "eq": 1, "reg": 1},
+			{"start_with": 1, "neq": 1, "contain": 1, "eq": "eq_value", "reg": "x", "age": 18},
+			{},
+		} {
+			assert.Equal(t, safeValidate(segmentationManager, dsl, customVariables), segment.Validate(customVariables), "%s on %v", dsl, customVariables)
+		}
+	}
+}
+
+func TestCompiledSegment(t *testing.T) {
+	evaluator := segmentation.New(segmentation.Options{})
+
+	t.Run("ReusesCompiledSegment", func(t *testing.T) {
+		segment, err := evaluator.Compile(allOperandsDSL)
+		assert.NoError(t, err)
+		customVariables := map[string]interface{}{"eq": "eq_value", "reg": "myregexxx"}
+		assert.True(t, segment.Validate(customVariables))
+		assert.True(t, evaluator.Validate(allOperandsDSL, customVariables))
+		assert.True(t, evaluator.Validate(allOperandsDSL, customVariables))
+		assert.False(t, segment.Validate(map[string]interface{}{"eq": "eq_value", "reg": "other"}))
+	})
+
+	t.Run("MixesExtendedOperands", func(t *testing.T) {
+		segment, err := evaluator.Compile(`{"and":[{"custom_variable":{"app_version":"semver_gte(2.10.0)"}},{"custom_variable":{"plan":"lower(PRO)"}}]}`)
+		assert.NoError(t, err)
+		assert.True(t, segment.Validate(map[string]interface{}{"app_version": "2.10.0", "plan": "Pro"}))
+		assert.False(t, segment.Validate(map[string]interface{}{"app_version": "2.9.0", "plan": "Pro"}))
+	})
+
+	t.Run("InvalidDSL", func(t *testing.T) {
+		_, err := evaluator.Compile(`{"or":[`)
+		assert.Error(t, err)
+		assert.False(t, evaluator.Validate(`{"or":[`, nil))
+	})
+}
+
+func BenchmarkSegmentEvaluation(b *testing.B) {
+	customVariables := map[string]interface{}{"start_with": 1, "neq": 1, "contain": 1, "eq": "eq_value", "reg": "nomatch", "age": 21}
+
+	b.Run("SDK", func(b *testing.B) {
+		segmentationManager := segmentationCore.NewSegmentationManagerWithEvaluator(loggerCore.NewLogManager(nil), true)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if !segmentationManager.ValidateSegmentation(allOperandsDSL, customVariables) {
+				b.Fatal("segment does not match")
+			}
+		}
+	})
+
+	b.Run("Evaluator", func(b *testing.B) {
+		evaluator := segmentation.New(segmentation.Options{})
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if !evaluator.Validate(allOperandsDSL, customVariables) {
+				b.Fatal("segment does not match")
+			}
+		}
+	})
+
+	b.Run("Compiled", func(b *testing.B) {
+		segment, err := segmentation.New(segmentation.Options{}).Compile(allOperandsDSL)
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if !segment.Validate(customVariables) {
+				b.Fatal("segment does not match")
+			}
+		}
+	})
+}
@@ -0,0 +1,130 @@
+/**
+ * Copyright 2025 Wingify Software Pvt. Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package unit
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wingify/vwo-fme-go-sdk"
+	"github.com/wingify/vwo-fme-go-sdk/pkg/segmentation"
+	"github.com/wingify/wingify-fme-go-sdk/pkg/enums"
+	loggerCore "github.com/wingify/wingify-fme-go-sdk/pkg/packages/logger/core"
+	segmentationCore "github.com/wingify/wingify-fme-go-sdk/pkg/packages/segmentation_evaluator/core"
+)
+
+// tiers ranks the tenant tiers of tier_at_least
+var tiers = map[string]int{"bronze": 1, "silver": 2, "gold": 3, "platinum": 4}
+
+// tierAtLeast matches tenants whose tier is at least the expected one
+func tierAtLeast(expected string, actual interface{}) (bool, error) {
+	want, ok := tiers[expected]
+	if !ok {
+		return false, fmt.Errorf("unknown tier %q", expected)
+	}
+	return tiers[fmt.Sprint(actual)] >= want, nil
+}
+
+// beforeGeoFence is created during variable initialization, before init registers geo_fence
+var beforeGeoFence = segmentation.New(segmentation.Options{})
+
+func init() {
+	if err := segmentation.RegisterOperand("geo_fence", func(expected string, actual interface{}) (bool, error) {
+		return expected == fmt.Sprint(actual), nil
+	}); err != nil {
+		panic(err)
+	}
+	if err := segmentation.RegisterOperand("tier_at_least", tierAtLeast); err != nil {
+		panic(err)
+	}
+	if err := segmentation.RegisterOperand("panicking_operand", func(string, interface{}) (bool, error) { panic("boom") }); err != nil {
+		panic(err)
+	}
+}
+
+func TestCustomOperand(t *testing.T) {
+	segmentationManager := segmentationCore.NewSegmentationManagerWithEvaluator(loggerCore.NewLogManager(nil), true)
+	evaluator := segmentation.New(segmentation.Options{})
+	dsl := `{"or":[{"custom_variable":{"tier":"tier_at_least(gold)"}}]}`
+
+	t.Run("Evaluation", func(t *testing.T) {
+		for tier, expected := range map[string]bool{"platinum": true, "gold": true, " gold ": true, "silver": false, "unknown": false} {
+			customVariables := map[string]interface{}{"tier": tier}
+			assert.Equal(t, expected, evaluator.Validate(dsl, customVariables), tier)
+			// the SDK compares the operand as an equality
+			assert.False(t, safeValidate(segmentationManager, dsl, customVariables), tier)
+		}
+		verifySegment(t, evaluator, `{"and":[{"custom_variable":{"tier":"tier_at_least(silver)"}},{"not":{"or":[{"custom_variable":{"tier":"tier_at_least(platinum)"}}]}}]}`,
+			map[string]interface{}{"tier": "gold", "expectation": true})
+	})
+
+	t.Run("Failures", func(t *testing.T) {
+		verifySegment(t, evaluator, `{"or":[{"custom_variable":{"tier":"tier_at_least(diamond)"}}]}`, map[string]interface{}{"tier": "gold", "expectation": false})
+		verifySegment(t, evaluator, `{"or":[{"custom_variable":{"tier":"panicking_operand(x)"}}]}`, map[string]interface{}{"tier": "gold", "expectation": false})
+
+		explanation, err := evaluator.Explain(`{"or":[{"custom_variable":{"tier":"panicking_operand(x)"}}]}`, map[string]interface{}{"tier": "gold"})
+		assert.NoError(t, err)
+		assert.Equal(t, "custom_variable tier: panicking_operand(x) failed on 'gold': segmentation: operand panicking_operand panicked: boom", explanation.Children[0].Reason)
+	})
+
+	t.Run("Collisions", func(t *testing.T) {
+		noop := func(string, interface{}) (bool, error) { return false, nil }
+		for _, name := range []string{"regex", "gt", "inlist", "semver_gt", "within_days", "in", "tier_at_least"} {
+			err := segmentation.RegisterOperand(name, noop)
+			assert.True(t, errors.Is(err, segmentation.ErrOperandExists), name)
+		}
+		assert.EqualError(t, segmentation.RegisterOperand("gt", noop), "segmentation: operand already exists: gt is an operand of the SDK")
+
+		for _, name := range []string{"", "Tier", "geo-fence", "1tier", "tier(x)"} {
+			err := segmentation.RegisterOperand(name, noop)
+			assert.True(t, errors.Is(err, segmentation.ErrInvalidOperand), name)
+		}
+		assert.True(t, errors.Is(segmentation.RegisterOperand("no_function", nil), segmentation.ErrInvalidOperand))
+	})
+
+	t.Run("RegisteredAfterNew", func(t *testing.T) {
+		geoDSL := `{"or":[{"custom_variable":{"zone":"geo_fence(downtown)"}}]}`
+		assert.False(t, beforeGeoFence.Validate(geoDSL, map[string]interface{}{"zone": "downtown"}))
+		assert.True(t, segmentation.New(segmentation.Options{}).Validate(geoDSL, map[string]interface{}{"zone": "downtown"}))
+	})
+
+	t.Run("GetFlag", func(t *testing.T) {
+		prepared, err := evaluator.Prepare(segmentedSettings(t, map[string]interface{}{"or": []interface{}{
+			map[string]interface{}{"custom_variable": map[string]interface{}{"tier": "tier_at_least(gold)"}},
+		}}))
+		assert.NoError(t, err)
+		assert.Len(t, prepared.Bindings(), 1)
+
+		vwoClient, err := vwo.Init(map[string]interface{}{
+			enums.OptionSDKKey.GetValue():    "abcd",
+			enums.OptionAccountID.GetValue(): 12345,
+			enums.OptionSettings.GetValue():  prepared.Settings(),
+		})
+		assert.NoError(t, err)
+
+		for tier, expected := range map[string]bool{"platinum": true, "silver": false} {
+			flag, err := vwoClient.GetFlag("feature1", prepared.Context(map[string]interface{}{
+				"id":              "tier-user-" + tier,
+				"customVariables": map[string]interface{}{"tier": tier},
+			}))
+			assert.NoError(t, err)
+			assert.Equal(t, expected, flag.IsEnabled(), tier)
+		}
+	})
+}
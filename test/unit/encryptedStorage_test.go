@@ -0,0 +1,190 @@
+/**
+ * Copyright 2025 Wingify Software Pvt. Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package unit
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wingify/vwo-fme-go-sdk"
+	"github.com/wingify/vwo-fme-go-sdk/pkg/storage"
+	"github.com/wingify/vwo-fme-go-sdk/pkg/storage/filestore"
+	"github.com/wingify/vwo-fme-go-sdk/test/data"
+	"github.com/wingify/wingify-fme-go-sdk/pkg/enums"
+)
+
+var (
+	_ storage.BatchConnector = (*storage.EncryptedConnector)(nil)
+	_ storage.Scanner        = (*storage.EncryptedConnector)(nil)
+	_ storage.Deleter        = (*storage.EncryptedConnector)(nil)
+)
+
+var (
+	keyOne = storage.Key{ID: "k1", Secret: []byte("0123456789abcdef0123456789abcdef")}
+	keyTwo = storage.Key{ID: "k2", Secret: []byte("fedcba9876543210fedcba9876543210")}
+)
+
+func TestEncryptedStorage(t *testing.T) {
+	t.Run("StoresNothingInClear", func(t *testing.T) {
+		inner := newBatchConnector()
+		encrypted := storage.Encrypted(inner, storage.StaticKeys{keyOne})
+
+		assert.NoError(t, encrypted.Set(storedDecision("feature1", "user-1", 1)))
+		stored, _ := inner.StorageTest.Get("feature1", "user-1")
+		assert.Nil(t, stored)
+
+		hashedID, err := encrypted.HashUserID("user-1")
+		assert.NoError(t, err)
+		assert.Len(t, hashedID, 64)
+		stored, _ = inner.StorageTest.Get("feature1", hashedID)
+		record := stored.(map[string]interface{})
+		assert.Equal(t, "k1", record[storage.FieldKeyID])
+		assert.NotContains(t, record[storage.FieldPayload], "user-1")
+		assert.NotContains(t, record, "rolloutVariationId")
+
+		result, err := encrypted.Get("feature1", "user-1")
+		assert.NoError(t, err)
+		assert.Equal(t, "user-1", result.(map[string]interface{})["userId"])
+		assert.Equal(t, float64(1), result.(map[string]interface{})["rolloutVariationId"])
+
+		result, _ = encrypted.Get("feature1", "user-2")
+		assert.Nil(t, result)
+	})
+
+	t.Run("RotatesKeysOnRead", func(t *testing.T) {
+		inner := newBatchConnector()
+		assert.NoError(t, storage.Encrypted(inner, storage.StaticKeys{keyOne}).SetMany([]map[string]interface{}{
+			storedDecision("feature1", "user-1", 1),
+			storedDecision("feature2", "user-1", 2),
+		}))
+
+		rotated := storage.Encrypted(inner, storage.StaticKeys{keyTwo, keyOne})
+		result, err := rotated.Get("feature1", "user-1")
+		assert.NoError(t, err)
+		assert.NotNil(t, result)
+
+		oldID, _ := storage.Encrypted(inner, storage.StaticKeys{keyOne}).HashUserID("user-1")
+		newID, _ := rotated.HashUserID("user-1")
+		stored, _ := inner.StorageTest.Get("feature1", newID)
+		assert.Equal(t, "k2", stored.(map[string]interface{})[storage.FieldKeyID])
+		stored, _ = inner.StorageTest.Get("feature1", oldID)
+		assert.Nil(t, stored)
+
+		decisions, err := rotated.GetMany("user-1", []string{"feature1", "feature2", "feature3"})
+		assert.NoError(t, err)
+		assert.Len(t, decisions, 2)
+		stored, _ = inner.StorageTest.Get("feature2", newID)
+		assert.NotNil(t, stored)
+
+		retired := storage.Encrypted(inner, storage.StaticKeys{keyTwo})
+		result, _ = retired.Get("feature2", "user-1")
+		assert.NotNil(t, result)
+	})
+
+	t.Run("RejectsTamperedRecords", func(t *testing.T) {
+		inner := data.NewStorageTest()
+		encrypted := storage.Encrypted(inner, storage.StaticKeys{keyOne})
+		assert.NoError(t, encrypted.Set(storedDecision("feature1", "user-1", 1)))
+		assert.NoError(t, encrypted.Set(storedDecision("feature1", "user-2", 2)))
+
+		victim, _ := encrypted.HashUserID("user-1")
+		attacker, _ := encrypted.HashUserID("user-2")
+		stored, _ := inner.Get("feature1", attacker)
+		record := stored.(map[string]interface{})
+		record["userId"] = victim
+		inner.Set(record)
+
+		_, err := encrypted.Get("feature1", "user-1")
+		assert.Error(t, err)
+	})
+
+	t.Run("ValidatesKeys", func(t *testing.T) {
+		inner := data.NewStorageTest()
+		assert.Equal(t, storage.ErrNoKeys, storage.Encrypted(inner, storage.StaticKeys{}).Set(storedDecision("feature1", "user-1", 1)))
+		assert.Error(t, storage.Encrypted(inner, storage.StaticKeys{{ID: "short", Secret: []byte("secret")}}).Set(storedDecision("feature1", "user-1", 1)))
+
+		assert.NoError(t, storage.Encrypted(inner, storage.StaticKeys{keyOne}).Set(storedDecision("feature1", "user-1", 1)))
+		hashedID, _ := storage.Encrypted(inner, storage.StaticKeys{keyOne}).HashUserID("user-1")
+		stored, _ := inner.Get("feature1", hashedID)
+		stored.(map[string]interface{})[storage.FieldKeyID] = "k3"
+		_, err := storage.Encrypted(inner, storage.StaticKeys{keyOne}).Get("feature1", "user-1")
+		assert.ErrorIs(t, err, storage.ErrUnknownKey)
+	})
+
+	t.Run("ForgetUserAcrossKeys", func(t *testing.T) {
+		ctx := context.Background()
+		path := filepath.Join(t.TempDir(), "vwo.db")
+		store := openFileStore(t, path, filestore.Options{})
+		defer store.Close()
+
+		assert.NoError(t, storage.Encrypted(store, storage.StaticKeys{keyOne}).Set(storedDecision("feature1", "user-1", 1)))
+		encrypted := storage.Encrypted(store, storage.StaticKeys{keyTwo, keyOne})
+		assert.NoError(t, encrypted.Set(storedDecision("feature2", "user-1", 2)))
+		assert.NoError(t, encrypted.Set(storedDecision("feature1", "user-2", 1)))
+
+		admin := storage.NewAdmin(encrypted)
+		decisions, err := admin.ListUser(ctx, "user-1")
+		assert.NoError(t, err)
+		assert.Len(t, decisions, 2)
+
+		var export bytes.Buffer
+		exported, err := admin.Export(ctx, &export, "")
+		assert.NoError(t, err)
+		assert.Equal(t, 3, exported)
+
+		deleted, err := admin.ForgetUser(ctx, "user-1")
+		assert.NoError(t, err)
+		assert.Equal(t, 2, deleted)
+		assert.Equal(t, 1, store.Len())
+		assert.Equal(t, 0, store.Garbage())
+
+		contents, _ := os.ReadFile(path)
+		assert.NotContains(t, string(contents), "user-2")
+	})
+}
+
+func TestEncryptedStorageWithClient(t *testing.T) {
+	settings := data.NewDummySettingsReader().SettingsMap["BASIC_ROLLOUT_SETTINGS"]
+
+	inner := data.NewStorageTest()
+	encrypted := storage.Encrypted(inner, storage.StaticKeys{keyOne})
+	vwoClient, err := vwo.Init(map[string]interface{}{
+		enums.OptionSDKKey.GetValue():    "abcd",
+		enums.OptionAccountID.GetValue(): 12345,
+		enums.OptionSettings.GetValue():  settings,
+		enums.OptionStorage.GetValue():   encrypted,
+	})
+	assert.NoError(t, err)
+
+	context := map[string]interface{}{"id": "user-1"}
+	flag, err := vwoClient.GetFlag("feature1", context)
+	assert.NoError(t, err)
+	assert.True(t, flag.IsEnabled())
+
+	stored, _ := inner.Get("feature1", "user-1")
+	assert.Nil(t, stored)
+	decision, err := encrypted.Get("feature1", "user-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "feature1_rolloutRule1", decision.(map[string]interface{})["rolloutKey"])
+
+	flag, _ = vwoClient.GetFlag("feature1", context)
+	assert.True(t, flag.IsEnabled())
+}
@@ -0,0 +1,236 @@
+/**
+ * Copyright 2025 Wingify Software Pvt. Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package unit
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wingify/vwo-fme-go-sdk/pkg/storage/filestore"
+	"github.com/wingify/wingify-fme-go-sdk/pkg/packages/storage"
+)
+
+var _ storage.Connector = (*filestore.Store)(nil)
+
+func storedDecision(featureKey string, userID string, variationID int) map[string]interface{} {
+	return map[string]interface{}{
+		"featureKey":         featureKey,
+		"userId":             userID,
+		"featureId":          1,
+		"rolloutKey":         featureKey + "_rollout",
+		"rolloutId":          2,
+		"rolloutVariationId": variationID,
+	}
+}
+
+func openFileStore(t *testing.T, path string, opts filestore.Options) *filestore.Store {
+	store, err := filestore.Open(path, opts)
+	assert.NoError(t, err)
+	return store
+}
+
+func TestFileStore(t *testing.T) {
+	t.Run("SetAndGet", func(t *testing.T) {
+		store := openFileStore(t, filepath.Join(t.TempDir(), "vwo.db"), filestore.Options{})
+		defer store.Close()
+
+		data, err := store.Get("feature1", "user-1")
+		assert.NoError(t, err)
+		assert.Nil(t, data)
+
+		assert.NoError(t, store.Set(storedDecision("feature1", "user-1", 1)))
+		data, err = store.Get("feature1", "user-1")
+		assert.NoError(t, err)
+		assert.Equal(t, "feature1_rollout", data.(map[string]interface{})["rolloutKey"])
+		assert.Equal(t, float64(1), data.(map[string]interface{})["rolloutVariationId"])
+
+		assert.Error(t, store.Set(map[string]interface{}{"userId": "user-1"}))
+		assert.Error(t, store.Set(map[string]interface{}{"featureKey": "feature1"}))
+	})
+
+	t.Run("PersistsAcrossReopen", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "vwo.db")
+		store := openFileStore(t, path, filestore.Options{})
+		assert.NoError(t, store.Set(storedDecision("feature1", "user-1", 1)))
+		assert.NoError(t, store.Set(storedDecision("feature1", "user-1", 2)))
+		assert.NoError(t, store.Set(storedDecision("feature2", "user-1", 1)))
+		assert.NoError(t, store.Close())
+
+		store = openFileStore(t, path, filestore.Options{})
+		defer store.Close()
+		assert.Equal(t, 2, store.Len())
+		data, _ := store.Get("feature1", "user-1")
+		assert.Equal(t, float64(2), data.(map[string]interface{})["rolloutVariationId"])
+	})
+
+	t.Run("RecoversFromTornWrite", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "vwo.db")
+		store := openFileStore(t, path, filestore.Options{})
+		assert.NoError(t, store.Set(storedDecision("feature1", "user-1", 1)))
+		assert.NoError(t, store.Close())
+
+		intact, _ := os.Stat(path)
+		file, _ := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0600)
+		file.Write([]byte{0, 0, 0, 40, 1, 2, 3, 4, '{', '"'})
+		file.Close()
+
+		store = openFileStore(t, path, filestore.Options{})
+		data, _ := store.Get("feature1", "user-1")
+		assert.NotNil(t, data)
+		repaired, _ := os.Stat(path)
+		assert.Equal(t, intact.Size(), repaired.Size())
+
+		assert.NoError(t, store.Set(storedDecision("feature2", "user-1", 1)))
+		assert.NoError(t, store.Close())
+
+		store = openFileStore(t, path, filestore.Options{})
+		defer store.Close()
+		assert.Equal(t, 2, store.Len())
+	})
+
+	t.Run("SkipsCorruptRecord", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "vwo.db")
+		store := openFileStore(t, path, filestore.Options{})
+		assert.NoError(t, store.Set(storedDecision("feature1", "user-1", 1)))
+		assert.NoError(t, store.Set(storedDecision("feature2", "user-1", 1)))
+		assert.NoError(t, store.Close())
+
+		// flip a byte of the first payload; the record after it must survive
+		content, _ := os.ReadFile(path)
+		content[14] ^= 0xff
+		assert.NoError(t, os.WriteFile(path, content, 0600))
+
+		store = openFileStore(t, path, filestore.Options{})
+		assert.Equal(t, 1, store.Len())
+		assert.Equal(t, 1, store.Garbage())
+		data, _ := store.Get("feature1", "user-1")
+		assert.Nil(t, data)
+		data, _ = store.Get("feature2", "user-1")
+		assert.NotNil(t, data)
+		stat, _ := os.Stat(path)
+		assert.Equal(t, int64(len(content)), stat.Size())
+
+		assert.NoError(t, store.Compact())
+		assert.Equal(t, 0, store.Garbage())
+		assert.NoError(t, store.Close())
+	})
+
+	t.Run("RejectsCorruptHeader", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "vwo.db")
+		store := openFileStore(t, path, filestore.Options{})
+		assert.NoError(t, store.Set(storedDecision("feature1", "user-1", 1)))
+		assert.NoError(t, store.Set(storedDecision("feature2", "user-1", 1)))
+		assert.NoError(t, store.Close())
+
+		content, _ := os.ReadFile(path)
+		content[0] = 0xff
+		assert.NoError(t, os.WriteFile(path, content, 0600))
+
+		_, err := filestore.Open(path, filestore.Options{})
+		assert.True(t, errors.Is(err, filestore.ErrCorrupt))
+		stat, _ := os.Stat(path)
+		assert.Equal(t, int64(len(content)), stat.Size())
+	})
+
+	t.Run("RejectsCorruptMiddleHeader", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "vwo.db")
+		store := openFileStore(t, path, filestore.Options{})
+		for i := 1; i <= 5; i++ {
+			assert.NoError(t, store.Set(storedDecision(fmt.Sprintf("feature%d", i), "user-1", 1)))
+		}
+		assert.NoError(t, store.Close())
+
+		// a plausible length in the second header must not pass for a torn final write
+		content, _ := os.ReadFile(path)
+		second := 12 + int(binary.BigEndian.Uint32(content[0:4]))
+		binary.BigEndian.PutUint32(content[second:second+4], 4096)
+		assert.NoError(t, os.WriteFile(path, content, 0600))
+
+		_, err := filestore.Open(path, filestore.Options{})
+		assert.True(t, errors.Is(err, filestore.ErrCorrupt))
+		stat, _ := os.Stat(path)
+		assert.Equal(t, int64(len(content)), stat.Size())
+	})
+
+	t.Run("EvictsStaleDecisions", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "vwo.db")
+		store := openFileStore(t, path, filestore.Options{MaxAge: 50 * time.Millisecond})
+		assert.NoError(t, store.Set(storedDecision("feature1", "user-1", 1)))
+		assert.NoError(t, store.Set(storedDecision("feature2", "user-1", 1)))
+
+		time.Sleep(100 * time.Millisecond)
+		assert.NoError(t, store.Set(storedDecision("feature3", "user-1", 1)))
+
+		data, _ := store.Get("feature1", "user-1")
+		assert.Nil(t, data)
+		assert.Equal(t, 1, store.Evict())
+		assert.Equal(t, 1, store.Len())
+		assert.NoError(t, store.Close())
+
+		store = openFileStore(t, path, filestore.Options{MaxAge: 50 * time.Millisecond})
+		defer store.Close()
+		data, _ = store.Get("feature2", "user-1")
+		assert.Nil(t, data)
+	})
+
+	t.Run("Compact", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "vwo.db")
+		store := openFileStore(t, path, filestore.Options{})
+		for i := 1; i <= 10; i++ {
+			assert.NoError(t, store.Set(storedDecision("feature1", "user-1", i)))
+		}
+		assert.NoError(t, store.Set(storedDecision("feature2", "user-1", 1)))
+		assert.Equal(t, 9, store.Garbage())
+
+		before, _ := os.Stat(path)
+		assert.NoError(t, store.Compact())
+		after, _ := os.Stat(path)
+		assert.Less(t, after.Size(), before.Size())
+		assert.Equal(t, 0, store.Garbage())
+
+		assert.NoError(t, store.Set(storedDecision("feature3", "user-1", 1)))
+		assert.NoError(t, store.Close())
+
+		store = openFileStore(t, path, filestore.Options{})
+		defer store.Close()
+		assert.Equal(t, 3, store.Len())
+		data, _ := store.Get("feature1", "user-1")
+		assert.Equal(t, float64(10), data.(map[string]interface{})["rolloutVariationId"])
+	})
+
+	t.Run("BackgroundCompaction", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "vwo.db")
+		store := openFileStore(t, path, filestore.Options{MaxAge: 20 * time.Millisecond, CompactInterval: 10 * time.Millisecond})
+		assert.NoError(t, store.Set(storedDecision("feature1", "user-1", 1)))
+
+		assert.Eventually(t, func() bool {
+			info, err := os.Stat(path)
+			return err == nil && info.Size() == 0
+		}, time.Second, 10*time.Millisecond)
+		assert.NoError(t, store.Close())
+		assert.NoError(t, store.Close())
+
+		_, err := store.Get("feature1", "user-1")
+		assert.Equal(t, filestore.ErrClosed, err)
+	})
+}
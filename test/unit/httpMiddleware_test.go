@@ -0,0 +1,261 @@
+/**
+ * Copyright 2025 Wingify Software Pvt. Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package unit
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wingify/vwo-fme-go-sdk/pkg/vwohttp"
+	"github.com/wingify/wingify-fme-go-sdk/pkg/models"
+)
+
+// fakeFlagClient records GetFlag calls and enables the features listed in enabled
+type fakeFlagClient struct {
+	mu       sync.Mutex
+	enabled  map[string]bool
+	failing  map[string]bool
+	calls    []string
+	contexts []map[string]interface{}
+}
+
+func newFakeFlagClient(enabled ...string) *fakeFlagClient {
+	client := &fakeFlagClient{enabled: map[string]bool{}, failing: map[string]bool{}}
+	for _, featureKey := range enabled {
+		client.enabled[featureKey] = true
+	}
+	return client
+}
+
+func (c *fakeFlagClient) GetFlag(featureKey string, context map[string]interface{}) (models.GetFlagResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.calls = append(c.calls, featureKey)
+	c.contexts = append(c.contexts, context)
+	if c.failing[featureKey] {
+		return nil, errors.New("evaluation failed")
+	}
+	variables := []*models.Variable{models.NewVariable("color", "blue", "string", 1)}
+	return models.NewGetFlag(c.enabled[featureKey], variables, "uuid-"+featureKey, 1), nil
+}
+
+func serveWithMiddleware(client vwohttp.Client, opts vwohttp.Options, req *http.Request, handler func(r *http.Request)) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler(r)
+	})
+	vwohttp.Middleware(client, opts)(next).ServeHTTP(httptest.NewRecorder(), req)
+}
+
+func TestHTTPMiddleware(t *testing.T) {
+	t.Run("UserIDFromCookie", func(t *testing.T) {
+		client := newFakeFlagClient("feature1")
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.AddCookie(&http.Cookie{Name: "uid", Value: "user-1"})
+		req.Header.Set("User-Agent", "test-agent")
+
+		var userContext map[string]interface{}
+		serveWithMiddleware(client, vwohttp.Options{UserIDSources: []vwohttp.UserIDSource{vwohttp.FromCookie("uid")}}, req, func(r *http.Request) {
+			userContext, _ = vwohttp.UserContextFromContext(r.Context())
+			assert.True(t, vwohttp.IsEnabled(r.Context(), "feature1"))
+		})
+
+		assert.Equal(t, "user-1", userContext["id"])
+		assert.Equal(t, "test-agent", userContext["userAgent"])
+		assert.Equal(t, "192.0.2.1", userContext["ipAddress"])
+	})
+
+	t.Run("SourcesAreTriedInOrder", func(t *testing.T) {
+		client := newFakeFlagClient()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("X-User-Id", "header-user")
+
+		claims := func(r *http.Request) (map[string]interface{}, error) {
+			return map[string]interface{}{"sub": float64(42)}, nil
+		}
+		opts := vwohttp.Options{UserIDSources: []vwohttp.UserIDSource{
+			vwohttp.FromCookie("uid"),
+			vwohttp.FromJWTClaim("sub", claims),
+			vwohttp.FromHeader("X-User-Id"),
+		}}
+
+		var userContext map[string]interface{}
+		serveWithMiddleware(client, opts, req, func(r *http.Request) {
+			userContext, _ = vwohttp.UserContextFromContext(r.Context())
+		})
+		assert.Equal(t, "42", userContext["id"])
+	})
+
+	t.Run("JWTClaimKeepsLargeNumbers", func(t *testing.T) {
+		payload := []byte(`{"sub": 9007199254740993, "ratio": 1.5}`)
+		for claim, expected := range map[string]string{"sub": "9007199254740993", "ratio": "1.5"} {
+			source := vwohttp.FromJWTClaim(claim, func(r *http.Request) (map[string]interface{}, error) {
+				return vwohttp.DecodeClaims(payload)
+			})
+			userID, ok := source(httptest.NewRequest(http.MethodGet, "/", nil))
+			assert.True(t, ok)
+			assert.Equal(t, expected, userID)
+		}
+
+		_, err := vwohttp.DecodeClaims([]byte("not json"))
+		assert.Error(t, err)
+	})
+
+	t.Run("MissingUserIDPassesThrough", func(t *testing.T) {
+		client := newFakeFlagClient("feature1")
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+
+		called := false
+		serveWithMiddleware(client, vwohttp.Options{UserIDSources: []vwohttp.UserIDSource{vwohttp.FromHeader("X-User-Id")}}, req, func(r *http.Request) {
+			called = true
+			_, ok := vwohttp.FromContext(r.Context())
+			assert.False(t, ok)
+			assert.False(t, vwohttp.IsEnabled(r.Context(), "feature1"))
+		})
+		assert.True(t, called)
+		assert.Empty(t, client.calls)
+	})
+
+	t.Run("PreEvaluatesListedFeaturesOnce", func(t *testing.T) {
+		client := newFakeFlagClient("feature1")
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("X-User-Id", "user-1")
+
+		opts := vwohttp.Options{
+			UserIDSources: []vwohttp.UserIDSource{vwohttp.FromHeader("X-User-Id")},
+			Features:      []string{"feature1", "feature2"},
+		}
+		serveWithMiddleware(client, opts, req, func(r *http.Request) {
+			flags, ok := vwohttp.FromContext(r.Context())
+			assert.True(t, ok)
+			assert.Len(t, flags.Evaluated(), 2)
+			assert.True(t, flags.IsEnabled("feature1"))
+			assert.False(t, flags.IsEnabled("feature2"))
+			assert.Equal(t, "blue", flags.GetVariable("feature1", "color", "red"))
+			// not listed, evaluated lazily
+			assert.False(t, flags.IsEnabled("feature3"))
+		})
+		assert.Equal(t, []string{"feature1", "feature2", "feature3"}, client.calls)
+	})
+
+	t.Run("CustomVariablesAndErrors", func(t *testing.T) {
+		client := newFakeFlagClient()
+		client.failing["broken"] = true
+		req := httptest.NewRequest(http.MethodGet, "/?plan=pro", nil)
+		req.Header.Set("X-User-Id", "user-1")
+
+		var failed []string
+		opts := vwohttp.Options{
+			UserIDSources: []vwohttp.UserIDSource{vwohttp.FromHeader("X-User-Id")},
+			CustomVariables: func(r *http.Request) map[string]interface{} {
+				return map[string]interface{}{"plan": r.URL.Query().Get("plan")}
+			},
+			OnError: func(r *http.Request, featureKey string, err error) {
+				failed = append(failed, featureKey)
+			},
+		}
+		serveWithMiddleware(client, opts, req, func(r *http.Request) {
+			assert.False(t, vwohttp.IsEnabled(r.Context(), "broken"))
+			assert.False(t, vwohttp.IsEnabled(r.Context(), "broken"))
+		})
+
+		assert.Equal(t, []string{"broken", "broken"}, failed)
+		assert.Equal(t, map[string]interface{}{"plan": "pro"}, client.contexts[0]["customVariables"])
+	})
+	t.Run("EvaluationDoesNotHoldTheLock", func(t *testing.T) {
+		// a flag whose evaluation reads another flag of the same request
+		var flags *vwohttp.Flags
+		client := &nestedFlagClient{fakeFlagClient: newFakeFlagClient("feature1", "feature2"), flags: &flags}
+		flags = vwohttp.NewFlags(client, map[string]interface{}{"id": "user-1"}, nil)
+
+		done := make(chan bool)
+		go func() { done <- flags.IsEnabled("feature2") }()
+		select {
+		case enabled := <-done:
+			assert.True(t, enabled)
+		case <-time.After(time.Second):
+			t.Fatal("nested evaluation deadlocked")
+		}
+		assert.Len(t, flags.Evaluated(), 2)
+	})
+}
+
+// nestedFlagClient evaluates feature1 through the request flags while evaluating feature2
+type nestedFlagClient struct {
+	*fakeFlagClient
+	flags **vwohttp.Flags
+}
+
+func (c *nestedFlagClient) GetFlag(featureKey string, context map[string]interface{}) (models.GetFlagResponse, error) {
+	if featureKey == "feature2" {
+		(*c.flags).IsEnabled("feature1")
+	}
+	return c.fakeFlagClient.GetFlag(featureKey, context)
+}
+
+func TestHTTPMiddlewareClientIP(t *testing.T) {
+	resolveIP := func(remoteAddr string, forwardedFor string, trusted []string) interface{} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = remoteAddr
+		req.Header.Set("X-User-Id", "user-1")
+		if forwardedFor != "" {
+			req.Header.Set("X-Forwarded-For", forwardedFor)
+		}
+
+		var userContext map[string]interface{}
+		opts := vwohttp.Options{
+			UserIDSources:  []vwohttp.UserIDSource{vwohttp.FromHeader("X-User-Id")},
+			TrustedProxies: trusted,
+		}
+		serveWithMiddleware(newFakeFlagClient(), opts, req, func(r *http.Request) {
+			userContext, _ = vwohttp.UserContextFromContext(r.Context())
+		})
+		return userContext["ipAddress"]
+	}
+
+	t.Run("IgnoresForwardedForWithoutTrustedProxies", func(t *testing.T) {
+		assert.Equal(t, "10.0.0.1", resolveIP("10.0.0.1:1234", "1.1.1.1", nil))
+	})
+
+	t.Run("IgnoresForwardedForFromUntrustedPeer", func(t *testing.T) {
+		assert.Equal(t, "203.0.113.9", resolveIP("203.0.113.9:1234", "1.1.1.1", []string{"10.0.0.0/8"}))
+	})
+
+	t.Run("UsesFirstUntrustedHopFromTheRight", func(t *testing.T) {
+		assert.Equal(t, "2.2.2.2", resolveIP("10.0.0.1:1234", "1.1.1.1, 2.2.2.2, 10.0.0.7", []string{"10.0.0.0/8"}))
+	})
+
+	t.Run("SingleTrustedIP", func(t *testing.T) {
+		assert.Equal(t, "1.1.1.1", resolveIP("10.0.0.1:1234", "1.1.1.1", []string{"10.0.0.1"}))
+	})
+
+	t.Run("AllHopsTrusted", func(t *testing.T) {
+		assert.Equal(t, "10.0.0.5", resolveIP("10.0.0.1:1234", "10.0.0.5, 10.0.0.6", []string{"10.0.0.0/8"}))
+	})
+
+	t.Run("StopsAtMalformedHop", func(t *testing.T) {
+		assert.Equal(t, "1.1.1.1", resolveIP("10.0.0.1:1234", "unknown, 1.1.1.1", []string{"10.0.0.0/8"}))
+	})
+
+	t.Run("IPv6", func(t *testing.T) {
+		assert.Equal(t, "2001:db8::1", resolveIP("[::1]:1234", "2001:db8::1", []string{"::1"}))
+	})
+}
@@ -0,0 +1,364 @@
+/**
+ * Copyright 2025 Wingify Software Pvt. Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package unit
+
+import (
+	"encoding/binary"
+	"net"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wingify/vwo-fme-go-sdk"
+	"github.com/wingify/vwo-fme-go-sdk/pkg/gateway"
+	"github.com/wingify/vwo-fme-go-sdk/test/data"
+	"github.com/wingify/wingify-fme-go-sdk/pkg/enums"
+)
+
+const (
+	iPhoneUA  = "Mozilla/5.0 (iPhone; CPU iPhone OS 17_2 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.2 Mobile/15E148 Safari/604.1"
+	windowsUA = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"
+)
+
+// mmdbWriter builds a small MaxMind DB for tests
+type mmdbWriter struct {
+	nodes   [][2]int
+	data    []byte
+	offsets []int
+}
+
+// mmdbEmpty and mmdbData mark records of mmdbWriter nodes; other values are node indexes
+const (
+	mmdbEmpty = -1
+	mmdbData  = -2
+)
+
+func newMMDBWriter() *mmdbWriter {
+	return &mmdbWriter{nodes: [][2]int{{mmdbEmpty, mmdbEmpty}}}
+}
+
+// insert stores a record for a network of an IPv6 tree
+func (w *mmdbWriter) insert(cidr string, record interface{}) {
+	_, network, _ := net.ParseCIDR(cidr)
+	ones, bits := network.Mask.Size()
+	ip := network.IP
+	if bits == 32 {
+		// IPv4 networks live under 96 zero bits
+		ip = append(make(net.IP, 12), network.IP.To4()...)
+		ones += 96
+	}
+
+	w.offsets = append(w.offsets, len(w.data))
+	w.data = append(w.data, encodeMMDB(record)...)
+	dataIndex := len(w.offsets) - 1
+
+	node := 0
+	for i := 0; i < ones; i++ {
+		bit := int(ip[i/8]>>(7-uint(i%8))) & 1
+		if i == ones-1 {
+			w.nodes[node][bit] = mmdbData - dataIndex
+			return
+		}
+		if w.nodes[node][bit] < 0 {
+			w.nodes = append(w.nodes, [2]int{mmdbEmpty, mmdbEmpty})
+			w.nodes[node][bit] = len(w.nodes) - 1
+		}
+		node = w.nodes[node][bit]
+	}
+}
+
+// bytes returns the database with the given record size
+func (w *mmdbWriter) bytes(recordSize int) []byte {
+	nodeCount := len(w.nodes)
+	value := func(record int) uint32 {
+		switch {
+		case record == mmdbEmpty:
+			return uint32(nodeCount)
+		case record <= mmdbData:
+			return uint32(nodeCount + 16 + w.offsets[mmdbData-record])
+		}
+		return uint32(record)
+	}
+
+	var out []byte
+	for _, node := range w.nodes {
+		left, right := value(node[0]), value(node[1])
+		switch recordSize {
+		case 24:
+			out = append(out, byte(left>>16), byte(left>>8), byte(left), byte(right>>16), byte(right>>8), byte(right))
+		case 28:
+			out = append(out, byte(left>>16), byte(left>>8), byte(left), byte(left>>20&0xf0|right>>24&0x0f), byte(right>>16), byte(right>>8), byte(right))
+		default:
+			record := make([]byte, 8)
+			binary.BigEndian.PutUint32(record, left)
+			binary.BigEndian.PutUint32(record[4:], right)
+			out = append(out, record...)
+		}
+	}
+	out = append(out, make([]byte, 16)...)
+	out = append(out, w.data...)
+	out = append(out, "\xab\xcd\xefMaxMind.com"...)
+	return append(out, encodeMMDB(map[string]interface{}{
+		"node_count":  uint32(nodeCount),
+		"record_size": uint16(recordSize),
+		"ip_version":  uint16(6),
+	})...)
+}
+
+// mmdbRaw is data section bytes written as they are
+type mmdbRaw []byte
+
+// encodeMMDB encodes maps, arrays, strings and unsigned integers of the MaxMind DB data section
+func encodeMMDB(value interface{}) []byte {
+	switch v := value.(type) {
+	case mmdbRaw:
+		return v
+	case string:
+		return append([]byte{2<<5 | byte(len(v))}, v...)
+	case uint16:
+		return []byte{5<<5 | 2, byte(v >> 8), byte(v)}
+	case uint32:
+		return []byte{6<<5 | 4, byte(v >> 24), byte(v >> 16), byte(v >> 8), byte(v)}
+	case []interface{}:
+		out := []byte{byte(len(v)), 11 - 7}
+		for _, item := range v {
+			out = append(out, encodeMMDB(item)...)
+		}
+		return out
+	case map[string]interface{}:
+		out := []byte{7<<5 | byte(len(v))}
+		for key, item := range v {
+			out = append(out, encodeMMDB(key)...)
+			out = append(out, encodeMMDB(item)...)
+		}
+		return out
+	}
+	panic("unsupported value")
+}
+
+// cityRecord returns a GeoIP2 City record
+func cityRecord(country string, region string, city string) map[string]interface{} {
+	return map[string]interface{}{
+		"country":      map[string]interface{}{"iso_code": country},
+		"subdivisions": []interface{}{map[string]interface{}{"names": map[string]interface{}{"en": region}}},
+		"city":         map[string]interface{}{"names": map[string]interface{}{"en": city}},
+	}
+}
+
+// segmentedSettings returns BASIC_ROLLOUT_SETTINGS with segments on its rollout rule, which rollouts keep on their variation
+func segmentedSettings(t testing.TB, segments map[string]interface{}) string {
+	raw := data.NewDummySettingsReader().SettingsMap["BASIC_ROLLOUT_SETTINGS"]
+	return string(mutateSettings(t, raw, func(settings map[string]interface{}) {
+		variation := firstCampaign(settings)["variations"].([]interface{})[0].(map[string]interface{})
+		variation["segments"] = segments
+		settings["features"].([]interface{})[0].(map[string]interface{})["isGatewayServiceRequired"] = true
+	}))
+}
+
+func TestParseUserAgent(t *testing.T) {
+	for ua, expected := range map[string]gateway.UserAgent{
+		windowsUA: {Browser: "Chrome", BrowserVersion: "120.0.0.0", OS: "Windows", OSVersion: "10", DeviceType: "desktop"},
+		iPhoneUA:  {Browser: "Safari", BrowserVersion: "17.2", OS: "iOS", OSVersion: "17.2", DeviceType: "mobile", Device: "iPhone"},
+		"Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/121.0.6167.101 Mobile Safari/537.36": {
+			Browser: "Chrome", BrowserVersion: "121.0.6167.101", OS: "Android", OSVersion: "14", DeviceType: "mobile", Device: "Pixel 8",
+		},
+		"Mozilla/5.0 (Linux; Android 13; SM-X710) AppleWebKit/537.36 (KHTML, like Gecko) SamsungBrowser/23.0 Chrome/115.0.0.0 Safari/537.36": {
+			Browser: "Samsung Internet", BrowserVersion: "23.0", OS: "Android", OSVersion: "13", DeviceType: "tablet", Device: "SM-X710",
+		},
+		"Mozilla/5.0 (Macintosh; Intel Mac OS X 10.15; rv:122.0) Gecko/20100101 Firefox/122.0": {
+			Browser: "Firefox", BrowserVersion: "122.0", OS: "Mac OS X", OSVersion: "10.15", DeviceType: "desktop",
+		},
+		"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 Edg/120.0.2210.91": {
+			Browser: "Edge", BrowserVersion: "120.0.2210.91", OS: "Windows", OSVersion: "10", DeviceType: "desktop",
+		},
+		"Mozilla/5.0 (Windows NT 6.1; Trident/7.0; rv:11.0) like Gecko": {
+			Browser: "Internet Explorer", BrowserVersion: "11.0", OS: "Windows", OSVersion: "7", DeviceType: "desktop",
+		},
+		"": {},
+	} {
+		assert.Equal(t, expected, gateway.ParseUserAgent(ua), ua)
+	}
+	assert.Equal(t, map[string]string{"browser_string": "Chrome", "browser_version": "120.0.0.0", "os": "Windows", "os_version": "10", "device_type": "desktop"},
+		gateway.ParseUserAgent(windowsUA).Map())
+}
+
+func TestLocators(t *testing.T) {
+	t.Run("CSV", func(t *testing.T) {
+		table, err := gateway.LoadCSV(strings.NewReader("network,country,region,city\n" +
+			"# documentation ranges\n" +
+			"81.2.69.0/24,GB,England,London\n" +
+			"2.125.160.216/29,GB\n" +
+			"2001:db8::/32,US,California,San Francisco\n"))
+		assert.NoError(t, err)
+		assert.Equal(t, 3, table.Len())
+
+		location, ok := table.Locate(net.ParseIP("81.2.69.142"))
+		assert.True(t, ok)
+		assert.Equal(t, gateway.Location{Country: "GB", Region: "England", City: "London"}, location)
+		location, _ = table.Locate(net.ParseIP("2.125.160.223"))
+		assert.Equal(t, map[string]string{"country": "GB"}, location.Map())
+		location, _ = table.Locate(net.ParseIP("2001:db8:1::1"))
+		assert.Equal(t, "US", location.Country)
+
+		for _, ip := range []string{"81.2.70.1", "2.125.160.224", "10.0.0.1", "2001:db9::1"} {
+			_, ok = table.Locate(net.ParseIP(ip))
+			assert.False(t, ok, ip)
+		}
+
+		_, network, _ := net.ParseCIDR("10.0.0.0/8")
+		table.Add(network, gateway.Location{Country: "XX"})
+		location, _ = table.Locate(net.ParseIP("10.1.2.3"))
+		assert.Equal(t, "XX", location.Country)
+
+		_, err = gateway.LoadCSV(strings.NewReader("81.2.69.0/24,GB\nnot-a-network,US\n"))
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "line 2")
+	})
+
+	t.Run("MMDB", func(t *testing.T) {
+		writer := newMMDBWriter()
+		writer.insert("81.2.69.0/24", cityRecord("GB", "England", "London"))
+		writer.insert("2001:db8::/32", cityRecord("US", "California", "San Francisco"))
+
+		for _, recordSize := range []int{24, 28, 32} {
+			db, err := gateway.LoadMMDB(writer.bytes(recordSize))
+			if !assert.NoError(t, err) {
+				continue
+			}
+
+			location, ok := db.Locate(net.ParseIP("81.2.69.160"))
+			assert.True(t, ok)
+			assert.Equal(t, gateway.Location{Country: "GB", Region: "England", City: "London"}, location)
+			location, ok = db.Locate(net.ParseIP("2001:db8::42"))
+			assert.True(t, ok)
+			assert.Equal(t, "San Francisco", location.City)
+
+			_, ok = db.Locate(net.ParseIP("81.2.70.1"))
+			assert.False(t, ok)
+		}
+
+		_, err := gateway.LoadMMDB([]byte("not a database"))
+		assert.Error(t, err)
+	})
+
+	t.Run("MMDBPointerCycles", func(t *testing.T) {
+		writer := newMMDBWriter()
+		// a pointer to itself, which is also a pointer to a pointer
+		writer.insert("10.0.0.0/24", mmdbRaw{1 << 5, 0})
+		// a map whose value points back to the map
+		writer.insert("10.0.1.0/24", mmdbRaw{7<<5 | 1, 2<<5 | 1, 'a', 1 << 5, 2})
+
+		db, err := gateway.LoadMMDB(writer.bytes(24))
+		assert.NoError(t, err)
+		_, ok := db.Locate(net.ParseIP("10.0.0.1"))
+		assert.False(t, ok)
+		_, ok = db.Locate(net.ParseIP("10.0.1.1"))
+		assert.False(t, ok)
+	})
+
+	t.Run("MMDBOversizedContainers", func(t *testing.T) {
+		writer := newMMDBWriter()
+		// a map and an array claiming about 16 million entries in a file of a few bytes
+		writer.insert("10.0.0.0/24", mmdbRaw{7<<5 | 31, 0xff, 0xff, 0xff})
+		writer.insert("10.0.1.0/24", mmdbRaw{31, 11 - 7, 0xff, 0xff, 0xff})
+
+		db, err := gateway.LoadMMDB(writer.bytes(24))
+		assert.NoError(t, err)
+		_, ok := db.Locate(net.ParseIP("10.0.0.1"))
+		assert.False(t, ok)
+		_, ok = db.Locate(net.ParseIP("10.0.1.1"))
+		assert.False(t, ok)
+	})
+}
+
+func TestLocalGateway(t *testing.T) {
+	table, _ := gateway.LoadCSV(strings.NewReader("81.2.69.0/24,GB,England,London\n"))
+	local := gateway.New(gateway.Options{Locator: table, Lists: map[string][]string{"vip": {"ada@example.com"}}})
+
+	t.Run("Lookup", func(t *testing.T) {
+		details := local.Lookup(iPhoneUA, "81.2.69.1")
+		assert.Equal(t, "GB", details.Location["country"])
+		assert.Equal(t, "mobile", details.UserAgent["device_type"])
+		assert.Equal(t, gateway.Details{}, local.Lookup("", "not-an-ip"))
+		assert.True(t, local.CheckAttribute("vip", "ada@example.com"))
+		assert.False(t, local.CheckAttribute("vip", "bob@example.com"))
+	})
+
+	t.Run("EnrichWithoutGateway", func(t *testing.T) {
+		settings := segmentedSettings(t, map[string]interface{}{
+			"and": []interface{}{map[string]interface{}{"country": "GB"}},
+		})
+		vwoClient, err := vwo.Init(map[string]interface{}{
+			enums.OptionSDKKey.GetValue():    "abcd",
+			enums.OptionAccountID.GetValue(): 12345,
+			enums.OptionSettings.GetValue():  settings,
+		})
+		assert.NoError(t, err)
+
+		context := map[string]interface{}{"id": "gateway-user-1", "ipAddress": "81.2.69.1"}
+		flag, _ := vwoClient.GetFlag("feature1", context)
+		assert.False(t, flag.IsEnabled())
+
+		enriched := local.Enrich(context)
+		assert.NotContains(t, context, gateway.ContextKey)
+		flag, _ = vwoClient.GetFlag("feature1", enriched)
+		assert.True(t, flag.IsEnabled())
+
+		flag, _ = vwoClient.GetFlag("feature1", local.Enrich(map[string]interface{}{"id": "gateway-user-2", "ipAddress": "10.0.0.1"}))
+		assert.False(t, flag.IsEnabled())
+	})
+
+	t.Run("ServesGatewayProtocol", func(t *testing.T) {
+		settings := segmentedSettings(t, map[string]interface{}{
+			"and": []interface{}{
+				map[string]interface{}{"or": []interface{}{map[string]interface{}{"device_type": "mobile"}}},
+				map[string]interface{}{"custom_variable": map[string]interface{}{"email": "inlist(vip)"}},
+			},
+		})
+		server := httptest.NewServer(gateway.New(gateway.Options{Lists: map[string][]string{"vip": {"ada@example.com"}}, Settings: settings}))
+		defer server.Close()
+
+		vwoClient, err := vwo.Init(map[string]interface{}{
+			enums.OptionSDKKey.GetValue():         "abcd",
+			enums.OptionAccountID.GetValue():      12345,
+			enums.OptionGatewayService.GetValue(): map[string]interface{}{"url": server.URL},
+		})
+		assert.NoError(t, err)
+
+		flag, _ := vwoClient.GetFlag("feature1", map[string]interface{}{
+			"id":              "gateway-user-3",
+			"userAgent":       iPhoneUA,
+			"customVariables": map[string]interface{}{"email": "ada@example.com"},
+		})
+		assert.True(t, flag.IsEnabled())
+
+		flag, _ = vwoClient.GetFlag("feature1", map[string]interface{}{
+			"id":              "gateway-user-4",
+			"userAgent":       windowsUA,
+			"customVariables": map[string]interface{}{"email": "ada@example.com"},
+		})
+		assert.False(t, flag.IsEnabled())
+
+		flag, _ = vwoClient.GetFlag("feature1", map[string]interface{}{
+			"id":              "gateway-user-5",
+			"userAgent":       iPhoneUA,
+			"customVariables": map[string]interface{}{"email": "bob@example.com"},
+		})
+		assert.False(t, flag.IsEnabled())
+	})
+}
@@ -0,0 +1,107 @@
+/**
+ * Copyright 2025 Wingify Software Pvt. Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package unit
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wingify/vwo-fme-go-sdk/pkg/segmentation"
+	loggerCore "github.com/wingify/wingify-fme-go-sdk/pkg/packages/logger/core"
+	segmentationCore "github.com/wingify/wingify-fme-go-sdk/pkg/packages/segmentation_evaluator/core"
+)
+
+// coercionCase is the result of an operand on a value under each coercion
+type coercionCase struct {
+	operand                                      string
+	value                                        interface{}
+	sdk, strict, noTrim, numbersAsText, booleans bool
+}
+
+// coercionCases is shared across operand types; the sdk column is the zero Coercion
+var coercionCases = []coercionCase{
+	{"5", "5.0", true, false, true, false, true},
+	{"5", 5.0, true, true, true, true, true},
+	{"5", "5", true, true, true, true, true},
+	{"5", " 5 ", true, true, false, true, true},
+	{"1.0.0", "1.0", true, false, true, false, true},
+	{"true", true, true, true, true, true, true},
+	{"true", "TRUE", false, false, false, false, true},
+	{"True", true, false, false, false, false, true},
+	{"lower(ABC)", " abc ", true, true, false, true, true},
+	{"lower(5)", 5, true, false, true, true, true},
+	{"wildcard(5*)", 55.0, true, false, true, true, true},
+	{"wildcard(*0)", "5.0", false, true, false, true, false},
+	{"regex(^[0-9]+$)", "42", true, true, true, true, true},
+	{"regex(^[0-9]+$)", 42, true, false, true, true, true},
+	{"gt(5)", "10", true, false, true, true, true},
+	{"gt(5)", 10, true, true, true, true, true},
+	{"gte(1.2)", "1.10", false, false, false, true, false},
+	{"semver_gt(2.0.0)", "2.1.0", true, true, true, true, true},
+	{"semver_gt(2.0.0)", 3, true, false, true, true, true},
+	{"in([42, acme])", 42, true, false, true, true, true},
+	{"in([42, acme])", " acme ", true, true, false, true, true},
+	{"in([5])", "5.0", true, false, true, false, true},
+	{"in([5.0, acme])", 5, true, false, true, false, true},
+	{"not_in([5])", "5.0", false, true, false, true, false},
+	{"in([yes, true])", "TRUE", false, false, false, false, true},
+	{"before(2026-01-01)", "1700000000", true, false, true, true, true},
+	{"before(2026-01-01)", 1700000000, true, true, true, true, true},
+}
+
+// extendedOperand matches the operands evaluated by the Evaluator rather than the SDK
+var extendedOperand = regexp.MustCompile(`^(semver_|in\(|before\()`)
+
+func TestOperandCoercion(t *testing.T) {
+	segmentationManager := segmentationCore.NewSegmentationManagerWithEvaluator(loggerCore.NewLogManager(nil), true)
+	evaluators := map[string]*segmentation.Evaluator{
+		"sdk":           segmentation.New(segmentation.Options{}),
+		"strict":        segmentation.New(segmentation.Options{Coercion: segmentation.Coercion{Strict: true}}),
+		"noTrim":        segmentation.New(segmentation.Options{Coercion: segmentation.Coercion{NoTrim: true}}),
+		"numbersAsText": segmentation.New(segmentation.Options{Coercion: segmentation.Coercion{NumbersAsText: true}}),
+		"booleans":      segmentation.New(segmentation.Options{Coercion: segmentation.Coercion{ParseBooleans: true}}),
+	}
+
+	for _, c := range coercionCases {
+		c := c
+		t.Run(fmt.Sprintf("%s/%v", c.operand, c.value), func(t *testing.T) {
+			condition, err := json.Marshal(map[string]interface{}{"or": []interface{}{
+				map[string]interface{}{"custom_variable": map[string]interface{}{"v": c.operand}},
+			}})
+			assert.NoError(t, err)
+			dsl := string(condition)
+			customVariables := map[string]interface{}{"v": c.value}
+
+			expected := map[string]bool{
+				"sdk":           c.sdk,
+				"strict":        c.strict,
+				"noTrim":        c.noTrim,
+				"numbersAsText": c.numbersAsText,
+				"booleans":      c.booleans,
+			}
+			for name, evaluator := range evaluators {
+				assert.Equal(t, expected[name], evaluator.Validate(dsl, customVariables), name)
+			}
+			if !extendedOperand.MatchString(c.operand) {
+				assert.Equal(t, c.sdk, safeValidate(segmentationManager, dsl, customVariables), "SDK")
+			}
+		})
+	}
+}
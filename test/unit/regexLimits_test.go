@@ -0,0 +1,120 @@
+/**
+ * Copyright 2025 Wingify Software Pvt. Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package unit
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wingify/vwo-fme-go-sdk"
+	"github.com/wingify/vwo-fme-go-sdk/pkg/segmentation"
+	"github.com/wingify/wingify-fme-go-sdk/pkg/enums"
+)
+
+// regexSegment returns a segment matching a custom variable against a regex operand
+func regexSegment(operand string) map[string]interface{} {
+	return map[string]interface{}{"or": []interface{}{
+		map[string]interface{}{"custom_variable": map[string]interface{}{"reg": operand}},
+	}}
+}
+
+func TestRegexLimits(t *testing.T) {
+	t.Run("PatternLength", func(t *testing.T) {
+		dsl := `{"or":[{"custom_variable":{"reg":"regex(` + strings.Repeat("a", 20) + `)"}}]}`
+		verifySegment(t, segmentation.New(segmentation.Options{}), dsl, map[string]interface{}{"reg": strings.Repeat("a", 20), "expectation": true})
+		limited := segmentation.New(segmentation.Options{Regex: segmentation.RegexLimits{MaxPatternLength: 10}})
+		verifySegment(t, limited, dsl, map[string]interface{}{"reg": strings.Repeat("a", 20), "expectation": false})
+	})
+
+	t.Run("InputLength", func(t *testing.T) {
+		dsl := `{"or":[{"custom_variable":{"reg":"regex(^a+$)"}}]}`
+		long := strings.Repeat("a", segmentation.DefaultMaxInputLength+1)
+		verifySegment(t, segmentation.New(segmentation.Options{}), dsl, map[string]interface{}{"reg": long, "expectation": false})
+		verifySegment(t, segmentation.New(segmentation.Options{}), dsl, map[string]interface{}{"reg": long[1:], "expectation": true})
+
+		unlimited := segmentation.New(segmentation.Options{Regex: segmentation.RegexLimits{MaxInputLength: -1}})
+		verifySegment(t, unlimited, dsl, map[string]interface{}{"reg": long, "expectation": true})
+
+		// wildcards without stars are regular expressions too
+		limited := segmentation.New(segmentation.Options{Regex: segmentation.RegexLimits{MaxInputLength: 3}})
+		verifySegment(t, limited, `{"or":[{"custom_variable":{"reg":"wildcard(a.c)"}}]}`, map[string]interface{}{"reg": "abc", "expectation": true})
+		verifySegment(t, limited, `{"or":[{"custom_variable":{"reg":"wildcard(a.c)"}}]}`, map[string]interface{}{"reg": "abcd", "expectation": false})
+		verifySegment(t, limited, `{"or":[{"custom_variable":{"reg":"wildcard(*b*)"}}]}`, map[string]interface{}{"reg": "abcd", "expectation": true})
+	})
+
+	t.Run("Budget", func(t *testing.T) {
+		dsl := `{"or":[{"custom_variable":{"first":"regex(b$)"}},{"custom_variable":{"second":"regex(^a)"}}]}`
+		customVariables := map[string]interface{}{"first": strings.Repeat("a", 8000), "second": "a", "expectation": true}
+		verifySegment(t, segmentation.New(segmentation.Options{Regex: segmentation.RegexLimits{Budget: time.Second}}), dsl, customVariables)
+
+		customVariables["expectation"] = false
+		exhausted := segmentation.New(segmentation.Options{Regex: segmentation.RegexLimits{Budget: time.Nanosecond}})
+		verifySegment(t, exhausted, dsl, customVariables)
+
+		// every evaluation has its own budget
+		verifySegment(t, exhausted, `{"or":[{"custom_variable":{"second":"regex(^a)"}}]}`, map[string]interface{}{"second": "a", "expectation": true})
+	})
+
+	t.Run("ValidateSettings", func(t *testing.T) {
+		evaluator := segmentation.New(segmentation.Options{Regex: segmentation.RegexLimits{MaxPatternLength: 10}})
+		assert.NoError(t, evaluator.ValidateSettings(segmentedSettings(t, regexSegment("regex(^a+$)"))))
+		assert.NoError(t, evaluator.ValidateSettings(segmentedSettings(t, regexSegment("wildcard(*[*)"))))
+
+		segments := map[string]interface{}{"and": []interface{}{
+			map[string]interface{}{"custom_variable": map[string]interface{}{"plan": "eq_value"}},
+			map[string]interface{}{"or": []interface{}{
+				map[string]interface{}{"custom_variable": map[string]interface{}{"reg": "regex(*)"}},
+				map[string]interface{}{"custom_variable": map[string]interface{}{"name": "wildcard(a[c)"}},
+				map[string]interface{}{"custom_variable": map[string]interface{}{"long": "regex(" + strings.Repeat("a", 11) + ")"}},
+			}},
+		}}
+		err := evaluator.ValidateSettings(segmentedSettings(t, segments))
+		assert.True(t, errors.Is(err, segmentation.ErrInvalidRegex))
+		assert.Contains(t, err.Error(), "$.and[1].or[0].custom_variable.reg: regex(*): error parsing regexp: missing argument to repetition operator: `*`")
+		assert.Contains(t, err.Error(), "$.and[1].or[1].custom_variable.name: wildcard(a[c): error parsing regexp")
+		assert.Contains(t, err.Error(), "$.and[1].or[2].custom_variable.long: regex(aaaaaaaaaaa): pattern of 11 bytes exceeds 10")
+		assert.Equal(t, 3, strings.Count(err.Error(), "$."))
+
+		assert.Error(t, evaluator.ValidateSettings("not json"))
+	})
+
+	t.Run("GetFlag", func(t *testing.T) {
+		evaluator := segmentation.New(segmentation.Options{Regex: segmentation.RegexLimits{MaxInputLength: 8}})
+		prepared, err := evaluator.Prepare(segmentedSettings(t, regexSegment("regex(^vip)")))
+		assert.NoError(t, err)
+		assert.Len(t, prepared.Bindings(), 1)
+
+		vwoClient, err := vwo.Init(map[string]interface{}{
+			enums.OptionSDKKey.GetValue():    "abcd",
+			enums.OptionAccountID.GetValue(): 12345,
+			enums.OptionSettings.GetValue():  prepared.Settings(),
+		})
+		assert.NoError(t, err)
+
+		for user, expected := range map[string]bool{"vip-1": true, "vip-123456789": false, "guest": false} {
+			flag, err := vwoClient.GetFlag("feature1", prepared.Context(map[string]interface{}{
+				"id":              "regex-user-" + user,
+				"customVariables": map[string]interface{}{"reg": user},
+			}))
+			assert.NoError(t, err)
+			assert.Equal(t, expected, flag.IsEnabled(), user)
+		}
+	})
+}
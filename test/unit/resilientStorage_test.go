@@ -0,0 +1,240 @@
+/**
+ * Copyright 2025 Wingify Software Pvt. Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package unit
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wingify/vwo-fme-go-sdk/pkg/storage"
+	"github.com/wingify/wingify-fme-go-sdk/pkg/models"
+)
+
+// recordingLogger collects the messages of a ResilientConnector
+type recordingLogger struct {
+	mu       sync.Mutex
+	messages []string
+}
+
+func (l *recordingLogger) Printf(format string, v ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.messages = append(l.messages, fmt.Sprintf(format, v...))
+}
+
+// lookupFlagClient looks up the stored decision before evaluating, as the SDK does
+type lookupFlagClient struct {
+	storage.FlagClient
+	connector storage.Connector
+}
+
+func (c *lookupFlagClient) GetFlag(featureKey string, context map[string]interface{}) (models.GetFlagResponse, error) {
+	c.connector.Get(featureKey, context["id"].(string))
+	return c.FlagClient.GetFlag(featureKey, context)
+}
+
+func TestResilientStorage(t *testing.T) {
+	t.Run("PassesThroughWhenHealthy", func(t *testing.T) {
+		inner := newCountingConnector()
+		resilient := storage.Resilient(inner, storage.Policy{})
+
+		assert.NoError(t, resilient.Set(storedDecision("feature1", "user-1", 1)))
+		result, err := resilient.Get("feature1", "user-1")
+		assert.NoError(t, err)
+		assert.NotNil(t, result)
+		assert.Equal(t, storage.ResilienceStats{State: storage.BreakerClosed, Successes: 2}, resilient.Stats())
+	})
+
+	t.Run("FailOpenHidesErrors", func(t *testing.T) {
+		inner := newCountingConnector()
+		inner.failGet = errors.New("backend down")
+		inner.failSet = errors.New("backend down")
+		resilient := storage.Resilient(inner, storage.Policy{Mode: storage.FailOpen})
+
+		result, err := resilient.Get("feature1", "user-1")
+		assert.NoError(t, err)
+		assert.Nil(t, result)
+		assert.NoError(t, resilient.Set(storedDecision("feature1", "user-1", 1)))
+		assert.Equal(t, uint64(2), resilient.Stats().Failures)
+	})
+
+	t.Run("FailClosedReportsErrors", func(t *testing.T) {
+		inner := newCountingConnector()
+		inner.failGet = errors.New("backend down")
+		inner.failSet = errors.New("backend down")
+		resilient := storage.Resilient(inner, storage.Policy{Mode: storage.FailClosed})
+
+		_, err := resilient.Get("feature1", "user-1")
+		assert.EqualError(t, err, "backend down")
+		assert.Error(t, resilient.Set(storedDecision("feature1", "user-1", 1)))
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		inner := newCountingConnector()
+		inner.block = make(chan struct{})
+		defer close(inner.block)
+		resilient := storage.Resilient(inner, storage.Policy{Mode: storage.FailClosed, Timeout: 20 * time.Millisecond})
+
+		start := time.Now()
+		_, err := resilient.Get("feature1", "user-1")
+		assert.Equal(t, storage.ErrTimeout, err)
+		assert.Less(t, int64(time.Since(start)), int64(time.Second))
+		assert.Equal(t, uint64(1), resilient.Stats().Timeouts)
+	})
+
+	t.Run("BreakerOpensAndRecovers", func(t *testing.T) {
+		inner := newCountingConnector()
+		inner.failGet = errors.New("backend down")
+		logger := &recordingLogger{}
+		var transitions []string
+		resilient := storage.Resilient(inner, storage.Policy{
+			Mode:    storage.FailClosed,
+			Breaker: storage.BreakerOptions{FailureThreshold: 3, OpenDuration: 30 * time.Millisecond},
+			Logger:  logger,
+			OnStateChange: func(from storage.BreakerState, to storage.BreakerState) {
+				transitions = append(transitions, from.String()+"->"+to.String())
+			},
+		})
+
+		for i := 0; i < 3; i++ {
+			resilient.Get("feature1", "user-1")
+		}
+		assert.Equal(t, storage.BreakerOpen, resilient.State())
+
+		_, err := resilient.Get("feature1", "user-1")
+		assert.Equal(t, storage.ErrCircuitOpen, err)
+		assert.Equal(t, int64(3), inner.gets)
+
+		time.Sleep(40 * time.Millisecond)
+		resilient.Get("feature1", "user-1")
+		assert.Equal(t, storage.BreakerOpen, resilient.State())
+		assert.Equal(t, int64(4), inner.gets)
+
+		inner.failGet = nil
+		time.Sleep(40 * time.Millisecond)
+		_, err = resilient.Get("feature1", "user-1")
+		assert.NoError(t, err)
+		assert.Equal(t, storage.BreakerClosed, resilient.State())
+
+		assert.Equal(t, []string{"closed->open", "open->half-open", "half-open->open", "open->half-open", "half-open->closed"}, transitions)
+		stats := resilient.Stats()
+		assert.Equal(t, uint64(2), stats.Opens)
+		assert.Equal(t, uint64(1), stats.ShortCircuits)
+		assert.Contains(t, logger.messages, "storage circuit breaker closed -> open")
+		assert.Contains(t, logger.messages, "storage get failed: backend down")
+	})
+
+	t.Run("ListenerMayQueryConnector", func(t *testing.T) {
+		inner := newCountingConnector()
+		inner.failGet = errors.New("backend down")
+		var resilient *storage.ResilientConnector
+		var states []storage.BreakerState
+		resilient = storage.Resilient(inner, storage.Policy{
+			Mode:    storage.FailClosed,
+			Breaker: storage.BreakerOptions{FailureThreshold: 1},
+			OnStateChange: func(from storage.BreakerState, to storage.BreakerState) {
+				states = append(states, resilient.Stats().State, resilient.State())
+			},
+		})
+
+		done := make(chan struct{})
+		go func() {
+			resilient.Get("feature1", "user-1")
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("state change listener deadlocked")
+		}
+		assert.Equal(t, []storage.BreakerState{storage.BreakerOpen, storage.BreakerOpen}, states)
+	})
+
+	t.Run("SuccessResetsFailureCount", func(t *testing.T) {
+		inner := newCountingConnector()
+		resilient := storage.Resilient(inner, storage.Policy{Breaker: storage.BreakerOptions{FailureThreshold: 2}})
+
+		inner.failGet = errors.New("backend down")
+		resilient.Get("feature1", "user-1")
+		inner.failGet = nil
+		resilient.Get("feature1", "user-1")
+		inner.failGet = errors.New("backend down")
+		resilient.Get("feature1", "user-1")
+		assert.Equal(t, storage.BreakerClosed, resilient.State())
+	})
+}
+
+func TestResilientStorageGuard(t *testing.T) {
+	t.Run("FailClosedDisablesFlagsWhileOpen", func(t *testing.T) {
+		inner := newCountingConnector()
+		resilient := storage.Resilient(inner, storage.Policy{
+			Mode:    storage.FailClosed,
+			Breaker: storage.BreakerOptions{FailureThreshold: 1, OpenDuration: 30 * time.Millisecond},
+		})
+		client := newFakeFlagClient("feature1")
+		guarded := resilient.Guard(client)
+
+		flag, err := guarded.GetFlag("feature1", map[string]interface{}{"id": "user-1"})
+		assert.NoError(t, err)
+		assert.True(t, flag.IsEnabled())
+
+		inner.failGet = errors.New("backend down")
+		resilient.Get("feature1", "user-1")
+
+		flag, err = guarded.GetFlag("feature1", map[string]interface{}{"id": "user-1"})
+		assert.NoError(t, err)
+		assert.False(t, flag.IsEnabled())
+		assert.Equal(t, 1, len(client.calls))
+
+		time.Sleep(40 * time.Millisecond)
+		flag, _ = guarded.GetFlag("feature1", map[string]interface{}{"id": "user-1"})
+		assert.True(t, flag.IsEnabled())
+		assert.Equal(t, 2, len(client.calls))
+	})
+
+	t.Run("FailClosedDisablesFlagsOnFailedLookup", func(t *testing.T) {
+		inner := newCountingConnector()
+		resilient := storage.Resilient(inner, storage.Policy{Mode: storage.FailClosed})
+		client := &lookupFlagClient{FlagClient: newFakeFlagClient("feature1"), connector: resilient}
+		guarded := resilient.Guard(client)
+
+		inner.failGet = errors.New("backend down")
+		flag, err := guarded.GetFlag("feature1", map[string]interface{}{"id": "user-1"})
+		assert.NoError(t, err)
+		assert.False(t, flag.IsEnabled())
+		assert.Equal(t, storage.BreakerClosed, resilient.State())
+
+		inner.failGet = nil
+		flag, err = guarded.GetFlag("feature1", map[string]interface{}{"id": "user-1"})
+		assert.NoError(t, err)
+		assert.True(t, flag.IsEnabled())
+
+		inner.failGet = errors.New("backend down")
+		flag, _ = client.GetFlag("feature1", map[string]interface{}{"id": "user-1"})
+		assert.True(t, flag.IsEnabled())
+	})
+
+	t.Run("FailOpenReturnsClient", func(t *testing.T) {
+		client := newFakeFlagClient("feature1")
+		resilient := storage.Resilient(newCountingConnector(), storage.Policy{Mode: storage.FailOpen})
+		assert.Equal(t, storage.FlagClient(client), resilient.Guard(client))
+	})
+}
@@ -0,0 +1,181 @@
+/**
+ * Copyright 2025 Wingify Software Pvt. Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package unit
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wingify/vwo-fme-go-sdk/pkg/segmentation"
+	"github.com/wingify/vwo-fme-go-sdk/pkg/segmentation/segmentcmd"
+)
+
+// allOperandsTree is allOperandsDSL as printed by Format
+const allOperandsTree = `or
+  or
+    and
+      or
+        custom_variable start_with: wildcard(my_start_with_val*)
+      not
+        or
+          custom_variable neq: not_eq_value
+    or
+      custom_variable contain: wildcard(*my_contain_val*)
+  and
+    or
+      custom_variable eq: eq_value
+    or
+      custom_variable reg: regex(myregex+)
+      custom_variable age: gte(18)
+`
+
+func TestSegmentParser(t *testing.T) {
+	t.Run("ParsesTypedTree", func(t *testing.T) {
+		root, err := segmentation.Parse(allOperandsDSL)
+		assert.NoError(t, err)
+		or := root.(*segmentation.Or)
+		assert.Len(t, or.Conditions, 2)
+		and := or.Conditions[1].(*segmentation.And)
+		reg := and.Conditions[1].(*segmentation.Or).Conditions[0].(*segmentation.CustomVariable)
+		assert.Equal(t, "reg", reg.Name)
+		assert.Equal(t, segmentation.Operand{Name: "regex", Argument: "myregex+"}, reg.Operand)
+		eq := and.Conditions[0].(*segmentation.Or).Conditions[0].(*segmentation.CustomVariable)
+		assert.Equal(t, segmentation.Operand{Argument: "eq_value"}, eq.Operand)
+	})
+
+	t.Run("ParsesOtherConditions", func(t *testing.T) {
+		root, err := segmentation.Parse(`{"and":[{"user":"user-1, \"user-2\""},{"country":"IN"},{"custom_variable":{"age":18}}]}`)
+		assert.NoError(t, err)
+		assert.Equal(t, "and\n  user: user-1, user-2\n  country: IN\n  custom_variable age: 18\n", segmentation.Format(root))
+		assert.Equal(t, &segmentation.Other{Operator: "country", Value: "IN"}, root.(*segmentation.And).Conditions[1])
+	})
+
+	t.Run("FormatIsCanonical", func(t *testing.T) {
+		root, err := segmentation.Parse(allOperandsDSL)
+		assert.NoError(t, err)
+		assert.Equal(t, allOperandsTree, segmentation.Format(root))
+
+		encoded, err := json.Marshal(root.DSL())
+		assert.NoError(t, err)
+		reparsed, err := segmentation.Parse(string(encoded))
+		assert.NoError(t, err)
+		assert.Equal(t, allOperandsTree, segmentation.Format(reparsed))
+		assert.JSONEq(t, allOperandsDSL, string(encoded))
+	})
+
+	t.Run("ReportsMalformedDSL", func(t *testing.T) {
+		for dsl, message := range map[string]string{
+			`{"or":[`:                               "invalid DSL",
+			`{}`:                                    "$ has 0 operators",
+			`{"and":{"custom_variable":{"a":"b"}}}`: "$.and is not a list",
+			`{"or":[{"custom_variable":{"a":"b"}},"x"]}`: "$.or[1] is not an object",
+			`{"not":{"or":[{"a":"b","c":"d"}]}}`:         "$.not.or[0] has 2 operators",
+			`{"custom_variable":{"a":"b","c":"d"}}`:      "$.custom_variable has 2 variables",
+			`{"user":5}`:                                 "$.user is not a string",
+		} {
+			_, err := segmentation.Parse(dsl)
+			if assert.Error(t, err, dsl) {
+				assert.Contains(t, err.Error(), message, dsl)
+			}
+		}
+	})
+}
+
+func TestSegmentExplain(t *testing.T) {
+	evaluator := segmentation.New(segmentation.Options{})
+
+	t.Run("ExplainsEachNode", func(t *testing.T) {
+		customVariables := map[string]interface{}{"start_with": 1, "neq": 1, "contain": 1, "eq": "eq_value", "reg": "wrong"}
+		explanation, err := evaluator.Explain(allOperandsDSL, customVariables)
+		assert.NoError(t, err)
+		assert.Equal(t, evaluator.Validate(allOperandsDSL, customVariables), explanation.Result)
+		assert.False(t, explanation.Result)
+		assert.Equal(t, "or: 0 of 2 conditions matched", explanation.Reason)
+
+		reasons := explanation.String()
+		assert.Contains(t, reasons, "  false and: 1 of 2 conditions matched\n")
+		assert.Contains(t, reasons, "true  not: its condition did not match\n")
+		assert.Contains(t, reasons, "true  custom_variable eq: eq_value matched 'eq_value'\n")
+		assert.Contains(t, reasons, "false custom_variable reg: regex(myregex+) did not match 'wrong'\n")
+		assert.Contains(t, reasons, "false custom_variable age is not set\n")
+	})
+
+	t.Run("MatchesValidate", func(t *testing.T) {
+		for _, customVariables := range []map[string]interface{}{
+			{"start_with": "my_start_with_val1", "neq": "x"},
+			{"eq": "eq_value", "age": 18},
+			{"contain": "a my_contain_val b"},
+			{},
+		} {
+			explanation, err := evaluator.Explain(allOperandsDSL, customVariables)
+			assert.NoError(t, err)
+			assert.Equal(t, evaluator.Validate(allOperandsDSL, customVariables), explanation.Result, "%v", customVariables)
+		}
+	})
+
+	t.Run("ExplainsOperandErrors", func(t *testing.T) {
+		explanation, err := evaluator.Explain(`{"custom_variable":{"app_version":"semver_gt(2.0.0)"}}`, map[string]interface{}{"app_version": "latest"})
+		assert.NoError(t, err)
+		assert.Contains(t, explanation.Reason, "custom_variable app_version: semver_gt(2.0.0) failed on 'latest'")
+	})
+
+	t.Run("ExplainsUsersAndSDKConditions", func(t *testing.T) {
+		explanation, err := evaluator.Explain(`{"or":[{"user":"user-1,user-2"},{"country":"IN"}]}`, map[string]interface{}{"_wingifyUserId": "user-2"})
+		assert.NoError(t, err)
+		assert.True(t, explanation.Result)
+		assert.Equal(t, "user: user-1, user-2 matched 'user-2'", explanation.Children[0].Reason)
+		assert.Equal(t, "country: IN: evaluated by the SDK with the user context", explanation.Children[1].Reason)
+	})
+}
+
+func TestSegmentCommand(t *testing.T) {
+	run := func(stdin string, args ...string) (string, string, error) {
+		var stdout, stderr bytes.Buffer
+		err := segmentcmd.Run(args, strings.NewReader(stdin), &stdout, &stderr)
+		return stdout.String(), stderr.String(), err
+	}
+
+	stdout, _, err := run(allOperandsDSL, "format")
+	assert.NoError(t, err)
+	assert.Equal(t, allOperandsTree, stdout)
+
+	path := filepath.Join(t.TempDir(), "segment.json")
+	assert.NoError(t, os.WriteFile(path, []byte(allOperandsDSL+"\n"), 0600))
+	stdout, _, err = run("", "format", "-in", path, "-json")
+	assert.NoError(t, err)
+	assert.JSONEq(t, allOperandsDSL, stdout)
+
+	stdout, _, err = run("", "explain", "-dsl", `{"or":[{"custom_variable":{"reg":"regex(myregex+)"}}]}`, "-vars", `{"reg":"wrong"}`)
+	assert.NoError(t, err)
+	assert.Equal(t, "false or: 0 of 1 conditions matched\n  false custom_variable reg: regex(myregex+) did not match 'wrong'\n", stdout)
+
+	_, _, err = run("", "explain", "-dsl", `{"or":[]}`, "-vars", `[1]`)
+	assert.Error(t, err)
+	_, _, err = run(`{"or":`, "format")
+	assert.Error(t, err)
+
+	for _, args := range [][]string{{}, {"lint"}, {"explain", "-dsl", "{}"}, {"format", "-dsl", "{}", "-in", path}} {
+		_, stderr, err := run("", args...)
+		assert.Equal(t, segmentcmd.ErrUsage, err, "%v", args)
+		assert.NotEmpty(t, stderr)
+	}
+}
@@ -0,0 +1,146 @@
+/**
+ * Copyright 2025 Wingify Software Pvt. Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package unit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wingify/vwo-fme-go-sdk"
+	"github.com/wingify/vwo-fme-go-sdk/pkg/segmentation"
+	"github.com/wingify/wingify-fme-go-sdk/pkg/enums"
+)
+
+// verifySegment checks the result of a segment DSL against the expectation in customVariables
+func verifySegment(t *testing.T, evaluator *segmentation.Evaluator, dsl string, customVariables map[string]interface{}) {
+	expected := customVariables["expectation"].(bool)
+	assert.Equal(t, expected, evaluator.Validate(dsl, customVariables), dsl)
+}
+
+func TestSemverOperand(t *testing.T) {
+	evaluator := segmentation.New(segmentation.Options{})
+
+	for _, tc := range []struct {
+		name     string
+		operand  string
+		version  interface{}
+		expected bool
+	}{
+		{"GreaterThanComparesNumerically", "semver_gt(2.9.0)", "2.10.3", true},
+		{"GreaterThanEqualVersion", "semver_gt(2.10.3)", "2.10.3", false},
+		{"GreaterThanEqualToEqualVersion", "semver_gte(2.10.3)", "2.10.3", true},
+		{"LessThanComparesNumerically", "semver_lt(2.10.0)", "2.9.12", true},
+		{"LessThanEqualToLowerVersion", "semver_lte(2.10.0)", "2.10.1", false},
+		{"EqualIgnoresMissingPatch", "semver_eq(2.10)", "2.10.0", true},
+		{"EqualIgnoresPrefixAndBuild", "semver_eq(v1.4.0)", "1.4.0+build.9", true},
+		{"PreReleaseIsLowerThanRelease", "semver_lt(3.0.0)", "3.0.0-rc.1", true},
+		{"PreReleaseIsAboveEarlierRelease", "semver_gt(2.99.0)", "3.0.0-alpha", true},
+		{"PreReleaseIdentifiersCompareNumerically", "semver_gt(1.0.0-beta.2)", "1.0.0-beta.11", true},
+		{"NumericCustomVariable", "semver_gte(2.1)", 2.1, true},
+		{"IntegerCustomVariable", "semver_eq(3)", 3, true},
+		{"InvalidCustomVariable", "semver_gt(1.0.0)", "latest", false},
+		{"BooleanCustomVariable", "semver_gt(1.0.0)", true, false},
+		{"InvalidExpectedVersion", "semver_gt(one)", "2.0.0", false},
+		{"TooManyComponents", "semver_gt(1.0.0)", "1.2.3.4", false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			dsl := `{"or":[{"custom_variable":{"app_version":"` + tc.operand + `"}}]}`
+			verifySegment(t, evaluator, dsl, map[string]interface{}{
+				"app_version": tc.version,
+				"expectation": tc.expected,
+			})
+		})
+	}
+
+	t.Run("MissingCustomVariable", func(t *testing.T) {
+		dsl := `{"or":[{"custom_variable":{"app_version":"semver_gt(1.0.0)"}}]}`
+		verifySegment(t, evaluator, dsl, map[string]interface{}{"expectation": false})
+	})
+
+	t.Run("CombinedWithBuiltInOperands", func(t *testing.T) {
+		dsl := `{"and":[{"custom_variable":{"app_version":"semver_gte(2.10.0)"}},{"or":[{"custom_variable":{"plan":"wildcard(*pro*)"}},{"custom_variable":{"seats":"gt(10)"}}]}]}`
+		verifySegment(t, evaluator, dsl, map[string]interface{}{"app_version": "2.10.1", "plan": "enterprise-pro", "expectation": true})
+		verifySegment(t, evaluator, dsl, map[string]interface{}{"app_version": "2.10.1", "plan": "free", "seats": 50, "expectation": true})
+		verifySegment(t, evaluator, dsl, map[string]interface{}{"app_version": "2.10.1", "plan": "free", "seats": 5, "expectation": false})
+		verifySegment(t, evaluator, dsl, map[string]interface{}{"app_version": "2.9.9", "plan": "pro", "expectation": false})
+	})
+
+	t.Run("NotOperator", func(t *testing.T) {
+		dsl := `{"not":{"or":[{"custom_variable":{"app_version":"semver_lt(2.0.0)"}}]}}`
+		verifySegment(t, evaluator, dsl, map[string]interface{}{"app_version": "2.0.0", "expectation": true})
+		verifySegment(t, evaluator, dsl, map[string]interface{}{"app_version": "1.9.0", "expectation": false})
+	})
+
+	t.Run("BuiltInOperandsAreUnchanged", func(t *testing.T) {
+		dsl := `{"or":[{"custom_variable":{"eq":"something"}}]}`
+		verifySegment(t, evaluator, dsl, map[string]interface{}{"eq": "something", "expectation": true})
+		verifySegment(t, evaluator, dsl, map[string]interface{}{"eq": "other", "expectation": false})
+		verifySegment(t, evaluator, `{"or":[`, map[string]interface{}{"expectation": false})
+	})
+}
+
+func TestVersionPrecedence(t *testing.T) {
+	ordered := []string{"1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-alpha.beta", "1.0.0-beta", "1.0.0-beta.2", "1.0.0-beta.11", "1.0.0-rc.1", "1.0.0", "1.0.1", "1.10.0", "2.0.0"}
+	for i := 0; i+1 < len(ordered); i++ {
+		lower, err := segmentation.ParseVersion(ordered[i])
+		assert.NoError(t, err)
+		higher, err := segmentation.ParseVersion(ordered[i+1])
+		assert.NoError(t, err)
+		assert.Equal(t, -1, lower.Compare(higher), "%s < %s", ordered[i], ordered[i+1])
+		assert.Equal(t, 1, higher.Compare(lower), "%s > %s", ordered[i+1], ordered[i])
+	}
+
+	version, _ := segmentation.ParseVersion("v2.1-rc.1+build")
+	assert.Equal(t, "2.1.0-rc.1", version.String())
+	for _, invalid := range []string{"", "1..0", "1.0.0-", "1.0.0-beta..1", "a.b.c"} {
+		_, err := segmentation.ParseVersion(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestPreparedSettings(t *testing.T) {
+	settings := segmentedSettings(t, map[string]interface{}{
+		"or": []interface{}{map[string]interface{}{"custom_variable": map[string]interface{}{"app_version": "semver_gte(2.10.0)"}}},
+	})
+	prepared, err := segmentation.New(segmentation.Options{}).Prepare(settings)
+	assert.NoError(t, err)
+	assert.Len(t, prepared.Bindings(), 1)
+	assert.NotContains(t, prepared.Settings(), "semver_gte")
+
+	vwoClient, err := vwo.Init(map[string]interface{}{
+		enums.OptionSDKKey.GetValue():    "abcd",
+		enums.OptionAccountID.GetValue(): 12345,
+		enums.OptionSettings.GetValue():  prepared.Settings(),
+	})
+	assert.NoError(t, err)
+
+	for user, version := range map[string]interface{}{"semver-user-1": "2.10.3", "semver-user-2": "2.9.0", "semver-user-3": nil} {
+		context := map[string]interface{}{"id": user}
+		if version != nil {
+			context["customVariables"] = map[string]interface{}{"app_version": version}
+		}
+		flag, err := vwoClient.GetFlag("feature1", prepared.Context(context))
+		assert.NoError(t, err)
+		assert.Equal(t, version == "2.10.3", flag.IsEnabled(), user)
+	}
+
+	unchanged, err := segmentation.New(segmentation.Options{}).Prepare(`{"campaigns":[]}`)
+	assert.NoError(t, err)
+	assert.Empty(t, unchanged.Bindings())
+	_, err = segmentation.New(segmentation.Options{}).Prepare("not json")
+	assert.Error(t, err)
+}
@@ -0,0 +1,211 @@
+/**
+ * Copyright 2025 Wingify Software Pvt. Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package unit
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wingify/vwo-fme-go-sdk"
+	"github.com/wingify/vwo-fme-go-sdk/pkg/segmentation"
+	"github.com/wingify/wingify-fme-go-sdk/pkg/enums"
+	loggerCore "github.com/wingify/wingify-fme-go-sdk/pkg/packages/logger/core"
+	segmentationCore "github.com/wingify/wingify-fme-go-sdk/pkg/packages/segmentation_evaluator/core"
+)
+
+// failingLists is a ListProvider that cannot load its lists
+type failingLists struct{}
+
+func (failingLists) List(id string) (*segmentation.Set, error) {
+	return nil, errors.New("list store unavailable")
+}
+
+func TestSetOperand(t *testing.T) {
+	evaluator := segmentation.New(segmentation.Options{})
+
+	for _, tc := range []struct {
+		name     string
+		operand  string
+		account  interface{}
+		expected bool
+	}{
+		{"InBareList", "in([acme, globex, initech])", "globex", true},
+		{"InBareListMiss", "in([acme, globex, initech])", "Globex", false},
+		{"InJSONList", `in([\"acme\", \"a,b\", 42])`, "a,b", true},
+		{"InNumberMatchesByValue", `in([\"acme\", 42])`, 42.0, true},
+		{"InNumberMatchesString", "in([41, 42])", "42", true},
+		{"InNumberMatchesNumber", "in([41, 42])", 42, true},
+		{"InLargeIntegerIsExact", "in([9007199254740993])", int64(9007199254740992), false},
+		{"InLargeIntegerMatches", "in([9007199254740993])", int64(9007199254740993), true},
+		{"InExponentMatchesInteger", "in([1e3])", 1000, true},
+		{"InEmptyList", "in([])", "acme", false},
+		{"NotIn", "not_in([acme, globex])", "initech", true},
+		{"NotInMember", "not_in([acme, globex])", "acme", false},
+		{"BooleanCustomVariable", "not_in([acme])", true, false},
+		{"MissingBrackets", "in(acme, globex)", "acme", false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			dsl := `{"or":[{"custom_variable":{"account_id":"` + tc.operand + `"}}]}`
+			verifySegment(t, evaluator, dsl, map[string]interface{}{
+				"account_id":  tc.account,
+				"expectation": tc.expected,
+			})
+		})
+	}
+
+	t.Run("MissingCustomVariable", func(t *testing.T) {
+		verifySegment(t, evaluator, `{"or":[{"custom_variable":{"account_id":"not_in([acme])"}}]}`, map[string]interface{}{"expectation": false})
+	})
+
+	t.Run("CombinedWithBuiltInOperands", func(t *testing.T) {
+		dsl := `{"and":[{"custom_variable":{"account_id":"in([acme, globex])"}},{"not":{"custom_variable":{"plan":"free"}}}]}`
+		verifySegment(t, evaluator, dsl, map[string]interface{}{"account_id": "acme", "plan": "pro", "expectation": true})
+		verifySegment(t, evaluator, dsl, map[string]interface{}{"account_id": "acme", "plan": "free", "expectation": false})
+	})
+
+	t.Run("SetMembership", func(t *testing.T) {
+		set := segmentation.NewSet("acme", json.Number("1.50"), 7, true)
+		assert.Equal(t, 3, set.Len())
+		assert.True(t, set.Contains(1.5))
+		assert.True(t, set.Contains("7"))
+		assert.False(t, set.Contains(true))
+		assert.False(t, (*segmentation.Set)(nil).Contains("acme"))
+
+		large := segmentation.NewSet(json.Number("9007199254740993"), uint64(18446744073709551615))
+		assert.True(t, large.Contains(int64(9007199254740993)))
+		assert.False(t, large.Contains(int64(9007199254740992)))
+		assert.True(t, large.Contains("18446744073709551615"))
+	})
+}
+
+func TestListOperand(t *testing.T) {
+	dsl := `{"or":[{"custom_variable":{"account_id":"inlist(vip-accounts)"}}]}`
+
+	t.Run("ListProvider", func(t *testing.T) {
+		evaluator := segmentation.New(segmentation.Options{Lists: segmentation.Lists{
+			"vip-accounts": segmentation.NewSet("acme", 1001),
+		}})
+		verifySegment(t, evaluator, dsl, map[string]interface{}{"account_id": "acme", "expectation": true})
+		verifySegment(t, evaluator, dsl, map[string]interface{}{"account_id": "1001", "expectation": true})
+		verifySegment(t, evaluator, dsl, map[string]interface{}{"account_id": "globex", "expectation": false})
+		verifySegment(t, evaluator, `{"or":[{"custom_variable":{"account_id":"inlist(unknown)"}}]}`, map[string]interface{}{"account_id": "acme", "expectation": false})
+	})
+
+	t.Run("FailingProvider", func(t *testing.T) {
+		evaluator := segmentation.New(segmentation.Options{Lists: failingLists{}})
+		verifySegment(t, evaluator, dsl, map[string]interface{}{"account_id": "acme", "expectation": false})
+	})
+
+	t.Run("SettingsLists", func(t *testing.T) {
+		var segments map[string]interface{}
+		assert.NoError(t, json.Unmarshal([]byte(dsl), &segments))
+		var settings map[string]interface{}
+		assert.NoError(t, json.Unmarshal([]byte(segmentedSettings(t, segments)), &settings))
+		settings[segmentation.SettingsLists] = map[string]interface{}{"vip-accounts": []interface{}{"acme", "globex"}}
+		encoded, _ := json.Marshal(settings)
+
+		evaluator := segmentation.New(segmentation.Options{Lists: segmentation.Lists{
+			"vip-accounts": segmentation.NewSet("initech"),
+		}})
+		prepared, err := evaluator.Prepare(string(encoded))
+		assert.NoError(t, err)
+		assert.Len(t, prepared.Bindings(), 1)
+
+		vwoClient, err := vwo.Init(map[string]interface{}{
+			enums.OptionSDKKey.GetValue():    "abcd",
+			enums.OptionAccountID.GetValue(): 12345,
+			enums.OptionSettings.GetValue():  prepared.Settings(),
+		})
+		assert.NoError(t, err)
+
+		for user, expected := range map[string]bool{"acme": true, "globex": true, "initech": false} {
+			flag, err := vwoClient.GetFlag("feature1", prepared.Context(map[string]interface{}{
+				"id":              "list-user-" + user,
+				"customVariables": map[string]interface{}{"account_id": user},
+			}))
+			assert.NoError(t, err)
+			assert.Equal(t, expected, flag.IsEnabled(), user)
+		}
+	})
+
+	t.Run("WithoutProvider", func(t *testing.T) {
+		evaluator := segmentation.New(segmentation.Options{})
+		prepared, err := evaluator.Prepare(segmentedSettings(t, map[string]interface{}{
+			"or": []interface{}{map[string]interface{}{"custom_variable": map[string]interface{}{"account_id": "inlist(vip-accounts)"}}},
+		}))
+		assert.NoError(t, err)
+		assert.Empty(t, prepared.Bindings())
+	})
+}
+
+// accountIDs returns n account ids
+func accountIDs(n int) []string {
+	ids := make([]string, n)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("account-%d", i)
+	}
+	return ids
+}
+
+func BenchmarkSetOperand(b *testing.B) {
+	for _, size := range []int{10, 1000, 100000} {
+		b.Run(fmt.Sprintf("In%d", size), func(b *testing.B) {
+			settings := segmentedSettings(b, map[string]interface{}{"or": []interface{}{map[string]interface{}{"custom_variable": map[string]interface{}{
+				"account_id": "in([" + strings.Join(accountIDs(size), ",") + "])",
+			}}}})
+			prepared, err := segmentation.New(segmentation.Options{}).Prepare(settings)
+			if err != nil {
+				b.Fatal(err)
+			}
+			binding := prepared.Bindings()[0]
+			context := map[string]interface{}{
+				"id":              "user",
+				"customVariables": map[string]interface{}{"account_id": fmt.Sprintf("account-%d", size-1)},
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if prepared.Context(context)["customVariables"].(map[string]interface{})[binding] != "true" {
+					b.Fatal("account not found")
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkEqualityChain(b *testing.B) {
+	for _, size := range []int{10, 1000} {
+		b.Run(fmt.Sprintf("Or%d", size), func(b *testing.B) {
+			segmentationManager := segmentationCore.NewSegmentationManagerWithEvaluator(loggerCore.NewLogManager(nil), true)
+			var conditions []interface{}
+			for _, id := range accountIDs(size) {
+				conditions = append(conditions, map[string]interface{}{"custom_variable": map[string]interface{}{"account_id": id}})
+			}
+			dsl := map[string]interface{}{"or": conditions}
+			customVariables := map[string]interface{}{"account_id": fmt.Sprintf("account-%d", size-1)}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if !segmentationManager.ValidateSegmentation(dsl, customVariables) {
+					b.Fatal("account not found")
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,232 @@
+/**
+ * Copyright 2025 Wingify Software Pvt. Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package unit
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wingify/vwo-fme-go-sdk/pkg/storage"
+	"github.com/wingify/vwo-fme-go-sdk/pkg/storage/filestore"
+	"github.com/wingify/vwo-fme-go-sdk/pkg/storage/storagecmd"
+	"github.com/wingify/vwo-fme-go-sdk/test/data"
+)
+
+var (
+	_ storage.Scanner   = (*data.StorageTest)(nil)
+	_ storage.Scanner   = (*filestore.Store)(nil)
+	_ storage.Deleter   = (*filestore.Store)(nil)
+	_ storage.Compacter = (*filestore.Store)(nil)
+	_ storage.Scanner   = (*storage.CachedConnector)(nil)
+	_ storage.Deleter   = (*storage.ResilientConnector)(nil)
+	_ storage.Wrapper   = (*storage.VersionedConnector)(nil)
+	_ storage.Wrapper   = (*storage.BatchingConnector)(nil)
+)
+
+// seedDecisions stores decisions of two users
+func seedDecisions(t *testing.T, c storage.Connector) {
+	for _, decision := range []map[string]interface{}{
+		storedDecision("feature1", "user-1", 1),
+		storedDecision("feature2", "user-1", 2),
+		storedDecision("feature1", "user-2", 1),
+		storedDecision("feature1", "prefix:user-1", 1),
+	} {
+		assert.NoError(t, c.Set(decision))
+	}
+}
+
+func TestStorageAdmin(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("ListAndForgetUser", func(t *testing.T) {
+		inner := data.NewStorageTest()
+		seedDecisions(t, inner)
+		admin := storage.NewAdmin(inner)
+
+		decisions, err := admin.ListUser(ctx, "user-1")
+		assert.NoError(t, err)
+		assert.Len(t, decisions, 2)
+
+		deleted, err := admin.ForgetUser(ctx, "user-1")
+		assert.NoError(t, err)
+		assert.Equal(t, 2, deleted)
+
+		decisions, _ = admin.ListUser(ctx, "user-1")
+		assert.Empty(t, decisions)
+		decisions, _ = admin.ListUser(ctx, "user-2")
+		assert.Len(t, decisions, 1)
+
+		_, err = admin.ListUser(ctx, "")
+		assert.Error(t, err)
+	})
+
+	t.Run("ForgetThroughCache", func(t *testing.T) {
+		inner := newCountingConnector()
+		seedDecisions(t, inner.StorageTest)
+		cached := storage.Cached(inner, 10, time.Minute)
+		cached.Get("feature1", "user-1")
+
+		deleted, err := storage.NewAdmin(cached).ForgetUser(ctx, "user-1")
+		assert.NoError(t, err)
+		assert.Equal(t, 2, deleted)
+
+		value, _ := cached.Get("feature1", "user-1")
+		assert.Nil(t, value)
+	})
+
+	t.Run("UnsupportedConnector", func(t *testing.T) {
+		admin := storage.NewAdmin(plainConnector{data.NewStorageTest()})
+		_, err := admin.ListUser(ctx, "user-1")
+		assert.Equal(t, storage.ErrScanUnsupported, err)
+
+		inner := data.NewStorageTest()
+		seedDecisions(t, inner)
+		scanOnly := struct {
+			storage.Connector
+			storage.Scanner
+		}{inner, inner}
+		_, err = storage.NewAdmin(scanOnly).ForgetUser(ctx, "user-1")
+		assert.ErrorIs(t, err, storage.ErrDeleteUnsupported)
+	})
+
+	t.Run("ExportImportRoundTrip", func(t *testing.T) {
+		source := data.NewStorageTest()
+		seedDecisions(t, source)
+
+		var export bytes.Buffer
+		exported, err := storage.NewAdmin(source).Export(ctx, &export, "")
+		assert.NoError(t, err)
+		assert.Equal(t, 4, exported)
+		lines := strings.Split(strings.TrimSpace(export.String()), "\n")
+		assert.Len(t, lines, 5)
+		assert.Contains(t, lines[0], `"format":"vwo-fme-storage"`)
+
+		target := newBatchConnector()
+		imported, err := storage.NewAdmin(target).Import(ctx, &export)
+		assert.NoError(t, err)
+		assert.Equal(t, 4, imported)
+		assert.Equal(t, int64(1), target.setManys)
+
+		value, _ := target.StorageTest.Get("feature2", "user-1")
+		assert.Equal(t, 2, value.(map[string]interface{})["rolloutVariationId"])
+	})
+
+	t.Run("ExportUser", func(t *testing.T) {
+		source := data.NewStorageTest()
+		seedDecisions(t, source)
+
+		var export bytes.Buffer
+		exported, err := storage.NewAdmin(source).Export(ctx, &export, "user-2")
+		assert.NoError(t, err)
+		assert.Equal(t, 1, exported)
+		assert.Contains(t, export.String(), `"userId":"user-2"`)
+	})
+
+	t.Run("ImportRejectsInvalidInput", func(t *testing.T) {
+		admin := storage.NewAdmin(data.NewStorageTest())
+
+		_, err := admin.Import(ctx, strings.NewReader(""))
+		assert.Error(t, err)
+		_, err = admin.Import(ctx, strings.NewReader(`{"featureKey":"feature1","userId":"user-1"}`+"\n"))
+		assert.Error(t, err)
+		_, err = admin.Import(ctx, strings.NewReader(`{"format":"vwo-fme-storage","version":2}`+"\n"))
+		assert.Error(t, err)
+
+		imported, err := admin.Import(ctx, strings.NewReader(`{"format":"vwo-fme-storage","version":1}`+"\n"+`{"featureKey":"feature1"}`+"\n"))
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "line 2")
+		assert.Equal(t, 0, imported)
+	})
+}
+
+func TestFileStoreErase(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "vwo.db")
+	store := openFileStore(t, path, filestore.Options{})
+	seedDecisions(t, store)
+
+	deleted, err := storage.NewAdmin(storage.Cached(store, 10, 0)).ForgetUser(ctx, "user-1")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, deleted)
+	assert.Equal(t, 0, store.Garbage())
+
+	contents, _ := os.ReadFile(path)
+	assert.NotContains(t, string(contents), `"user-1"`)
+	assert.Contains(t, string(contents), `"prefix:user-1"`)
+	assert.NoError(t, store.Close())
+
+	t.Run("DeleteSurvivesReopen", func(t *testing.T) {
+		store := openFileStore(t, path, filestore.Options{})
+		defer store.Close()
+		assert.NoError(t, store.Delete("feature1", "user-2"))
+		assert.NoError(t, store.Close())
+
+		store = openFileStore(t, path, filestore.Options{})
+		value, _ := store.Get("feature1", "user-2")
+		assert.Nil(t, value)
+		assert.Equal(t, 1, store.Len())
+	})
+}
+
+func TestStorageCommand(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "vwo.db")
+	store := openFileStore(t, path, filestore.Options{})
+	seedDecisions(t, store)
+	assert.NoError(t, store.Close())
+
+	open := func(name string) (storage.Connector, func() error, error) {
+		s, err := filestore.Open(name, filestore.Options{})
+		if err != nil {
+			return nil, nil, err
+		}
+		return s, s.Close, nil
+	}
+	run := func(stdin string, args ...string) (string, string, error) {
+		var stdout, stderr bytes.Buffer
+		err := storagecmd.Run(ctx, args, strings.NewReader(stdin), &stdout, &stderr, open)
+		return stdout.String(), stderr.String(), err
+	}
+
+	stdout, _, err := run("", "list", "-store", path, "-user", "user-1")
+	assert.NoError(t, err)
+	assert.Len(t, strings.Split(strings.TrimSpace(stdout), "\n"), 2)
+
+	export, stderr, err := run("", "export", "-store", path)
+	assert.NoError(t, err)
+	assert.Contains(t, stderr, "exported 4 decisions")
+
+	_, stderr, err = run("", "forget", "-store", path, "-user", "user-1")
+	assert.NoError(t, err)
+	assert.Contains(t, stderr, "deleted 2 decisions")
+
+	target := filepath.Join(t.TempDir(), "copy.db")
+	_, stderr, err = run(export, "import", "-store", target)
+	assert.NoError(t, err)
+	assert.Contains(t, stderr, "imported 4 decisions")
+
+	_, _, err = run("", "forget", "-store", path)
+	assert.Equal(t, storagecmd.ErrUsage, err)
+	_, _, err = run("", "unknown")
+	assert.Equal(t, storagecmd.ErrUsage, err)
+}
@@ -0,0 +1,160 @@
+/**
+ * Copyright 2025 Wingify Software Pvt. Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wingify/vwo-fme-go-sdk"
+	"github.com/wingify/vwo-fme-go-sdk/pkg/segmentation"
+	"github.com/wingify/wingify-fme-go-sdk/pkg/enums"
+)
+
+// fixedNow is the time of the clock of the date and time operand tests
+var fixedNow = time.Date(2026, 3, 15, 12, 0, 0, 0, time.UTC)
+
+func fixedClock() segmentation.Clock {
+	return segmentation.ClockFunc(func() time.Time { return fixedNow })
+}
+
+func TestTimeOperand(t *testing.T) {
+	evaluator := segmentation.New(segmentation.Options{Clock: fixedClock()})
+
+	for _, tc := range []struct {
+		name     string
+		operand  string
+		signup   interface{}
+		expected bool
+	}{
+		{"BeforeRFC3339", "before(2026-01-01T00:00:00Z)", "2025-12-31T23:59:59Z", true},
+		{"BeforeIsStrict", "before(2026-01-01T00:00:00Z)", "2026-01-01T00:00:00Z", false},
+		{"BeforeWithOffset", "before(2026-01-01T00:00:00Z)", "2026-01-01T00:30:00+01:00", true},
+		{"AfterRFC3339", "after(2026-01-01T00:00:00Z)", "2026-01-01T00:00:01Z", true},
+		{"AfterDate", "after(2026-01-01)", "2025-06-01", false},
+		{"AfterUnixSeconds", "after(2026-01-01T00:00:00Z)", float64(1767225601), true},
+		{"AfterUnixMilliseconds", "after(2026-01-01T00:00:00Z)", int64(1767225600500), true},
+		{"UnixTimestampString", "before(1767225600)", "1767225599", true},
+		{"TimeValue", "after(2026-01-01T00:00:00Z)", fixedNow, true},
+		{"BetweenIncludesBounds", "between(2026-01-01T00:00:00Z,2026-02-01T00:00:00Z)", "2026-02-01T00:00:00Z", true},
+		{"BetweenInside", "between(2026-01-01, 2026-02-01)", "2026-01-15T08:00:00Z", true},
+		{"BetweenOutside", "between(2026-01-01T00:00:00Z,2026-02-01T00:00:00Z)", "2026-02-01T00:00:01Z", false},
+		{"BetweenNeedsTwoBounds", "between(2026-01-01T00:00:00Z)", "2026-01-01T00:00:00Z", false},
+		{"WithinDays", "within_days(30)", "2026-02-13T12:00:00Z", true},
+		{"WithinDaysExpired", "within_days(30)", "2026-02-13T11:59:59Z", false},
+		{"WithinDaysExcludesFuture", "within_days(30)", "2026-03-16T00:00:00Z", false},
+		{"WithinFractionalDays", "within_days(0.5)", "2026-03-15T01:00:00Z", true},
+		{"OlderThanDays", "older_than_days(30)", "2026-01-01T00:00:00Z", true},
+		{"NotOlderThanDays", "older_than_days(30)", "2026-03-01T00:00:00Z", false},
+		{"NegativeDays", "within_days(-1)", "2026-03-15T00:00:00Z", false},
+		{"WithinMaxDays", "within_days(106751)", "1800-01-01T00:00:00Z", true},
+		{"TooManyDays", "within_days(200000)", "2026-03-15T00:00:00Z", false},
+		{"TooManyDaysOlderThan", "older_than_days(1e300)", "1800-01-01T00:00:00Z", false},
+		{"NaNDays", "within_days(NaN)", "2026-03-15T00:00:00Z", false},
+		{"InvalidTime", "before(2026-01-01T00:00:00Z)", "yesterday", false},
+		{"InvalidExpectedTime", "before(next year)", "2025-01-01T00:00:00Z", false},
+		{"BooleanCustomVariable", "after(2026-01-01T00:00:00Z)", true, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			dsl := `{"or":[{"custom_variable":{"signup_date":"` + tc.operand + `"}}]}`
+			verifySegment(t, evaluator, dsl, map[string]interface{}{
+				"signup_date": tc.signup,
+				"expectation": tc.expected,
+			})
+		})
+	}
+
+	t.Run("MissingCustomVariable", func(t *testing.T) {
+		verifySegment(t, evaluator, `{"or":[{"custom_variable":{"signup_date":"within_days(30)"}}]}`, map[string]interface{}{"expectation": false})
+	})
+
+	t.Run("CurrentTime", func(t *testing.T) {
+		dsl := `{"and":[{"custom_variable":{"current_time":"between(2026-03-01T00:00:00Z,2026-03-31T23:59:59Z)"}},{"custom_variable":{"plan":"pro"}}]}`
+		verifySegment(t, evaluator, dsl, map[string]interface{}{"plan": "pro", "expectation": true})
+		verifySegment(t, evaluator, dsl, map[string]interface{}{"plan": "free", "expectation": false})
+
+		later := segmentation.New(segmentation.Options{Clock: segmentation.ClockFunc(func() time.Time { return fixedNow.AddDate(0, 1, 0) })})
+		verifySegment(t, later, dsl, map[string]interface{}{"plan": "pro", "expectation": false})
+
+		// the clock wins over a value passed by the caller
+		verifySegment(t, later, dsl, map[string]interface{}{"plan": "pro", "current_time": "2026-03-15T00:00:00Z", "expectation": false})
+	})
+
+	t.Run("CurrentTimeWithBuiltInOperands", func(t *testing.T) {
+		dsl := `{"or":[{"custom_variable":{"current_time":"gte(1773576000)"}}]}`
+		verifySegment(t, evaluator, dsl, map[string]interface{}{"expectation": true})
+		dsl = `{"or":[{"custom_variable":{"current_time":"gt(1773576000)"}}]}`
+		verifySegment(t, evaluator, dsl, map[string]interface{}{"expectation": false})
+	})
+}
+
+func TestParseTime(t *testing.T) {
+	for input, expected := range map[string]time.Time{
+		"2026-01-01T00:00:00Z":      time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		"2026-01-01T05:30:00+05:30": time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		"2026-01-01":                time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		"1767225600":                time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		"1767225600000":             time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		" 1767225600.5 ":            time.Date(2026, 1, 1, 0, 0, 0, 500000000, time.UTC),
+	} {
+		parsed, err := segmentation.ParseTime(input)
+		assert.NoError(t, err, input)
+		assert.True(t, expected.Equal(parsed), "%s: %s", input, parsed)
+	}
+	for _, invalid := range []string{"", "soon", "2026-13-01", "NaN", "Inf"} {
+		_, err := segmentation.ParseTime(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestPreparedTimeSettings(t *testing.T) {
+	settings := segmentedSettings(t, map[string]interface{}{
+		"and": []interface{}{
+			map[string]interface{}{"custom_variable": map[string]interface{}{"signup_date": "within_days(30)"}},
+			map[string]interface{}{"custom_variable": map[string]interface{}{"current_time": "before(2026-04-01T00:00:00Z)"}},
+		},
+	})
+	clock := fixedNow
+	prepared, err := segmentation.New(segmentation.Options{Clock: segmentation.ClockFunc(func() time.Time { return clock })}).Prepare(settings)
+	assert.NoError(t, err)
+	assert.Len(t, prepared.Bindings(), 2)
+
+	vwoClient, err := vwo.Init(map[string]interface{}{
+		enums.OptionSDKKey.GetValue():    "abcd",
+		enums.OptionAccountID.GetValue(): 12345,
+		enums.OptionSettings.GetValue():  prepared.Settings(),
+	})
+	assert.NoError(t, err)
+
+	getFlag := func(user string, signup string) bool {
+		flag, err := vwoClient.GetFlag("feature1", prepared.Context(map[string]interface{}{
+			"id":              user,
+			"customVariables": map[string]interface{}{"signup_date": signup},
+		}))
+		assert.NoError(t, err)
+		return flag.IsEnabled()
+	}
+	assert.True(t, getFlag("time-user-1", "2026-03-01T00:00:00Z"))
+	assert.False(t, getFlag("time-user-2", "2025-12-01T00:00:00Z"))
+
+	clock = time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+	assert.False(t, getFlag("time-user-3", "2026-03-30T00:00:00Z"))
+
+	context := prepared.Context(map[string]interface{}{"id": "time-user-4"})
+	assert.Equal(t, clock.Unix(), context["customVariables"].(map[string]interface{})[segmentation.CurrentTime])
+}
@@ -0,0 +1,185 @@
+/**
+ * Copyright 2025 Wingify Software Pvt. Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package unit
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wingify/vwo-fme-go-sdk/pkg/transport"
+)
+
+// scriptedTransport answers with the given status codes in turn, failing with a network error on 0
+func scriptedTransport(header http.Header, statuses ...int) *fakeTransport {
+	f := &fakeTransport{}
+	f.respond = func(req *http.Request) (*http.Response, error) {
+		f.mu.Lock()
+		n := len(f.requests)
+		f.mu.Unlock()
+		status := statuses[len(statuses)-1]
+		if n <= len(statuses) {
+			status = statuses[n-1]
+		}
+		if status == 0 {
+			return nil, errors.New("connection reset")
+		}
+		resp := jsonResponse(req, status, "{}")
+		for key, values := range header {
+			resp.Header[key] = values
+		}
+		return resp, nil
+	}
+	return f
+}
+
+const settingsURL = "https://dev.visualwebsiteoptimizer.com/server-side/v2-settings"
+
+func TestTransportRetry(t *testing.T) {
+	fast := transport.RetryPolicy{MaxRetries: 3, InitialDelay: time.Millisecond}
+
+	t.Run("RetriesRetryableFailures", func(t *testing.T) {
+		sdk := scriptedTransport(nil, 503, 0, 429, 200)
+		router := transport.New(transport.Config{Transport: sdk, Retry: fast}, nil)
+		resp, err := router.RoundTrip(newRequest(t, settingsURL))
+		assert.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+		assert.Len(t, sdk.requests, 4)
+	})
+
+	t.Run("ReturnsOtherStatusCodes", func(t *testing.T) {
+		sdk := scriptedTransport(nil, 401)
+		router := transport.New(transport.Config{Transport: sdk, Retry: fast}, nil)
+		resp, err := router.RoundTrip(newRequest(t, settingsURL))
+		assert.NoError(t, err)
+		assert.Equal(t, 401, resp.StatusCode)
+		assert.Len(t, sdk.requests, 1)
+
+		sdk = scriptedTransport(nil, 500, 200)
+		router = transport.New(transport.Config{Transport: sdk, Retry: transport.RetryPolicy{MaxRetries: 1, InitialDelay: time.Millisecond, RetryableStatus: []int{500}}}, nil)
+		resp, _ = router.RoundTrip(newRequest(t, settingsURL))
+		assert.Equal(t, 200, resp.StatusCode)
+	})
+
+	t.Run("GivesUpAfterMaxRetries", func(t *testing.T) {
+		sdk := scriptedTransport(nil, 0)
+		router := transport.New(transport.Config{Transport: sdk, Retry: fast}, nil)
+		_, err := router.RoundTrip(newRequest(t, settingsURL))
+		assert.EqualError(t, err, "connection reset")
+		assert.Len(t, sdk.requests, 4)
+	})
+
+	t.Run("ResendsTheBody", func(t *testing.T) {
+		var bodies []string
+		sdk := scriptedTransport(nil, 503, 200)
+		respond := sdk.respond
+		sdk.respond = func(req *http.Request) (*http.Response, error) {
+			body, _ := io.ReadAll(req.Body)
+			bodies = append(bodies, string(body))
+			return respond(req)
+		}
+		router := transport.New(transport.Config{Transport: sdk, Retry: fast}, nil)
+		req, _ := http.NewRequest(http.MethodPost, "https://dev.visualwebsiteoptimizer.com/events/t", strings.NewReader(`{"d":1}`))
+		_, err := router.RoundTrip(req)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{`{"d":1}`, `{"d":1}`}, bodies)
+	})
+
+	t.Run("HonoursRetryAfter", func(t *testing.T) {
+		sdk := scriptedTransport(http.Header{"Retry-After": []string{"5"}}, 429, 200)
+		router := transport.New(transport.Config{Transport: sdk, Retry: transport.RetryPolicy{MaxRetries: 3, MaxDelay: time.Second}}, nil)
+		resp, _ := router.RoundTrip(newRequest(t, settingsURL))
+		assert.Equal(t, 429, resp.StatusCode)
+		assert.Len(t, sdk.requests, 1)
+
+		past := time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat)
+		sdk = scriptedTransport(http.Header{"Retry-After": []string{past}}, 503, 200)
+		router = transport.New(transport.Config{Transport: sdk, Retry: fast}, nil)
+		resp, _ = router.RoundTrip(newRequest(t, settingsURL))
+		assert.Equal(t, 200, resp.StatusCode)
+
+		sdk = scriptedTransport(http.Header{"Retry-After": []string{"1"}}, 503, 200)
+		router = transport.New(transport.Config{Transport: sdk, Retry: fast}, nil)
+		start := time.Now()
+		resp, _ = router.RoundTrip(newRequest(t, settingsURL))
+		assert.Equal(t, 200, resp.StatusCode)
+		assert.GreaterOrEqual(t, int64(time.Since(start)), int64(time.Second))
+	})
+
+	t.Run("CapsDelays", func(t *testing.T) {
+		for _, jitter := range []transport.Jitter{transport.NoJitter, transport.FullJitter, transport.DecorrelatedJitter} {
+			sdk := scriptedTransport(nil, 503)
+			router := transport.New(transport.Config{Transport: sdk, Retry: transport.RetryPolicy{
+				MaxRetries:   3,
+				InitialDelay: 10 * time.Millisecond,
+				MaxDelay:     20 * time.Millisecond,
+				Multiplier:   100,
+				Jitter:       jitter,
+			}}, nil)
+			start := time.Now()
+			router.RoundTrip(newRequest(t, settingsURL))
+			assert.Len(t, sdk.requests, 4)
+			assert.Less(t, int64(time.Since(start)), int64(500*time.Millisecond))
+		}
+	})
+
+	t.Run("SpendsTheBudget", func(t *testing.T) {
+		budget := transport.NewRetryBudget(4, 1)
+		policy := transport.RetryPolicy{MaxRetries: 10, InitialDelay: time.Millisecond, Budget: budget}
+
+		sdk := scriptedTransport(nil, 503)
+		router := transport.New(transport.Config{Transport: sdk, Retry: policy}, nil)
+		router.RoundTrip(newRequest(t, settingsURL))
+		assert.Len(t, sdk.requests, 2)
+		router.RoundTrip(newRequest(t, settingsURL))
+		assert.Len(t, sdk.requests, 3)
+		assert.Equal(t, float64(1), budget.Tokens())
+
+		healthy := transport.New(transport.Config{Transport: scriptedTransport(nil, 200), Retry: policy}, nil)
+		for i := 0; i < 3; i++ {
+			healthy.RoundTrip(newRequest(t, settingsURL))
+		}
+		assert.Equal(t, float64(4), budget.Tokens())
+	})
+
+	t.Run("PerEndpointPolicies", func(t *testing.T) {
+		sdk := scriptedTransport(nil, 503)
+		router := transport.New(transport.Config{
+			Transport: sdk,
+			Retry:     fast,
+			Retries:   map[transport.Endpoint]transport.RetryPolicy{transport.EndpointEvents: {}},
+		}, nil)
+		router.RoundTrip(newRequest(t, "https://dev.visualwebsiteoptimizer.com/events/t"))
+		assert.Len(t, sdk.requests, 1)
+	})
+
+	t.Run("StopsWhenCancelled", func(t *testing.T) {
+		sdk := scriptedTransport(nil, 503)
+		router := transport.New(transport.Config{Transport: sdk, Retry: transport.RetryPolicy{MaxRetries: 3, InitialDelay: time.Minute}}, nil)
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, settingsURL, nil)
+		_, err := router.RoundTrip(req)
+		assert.True(t, errors.Is(err, context.DeadlineExceeded))
+		assert.Len(t, sdk.requests, 1)
+	})
+}
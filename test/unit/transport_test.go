@@ -0,0 +1,247 @@
+/**
+ * Copyright 2025 Wingify Software Pvt. Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package unit
+
+import (
+	"context"
+	"encoding/pem"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wingify/vwo-fme-go-sdk"
+	"github.com/wingify/vwo-fme-go-sdk/pkg/transport"
+	"github.com/wingify/vwo-fme-go-sdk/test/data"
+	"github.com/wingify/wingify-fme-go-sdk/pkg/enums"
+)
+
+// fakeTransport answers requests in memory and records them
+type fakeTransport struct {
+	mu       sync.Mutex
+	requests []*http.Request
+	respond  func(req *http.Request) (*http.Response, error)
+}
+
+func (f *fakeTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	f.mu.Lock()
+	f.requests = append(f.requests, req)
+	f.mu.Unlock()
+	if f.respond != nil {
+		return f.respond(req)
+	}
+	return jsonResponse(req, 200, "{}"), nil
+}
+
+func (f *fakeTransport) paths() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	paths := make([]string, len(f.requests))
+	for i, req := range f.requests {
+		paths[i] = req.URL.Path
+	}
+	return paths
+}
+
+func jsonResponse(req *http.Request, status int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: status,
+		Status:     http.StatusText(status),
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}
+}
+
+func newRequest(t *testing.T, url string) *http.Request {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	assert.NoError(t, err)
+	return req
+}
+
+func TestTransportRouter(t *testing.T) {
+	t.Run("RoutesSDKHosts", func(t *testing.T) {
+		sdk, fallback := &fakeTransport{}, &fakeTransport{}
+		router := transport.New(transport.Config{Transport: sdk, Hosts: []string{"gateway.internal", ".proxy.example"}}, fallback)
+
+		for _, url := range []string{
+			"https://dev.visualwebsiteoptimizer.com/server-side/v2-settings",
+			"http://gateway.internal:8000/get-user-details",
+			"https://eu.proxy.example/events/t",
+			"https://example.com/",
+			"https://proxy.example.evil.com/",
+		} {
+			resp, err := router.RoundTrip(newRequest(t, url))
+			assert.NoError(t, err)
+			resp.Body.Close()
+		}
+		assert.Equal(t, []string{"/server-side/v2-settings", "/get-user-details", "/events/t"}, sdk.paths())
+		assert.Len(t, fallback.requests, 2)
+	})
+
+	t.Run("SignsACopy", func(t *testing.T) {
+		sdk := &fakeTransport{}
+		router := transport.New(transport.Config{
+			Transport: sdk,
+			Sign: func(req *http.Request) error {
+				req.Header.Set("X-Signature", "signed:"+req.URL.Path)
+				return nil
+			},
+		}, &fakeTransport{})
+
+		req := newRequest(t, "https://dev.visualwebsiteoptimizer.com/events/t")
+		_, err := router.RoundTrip(req)
+		assert.NoError(t, err)
+		assert.Equal(t, "signed:/events/t", sdk.requests[0].Header.Get("X-Signature"))
+		assert.Empty(t, req.Header.Get("X-Signature"))
+
+		failing := transport.New(transport.Config{Transport: sdk, Sign: func(*http.Request) error { return errors.New("no key") }}, nil)
+		_, err = failing.RoundTrip(newRequest(t, "https://dev.visualwebsiteoptimizer.com/events/t"))
+		assert.EqualError(t, err, "no key")
+	})
+
+	t.Run("PerEndpointTimeouts", func(t *testing.T) {
+		sdk := &fakeTransport{respond: func(req *http.Request) (*http.Response, error) {
+			select {
+			case <-req.Context().Done():
+				return nil, req.Context().Err()
+			case <-time.After(time.Second):
+				return jsonResponse(req, 200, "{}"), nil
+			}
+		}}
+		router := transport.New(transport.Config{
+			Transport: sdk,
+			Client:    &http.Client{Timeout: 2 * time.Second},
+			Timeouts:  map[transport.Endpoint]time.Duration{transport.EndpointEvents: 20 * time.Millisecond},
+		}, nil)
+
+		start := time.Now()
+		_, err := router.RoundTrip(newRequest(t, "https://dev.visualwebsiteoptimizer.com/events/t"))
+		assert.True(t, errors.Is(err, context.DeadlineExceeded))
+		assert.Less(t, int64(time.Since(start)), int64(500*time.Millisecond))
+
+		resp, err := router.RoundTrip(newRequest(t, "https://dev.visualwebsiteoptimizer.com/server-side/v2-settings"))
+		assert.NoError(t, err)
+		resp.Body.Close()
+	})
+
+	t.Run("Classify", func(t *testing.T) {
+		for url, endpoint := range map[string]transport.Endpoint{
+			"https://h/server-side/v2-settings":     transport.EndpointSettings,
+			"https://h/prefix/server-side/v2-pull":  transport.EndpointSettings,
+			"https://h/server-side/batch-events-v2": transport.EndpointBatchEvents,
+			"https://h/events/t":                    transport.EndpointEvents,
+			"https://h/gateway/v1/get-user-details": transport.EndpointGateway,
+			"https://h/check-attribute":             transport.EndpointGateway,
+			"https://h/something-else":              transport.EndpointOther,
+		} {
+			assert.Equal(t, endpoint, transport.Classify(newRequest(t, url)), url)
+		}
+	})
+}
+
+func TestTransportTLS(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	assert.NoError(t, os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0600))
+
+	untrusted := &http.Client{Transport: transport.New(transport.Config{Hosts: []string{"127.0.0.1"}}, nil)}
+	_, err := untrusted.Get(server.URL)
+	assert.Error(t, err)
+
+	tlsConfig, err := transport.LoadTLS(transport.TLSFiles{CAFile: caFile})
+	assert.NoError(t, err)
+	trusted := &http.Client{Transport: transport.New(transport.Config{
+		TLS:   tlsConfig,
+		Pool:  transport.Pool{MaxConnsPerHost: 4},
+		Hosts: []string{"127.0.0.1"},
+	}, nil)}
+	resp, err := trusted.Get(server.URL)
+	assert.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.Equal(t, "ok", string(body))
+
+	_, err = transport.LoadTLS(transport.TLSFiles{CAFile: filepath.Join(t.TempDir(), "missing.pem")})
+	assert.Error(t, err)
+	_, err = transport.LoadTLS(transport.TLSFiles{CertFile: caFile, KeyFile: caFile})
+	assert.Error(t, err)
+}
+
+func TestTransportInstall(t *testing.T) {
+	settings := data.NewDummySettingsReader().SettingsMap["BASIC_ROLLOUT_SETTINGS"]
+	sdk := &fakeTransport{respond: func(req *http.Request) (*http.Response, error) {
+		if transport.Classify(req) == transport.EndpointSettings {
+			return jsonResponse(req, 200, settings), nil
+		}
+		return jsonResponse(req, 200, "{}"), nil
+	}}
+
+	restore, err := transport.Install(transport.Config{Transport: sdk})
+	assert.NoError(t, err)
+	defer restore()
+
+	_, err = transport.Install(transport.Config{Transport: sdk})
+	assert.Equal(t, transport.ErrInstalled, err)
+
+	vwoClient, err := vwo.Init(map[string]interface{}{
+		enums.OptionSDKKey.GetValue():    "abcd",
+		enums.OptionAccountID.GetValue(): 12345,
+	})
+	assert.NoError(t, err)
+
+	flag, err := vwoClient.GetFlag("feature1", map[string]interface{}{"id": "user-1"})
+	assert.NoError(t, err)
+	assert.True(t, flag.IsEnabled())
+	assert.Contains(t, sdk.paths(), "/server-side/v2-settings")
+
+	switched := http.DefaultTransport
+	_, isTransport := switched.(*http.Transport)
+	assert.False(t, isTransport)
+
+	restore()
+	_, isTransport = http.DefaultTransport.(*http.Transport)
+	assert.True(t, isTransport)
+
+	// a cancelled request fails in the original transport without reaching the network,
+	// also when sent through the switch picked up before restore
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	calls := len(sdk.paths())
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "https://dev.visualwebsiteoptimizer.com/server-side/v2-settings", nil)
+	_, err = switched.RoundTrip(req)
+	assert.Error(t, err)
+	assert.Equal(t, calls, len(sdk.paths()))
+
+	again, err := transport.Install(transport.Config{Transport: sdk})
+	assert.NoError(t, err)
+	assert.Equal(t, switched, http.DefaultTransport)
+	again()
+	_, isTransport = http.DefaultTransport.(*http.Transport)
+	assert.True(t, isTransport)
+}
@@ -0,0 +1,97 @@
+/**
+ * Copyright 2025 Wingify Software Pvt. Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package unit
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wingify/vwo-fme-go-sdk/pkg/segmentation"
+)
+
+func TestUnknownPolicy(t *testing.T) {
+	notEq := `{"not":{"or":[{"custom_variable":{"eq":"something"}}]}}`
+	andEq := `{"and":[{"custom_variable":{"eq":"something"}},{"custom_variable":{"plan":"pro"}}]}`
+	orEq := `{"or":[{"custom_variable":{"eq":"something"}},{"custom_variable":{"plan":"pro"}}]}`
+
+	for _, tc := range []struct {
+		name     string
+		policy   segmentation.UnknownPolicy
+		dsl      string
+		vars     map[string]interface{}
+		expected segmentation.Truth
+	}{
+		{"IsFalseNotOverMissing", segmentation.UnknownIsFalse, notEq, map[string]interface{}{}, segmentation.True},
+		{"IsFalseNotOverMismatch", segmentation.UnknownIsFalse, notEq, map[string]interface{}{"eq": "other"}, segmentation.True},
+		{"PropagatesNotOverMissing", segmentation.UnknownPropagates, notEq, map[string]interface{}{}, segmentation.Unknown},
+		{"PropagatesNotOverMismatch", segmentation.UnknownPropagates, notEq, map[string]interface{}{"eq": "other"}, segmentation.True},
+		{"PropagatesNotOverMatch", segmentation.UnknownPropagates, notEq, map[string]interface{}{"eq": "something"}, segmentation.False},
+		{"PropagatesAndWithFalse", segmentation.UnknownPropagates, andEq, map[string]interface{}{"plan": "free"}, segmentation.False},
+		{"PropagatesAndWithTrue", segmentation.UnknownPropagates, andEq, map[string]interface{}{"plan": "pro"}, segmentation.Unknown},
+		{"PropagatesOrWithTrue", segmentation.UnknownPropagates, orEq, map[string]interface{}{"plan": "pro"}, segmentation.True},
+		{"PropagatesOrWithFalse", segmentation.UnknownPropagates, orEq, map[string]interface{}{"plan": "free"}, segmentation.Unknown},
+		{"IsTrueAnd", segmentation.UnknownIsTrue, andEq, map[string]interface{}{"plan": "pro"}, segmentation.True},
+		{"IsTrueNotOverMissing", segmentation.UnknownIsTrue, notEq, map[string]interface{}{}, segmentation.False},
+		{"IsTrueExtendedOperand", segmentation.UnknownIsTrue, `{"custom_variable":{"app_version":"semver_gt(1.0.0)"}}`, map[string]interface{}{}, segmentation.True},
+		{"PropagatesUser", segmentation.UnknownPropagates, `{"or":[{"user":"user-1"}]}`, map[string]interface{}{}, segmentation.Unknown},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			evaluator := segmentation.New(segmentation.Options{Unknown: tc.policy})
+			truth, err := evaluator.Evaluate(tc.dsl, tc.vars)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, truth)
+			assert.Equal(t, tc.expected == segmentation.True, evaluator.Validate(tc.dsl, tc.vars))
+		})
+	}
+
+	t.Run("ExplainsUnknownConditions", func(t *testing.T) {
+		evaluator := segmentation.New(segmentation.Options{Unknown: segmentation.UnknownPropagates})
+		explanation, err := evaluator.Explain(notEq, map[string]interface{}{})
+		assert.NoError(t, err)
+		assert.Equal(t, segmentation.Unknown, explanation.Truth)
+		assert.False(t, explanation.Result)
+		assert.Equal(t, "unknown not: its condition is unknown\n  unknown or: 0 of 1 conditions matched\n    unknown custom_variable eq is not set\n", explanation.String())
+	})
+}
+
+func TestStrictMode(t *testing.T) {
+	dsl := `{"or":[{"custom_variable":{"eq":"something"}},{"and":[{"custom_variable":{"app_version":"semver_gte(2.0.0)"}},{"custom_variable":{"current_time":"after(2020-01-01)"}},{"country":"IN"}]}]}`
+	strict := segmentation.New(segmentation.Options{Strict: true})
+
+	segment, err := strict.Compile(dsl)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"app_version", "eq"}, segment.Variables())
+
+	truth, err := strict.Evaluate(dsl, map[string]interface{}{"eq": "something"})
+	assert.True(t, errors.Is(err, segmentation.ErrMissingVariables))
+	assert.EqualError(t, err, "segmentation: missing custom variables: app_version")
+	assert.Equal(t, segmentation.Unknown, truth)
+	assert.False(t, strict.Validate(dsl, map[string]interface{}{"eq": "something"}))
+
+	truth, err = strict.Evaluate(dsl, map[string]interface{}{"eq": "something", "app_version": "1.0.0"})
+	assert.NoError(t, err)
+	assert.Equal(t, segmentation.True, truth)
+	assert.Equal(t, []string{"app_version", "eq"}, segment.Missing(map[string]interface{}{}))
+
+	lenient := segmentation.New(segmentation.Options{})
+	assert.True(t, lenient.Validate(dsl, map[string]interface{}{"eq": "something"}))
+	_, err = lenient.Evaluate(dsl, map[string]interface{}{})
+	assert.NoError(t, err)
+	_, err = lenient.Evaluate(`{"or":[`, nil)
+	assert.Error(t, err)
+}
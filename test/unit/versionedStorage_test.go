@@ -0,0 +1,275 @@
+/**
+ * Copyright 2025 Wingify Software Pvt. Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package unit
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wingify/vwo-fme-go-sdk"
+	"github.com/wingify/vwo-fme-go-sdk/pkg/storage"
+	"github.com/wingify/vwo-fme-go-sdk/test/data"
+	"github.com/wingify/wingify-fme-go-sdk/pkg/enums"
+)
+
+// plainConnector hides the optional interfaces of the connector it wraps
+type plainConnector struct {
+	storage.Connector
+}
+
+var (
+	_ storage.BatchConnector = (*storage.VersionedConnector)(nil)
+	_ storage.Deleter        = (*data.StorageTest)(nil)
+)
+
+// rolloutDecision is a decision for the rollout rule of BASIC_ROLLOUT_SETTINGS
+func rolloutDecision(userID string) map[string]interface{} {
+	return map[string]interface{}{
+		"featureKey":         "feature1",
+		"userId":             userID,
+		"featureId":          1,
+		"rolloutKey":         "feature1_rolloutRule1",
+		"rolloutId":          1,
+		"rolloutVariationId": 1,
+	}
+}
+
+// mutateSettings returns a copy of the settings JSON changed by fn
+func mutateSettings(t testing.TB, raw string, fn func(settings map[string]interface{})) storage.StaticSettings {
+	var settings map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(raw), &settings))
+	fn(settings)
+	changed, err := json.Marshal(settings)
+	assert.NoError(t, err)
+	return storage.StaticSettings(changed)
+}
+
+// firstCampaign returns the first campaign of decoded settings
+func firstCampaign(settings map[string]interface{}) map[string]interface{} {
+	return settings["campaigns"].([]interface{})[0].(map[string]interface{})
+}
+
+func TestVersionedStorage(t *testing.T) {
+	raw := data.NewDummySettingsReader().SettingsMap["BASIC_ROLLOUT_SETTINGS"]
+	settings := storage.StaticSettings(raw)
+
+	t.Run("StampsDecisions", func(t *testing.T) {
+		inner := newCountingConnector()
+		versioned := storage.Versioned(inner, storage.VersionOptions{Settings: settings})
+
+		assert.NoError(t, versioned.Set(rolloutDecision("user-1")))
+		stored, _ := inner.StorageTest.Get("feature1", "user-1")
+		record := stored.(map[string]interface{})
+		assert.Equal(t, storage.RecordSchemaVersion, record[storage.FieldSchemaVersion])
+		assert.Len(t, record[storage.FieldFingerprint], 16)
+
+		result, err := versioned.Get("feature1", "user-1")
+		assert.NoError(t, err)
+		assert.NotNil(t, result)
+		assert.Equal(t, uint64(1), versioned.Report().Checked)
+		assert.Empty(t, versioned.Report().Records)
+	})
+
+	t.Run("PassesThroughWithoutSettings", func(t *testing.T) {
+		inner := newCountingConnector()
+		inner.StorageTest.Set(storedDecision("feature1", "user-1", 9))
+		versioned := storage.Versioned(inner, storage.VersionOptions{Policy: storage.DropStale})
+
+		result, err := versioned.Get("feature1", "user-1")
+		assert.NoError(t, err)
+		assert.NotNil(t, result)
+		assert.Equal(t, uint64(0), versioned.Report().Checked)
+	})
+
+	t.Run("ReevaluatesRemovedVariation", func(t *testing.T) {
+		inner := newCountingConnector()
+		decision := rolloutDecision("user-1")
+		decision["rolloutVariationId"] = 9
+		inner.StorageTest.Set(decision)
+
+		var notified []storage.InvalidatedRecord
+		versioned := storage.Versioned(inner, storage.VersionOptions{
+			Settings: settings,
+			Policy:   storage.ReevaluateStale,
+			OnInvalidated: func(record storage.InvalidatedRecord) {
+				notified = append(notified, record)
+			},
+		})
+
+		result, err := versioned.Get("feature1", "user-1")
+		assert.NoError(t, err)
+		assert.Nil(t, result)
+
+		stored, _ := inner.StorageTest.Get("feature1", "user-1")
+		assert.NotNil(t, stored)
+
+		report := versioned.Report()
+		assert.Equal(t, uint64(1), report.Legacy)
+		assert.Equal(t, uint64(1), report.Invalidated[storage.ReasonVariationRemoved])
+		assert.Len(t, report.Records, 1)
+		assert.Equal(t, "feature1_rolloutRule1", report.Records[0].CampaignKey)
+		assert.Equal(t, 9, report.Records[0].VariationID)
+		assert.Equal(t, storage.ReevaluateStale, report.Records[0].Action)
+		assert.Equal(t, report.Records, notified)
+	})
+
+	t.Run("DropsRemovedCampaign", func(t *testing.T) {
+		inner := newCountingConnector()
+		inner.StorageTest.Set(rolloutDecision("user-1"))
+		removed := mutateSettings(t, raw, func(settings map[string]interface{}) {
+			firstCampaign(settings)["key"] = "feature1_rolloutRule2"
+		})
+		versioned := storage.Versioned(inner, storage.VersionOptions{Settings: removed, Policy: storage.DropStale})
+
+		result, err := versioned.Get("feature1", "user-1")
+		assert.NoError(t, err)
+		assert.Nil(t, result)
+
+		stored, _ := inner.StorageTest.Get("feature1", "user-1")
+		assert.Nil(t, stored)
+		report := versioned.Report()
+		assert.Equal(t, uint64(1), report.Invalidated[storage.ReasonCampaignRemoved])
+		assert.Equal(t, uint64(1), report.Dropped)
+	})
+
+	t.Run("DropWithoutDeleterHidesDecision", func(t *testing.T) {
+		inner := newCountingConnector()
+		inner.StorageTest.Set(rolloutDecision("user-1"))
+		paused := mutateSettings(t, raw, func(settings map[string]interface{}) {
+			firstCampaign(settings)["status"] = "PAUSED"
+		})
+		versioned := storage.Versioned(plainConnector{inner}, storage.VersionOptions{Settings: paused, Policy: storage.DropStale})
+
+		result, _ := versioned.Get("feature1", "user-1")
+		assert.Nil(t, result)
+		stored, _ := inner.StorageTest.Get("feature1", "user-1")
+		assert.NotNil(t, stored)
+		report := versioned.Report()
+		assert.Equal(t, uint64(1), report.Invalidated[storage.ReasonCampaignInactive])
+		assert.Equal(t, uint64(0), report.Dropped)
+	})
+
+	t.Run("KeepsRemovedFeatureButReportsIt", func(t *testing.T) {
+		inner := newCountingConnector()
+		decision := rolloutDecision("user-1")
+		decision["featureId"] = 42.0
+		inner.StorageTest.Set(decision)
+		versioned := storage.Versioned(inner, storage.VersionOptions{Settings: settings})
+
+		result, err := versioned.Get("feature1", "user-1")
+		assert.NoError(t, err)
+		assert.NotNil(t, result)
+		assert.Equal(t, uint64(1), versioned.Report().Invalidated[storage.ReasonFeatureRemoved])
+		assert.Equal(t, storage.KeepStale, versioned.Report().Records[0].Action)
+	})
+
+	t.Run("DetectsChangedCampaign", func(t *testing.T) {
+		inner := newCountingConnector()
+		versioned := storage.Versioned(inner, storage.VersionOptions{Settings: settings, ChangedPolicy: storage.ReevaluateStale})
+		assert.NoError(t, versioned.Set(rolloutDecision("user-1")))
+
+		reweighted := mutateSettings(t, raw, func(settings map[string]interface{}) {
+			variation := firstCampaign(settings)["variations"].([]interface{})[0].(map[string]interface{})
+			variation["weight"] = 50
+		})
+		versioned.UseSettings(reweighted)
+		result, _ := versioned.Get("feature1", "user-1")
+		assert.NotNil(t, result)
+
+		extended := mutateSettings(t, raw, func(settings map[string]interface{}) {
+			campaign := firstCampaign(settings)
+			campaign["variations"] = append(campaign["variations"].([]interface{}), map[string]interface{}{"id": 2, "name": "Rollout-rule-2"})
+		})
+		versioned.UseSettings(extended)
+		result, _ = versioned.Get("feature1", "user-1")
+		assert.Nil(t, result)
+		assert.Equal(t, uint64(1), versioned.Report().Invalidated[storage.ReasonCampaignChanged])
+	})
+
+	t.Run("ChecksExperimentFirst", func(t *testing.T) {
+		inner := newCountingConnector()
+		decision := rolloutDecision("user-1")
+		decision["experimentKey"] = "feature1_testingRule1"
+		decision["experimentId"] = 2
+		decision["experimentVariationId"] = 2
+		inner.StorageTest.Set(decision)
+		versioned := storage.Versioned(inner, storage.VersionOptions{Settings: settings, Policy: storage.ReevaluateStale})
+
+		result, _ := versioned.Get("feature1", "user-1")
+		assert.Nil(t, result)
+		assert.Equal(t, "feature1_testingRule1", versioned.Report().Records[0].CampaignKey)
+	})
+
+	t.Run("GetManyFiltersStaleDecisions", func(t *testing.T) {
+		inner := newBatchConnector()
+		inner.StorageTest.Set(rolloutDecision("user-1"))
+		stale := storedDecision("feature2", "user-1", 1)
+		inner.StorageTest.Set(stale)
+		versioned := storage.Versioned(inner, storage.VersionOptions{Settings: settings, Policy: storage.ReevaluateStale})
+
+		result, err := versioned.GetMany("user-1", []string{"feature1", "feature2"})
+		assert.NoError(t, err)
+		assert.Len(t, result, 1)
+		assert.Contains(t, result, "feature1")
+		assert.Equal(t, int64(1), inner.getManys)
+	})
+
+	t.Run("ReportKeepsMostRecent", func(t *testing.T) {
+		inner := newCountingConnector()
+		versioned := storage.Versioned(inner, storage.VersionOptions{Settings: settings, ReportSize: 2})
+		for _, userID := range []string{"user-1", "user-2", "user-3"} {
+			inner.StorageTest.Set(storedDecision("feature1", userID, 1))
+			versioned.Get("feature1", userID)
+		}
+
+		report := versioned.Report()
+		assert.Equal(t, uint64(3), report.Invalidated[storage.ReasonCampaignRemoved])
+		assert.Len(t, report.Records, 2)
+		assert.Equal(t, "user-2", report.Records[0].UserID)
+
+		versioned.ResetReport()
+		assert.Equal(t, uint64(0), versioned.Report().Checked)
+	})
+}
+
+func TestVersionedStorageWithClient(t *testing.T) {
+	raw := data.NewDummySettingsReader().SettingsMap["BASIC_ROLLOUT_SETTINGS"]
+
+	inner := newCountingConnector()
+	versioned := storage.Versioned(inner, storage.VersionOptions{Policy: storage.ReevaluateStale})
+	vwoClient, err := vwo.Init(map[string]interface{}{
+		enums.OptionSDKKey.GetValue():    "abcd",
+		enums.OptionAccountID.GetValue(): 12345,
+		enums.OptionSettings.GetValue():  raw,
+		enums.OptionStorage.GetValue():   versioned,
+	})
+	assert.NoError(t, err)
+	versioned.UseSettings(vwoClient)
+
+	context := map[string]interface{}{"id": "user-1"}
+	flag, err := vwoClient.GetFlag("feature1", context)
+	assert.NoError(t, err)
+	assert.True(t, flag.IsEnabled())
+
+	stored, _ := inner.StorageTest.Get("feature1", "user-1")
+	assert.Equal(t, storage.RecordSchemaVersion, stored.(map[string]interface{})[storage.FieldSchemaVersion])
+
+	flag, _ = vwoClient.GetFlag("feature1", context)
+	assert.True(t, flag.IsEnabled())
+	assert.Empty(t, versioned.Report().Records)
+}
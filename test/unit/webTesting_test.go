@@ -0,0 +1,232 @@
+/**
+ * Copyright 2025 Wingify Software Pvt. Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package unit
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wingify/vwo-fme-go-sdk"
+	"github.com/wingify/vwo-fme-go-sdk/pkg/webtesting"
+	"github.com/wingify/wingify-fme-go-sdk/pkg/enums"
+	"github.com/wingify/wingify-fme-go-sdk/pkg/models/campaign"
+	"github.com/wingify/wingify-fme-go-sdk/pkg/models/user"
+	"github.com/wingify/wingify-fme-go-sdk/pkg/packages/interfaces"
+	loggerCore "github.com/wingify/wingify-fme-go-sdk/pkg/packages/logger/core"
+	segmentationCore "github.com/wingify/wingify-fme-go-sdk/pkg/packages/segmentation_evaluator/core"
+	"github.com/wingify/wingify-fme-go-sdk/pkg/packages/segmentation_evaluator/utils"
+	"github.com/wingify/wingify-fme-go-sdk/pkg/services"
+)
+
+// webTestingSettings is the settings manager of webTestingServices
+type webTestingSettings struct {
+	interfaces.SettingsManagerInterface
+}
+
+func (webTestingSettings) GetIsGatewayServiceProvided() bool { return false }
+
+// webTestingServices provides what the SDK needs to evaluate campaignVariation conditions on their own
+type webTestingServices struct {
+	interfaces.ServiceContainerInterface
+	logger interfaces.LoggerServiceInterface
+}
+
+func (s webTestingServices) GetLoggerService() interfaces.LoggerServiceInterface { return s.logger }
+func (webTestingServices) GetSettingsManager() interfaces.SettingsManagerInterface {
+	return webTestingSettings{}
+}
+func (webTestingServices) GetDebuggerService() interfaces.DebuggerServiceInterface {
+	return services.NewDebuggerService()
+}
+
+func TestWebTestingOperands(t *testing.T) {
+	campaigns := webtesting.New().Assign(122, 2).Assign(130, 1)
+	assert.Equal(t, webtesting.Campaigns{"122": "2", "130": "1"}, campaigns)
+	assert.NoError(t, campaigns.Validate())
+
+	t.Run("Forms", func(t *testing.T) {
+		for operand, expected := range map[webtesting.Operand]string{
+			webtesting.InCampaign(122):        "122",
+			webtesting.InVariation(122, 2):    "122_2",
+			webtesting.NotInVariation(122, 1): "122_!1",
+			webtesting.NotInCampaign(122):     "!122",
+		} {
+			assert.Equal(t, expected, operand.String())
+			parsed, err := webtesting.ParseOperand(expected)
+			assert.NoError(t, err)
+			assert.Equal(t, operand, parsed)
+		}
+		assert.Equal(t, map[string]interface{}{"campaignVariation": "122_2"}, webtesting.InVariation(122, 2).DSL())
+
+		for _, operand := range []string{"", "bogus", "!122_2", "!!122", "122_", "_2", "-1", "122_2_3", "1.5"} {
+			_, err := webtesting.ParseOperand(operand)
+			assert.True(t, errors.Is(err, webtesting.ErrInvalidOperand), operand)
+		}
+	})
+
+	t.Run("MatchesTheSDK", func(t *testing.T) {
+		for _, operand := range []string{"122", "122_2", "122_1", "122_!1", "122_!2", "!122", "!99", "99", "99_1", "99_!1", " 130_1 ", "bogus", "!122_2"} {
+			for _, assigned := range []webtesting.Campaigns{campaigns, nil, webtesting.New()} {
+				expected, invalid := utils.EvaluateWebTestingCampaignVariation(operand, assigned)
+				if operand == " 130_1 " {
+					// the SDK trims operands before evaluating them
+					expected, invalid = utils.EvaluateWebTestingCampaignVariation("130_1", assigned)
+				}
+				matched, err := assigned.Match(operand)
+				assert.Equal(t, expected, matched, operand)
+				assert.Equal(t, invalid, err != nil, operand)
+			}
+		}
+	})
+}
+
+func TestWebTestingNormalize(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		for _, raw := range []interface{}{
+			map[string]interface{}{"129": 1, "14": "2"},
+			map[int]interface{}{129: 1.0, 14: int64(2)},
+			map[string]string{"129": "1", "14": "2"},
+			`{"129": 1, "14": "2"}`,
+			` {"129":"1","14":2.0} `,
+		} {
+			campaigns, err := webtesting.Normalize(raw)
+			assert.NoError(t, err, raw)
+			assert.Equal(t, webtesting.Campaigns{"129": "1", "14": "2"}, campaigns, raw)
+		}
+
+		campaigns, err := webtesting.Normalize(map[string]interface{}{"1": 1e6})
+		assert.NoError(t, err)
+		assert.Equal(t, webtesting.Campaigns{"1": "1000000"}, campaigns)
+
+		campaigns, err = webtesting.Normalize(`{}`)
+		assert.NoError(t, err)
+		assert.Empty(t, campaigns)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		for _, raw := range []interface{}{
+			map[string]interface{}{"": "1"},
+			map[string]interface{}{"1": ""},
+			map[string]interface{}{"campaign": "1"},
+			map[string]interface{}{"1": "some"},
+			map[string]interface{}{"-1": "1"},
+			map[string]interface{}{"1": -1},
+			map[string]interface{}{"1.5": "1"},
+			map[string]interface{}{"1": 1.5},
+			map[string]interface{}{"1": nil},
+			map[string]interface{}{"1": true},
+			map[string]interface{}{"1": []int{1}},
+			map[string]interface{}{"1": map[string]int{}},
+			map[string]string{"1": "x"},
+			webtesting.Campaigns{"x": "1"},
+			`{"1":0,"1":1}`,
+			`{"1":1} {"2":2}`,
+			`[1, 2]`,
+			`{"1":`,
+			"",
+			nil,
+			true,
+			42,
+		} {
+			_, err := webtesting.Normalize(raw)
+			assert.True(t, errors.Is(err, webtesting.ErrInvalidCampaigns), "%v", raw)
+		}
+	})
+}
+
+func TestWebTestingCookies(t *testing.T) {
+	campaigns, err := webtesting.ParseCookieHeader("_vis_opt_exp_122_combi=2; _ga=GA1.2.3; _vis_opt_exp_130_combi=1; _vis_opt_s=1%7C")
+	assert.NoError(t, err)
+	assert.Equal(t, webtesting.Campaigns{"122": "2", "130": "1"}, campaigns)
+
+	campaigns, err = webtesting.ParseCookieHeader("_vis_opt_exp_122_combi=2; _vis_opt_exp_abc_combi=1; _vis_opt_exp_140_combi=x; _vis_opt_exp__combi=1")
+	assert.True(t, errors.Is(err, webtesting.ErrInvalidCampaigns))
+	assert.EqualError(t, err, "webtesting: invalid campaigns: _vis_opt_exp_140_combi=x, _vis_opt_exp__combi=1, _vis_opt_exp_abc_combi=1")
+	assert.Equal(t, webtesting.Campaigns{"122": "2"}, campaigns)
+
+	campaigns, err = webtesting.FromCookies([]*http.Cookie{
+		{Name: "_vis_opt_exp_122_combi", Value: "2"},
+		{Name: "_vis_opt_exp_122_combi", Value: "2"},
+		{Name: "_vis_opt_exp_130_combi", Value: "1"},
+		{Name: "_vis_opt_exp_130_combi", Value: "2"},
+		{Name: "_vis_opt_exp_130_combi", Value: "1"},
+	})
+	assert.EqualError(t, err, "webtesting: invalid campaigns: campaign 130 has several variations")
+	assert.Equal(t, webtesting.Campaigns{"122": "2"}, campaigns)
+
+	req, _ := http.NewRequest(http.MethodGet, "https://example.com/", nil)
+	req.AddCookie(&http.Cookie{Name: "_vis_opt_exp_7_combi", Value: "3"})
+	campaigns, err = webtesting.FromRequest(req)
+	assert.NoError(t, err)
+	assert.Equal(t, webtesting.Campaigns{"7": "3"}, campaigns)
+
+	campaigns, err = webtesting.ParseCookieHeader("")
+	assert.NoError(t, err)
+	assert.Empty(t, campaigns)
+}
+
+func TestWebTestingSegmentation(t *testing.T) {
+	logManager := loggerCore.NewLogManager(nil)
+	segmentationManager := segmentationCore.NewSegmentationManagerWithEvaluator(logManager, true)
+	campaigns := webtesting.New().Assign(122, 2)
+
+	base := map[string]interface{}{
+		"id":                "web-user-1",
+		"platformVariables": map[string]interface{}{"source": "ssr"},
+	}
+	context := campaigns.Context(base)
+	assert.Equal(t, map[string]interface{}{"source": "ssr"}, base["platformVariables"])
+	assert.Equal(t, "ssr", context["platformVariables"].(map[string]interface{})["source"])
+
+	segmentationManager.SetContextualData(webTestingServices{logger: logManager}, &campaign.Feature{}, user.NewWingifyUserContext(context))
+	for operand, expected := range map[webtesting.Operand]bool{
+		webtesting.InCampaign(122):        true,
+		webtesting.InVariation(122, 2):    true,
+		webtesting.InVariation(122, 1):    false,
+		webtesting.NotInVariation(122, 1): true,
+		webtesting.NotInVariation(122, 2): false,
+		webtesting.NotInCampaign(122):     false,
+		webtesting.NotInCampaign(99):      true,
+	} {
+		dsl := map[string]interface{}{"or": []interface{}{operand.DSL()}}
+		assert.Equal(t, expected, segmentationManager.ValidateSegmentation(dsl, map[string]interface{}{}), operand.String())
+		assert.Equal(t, expected, operand.Match(campaigns), operand.String())
+	}
+
+	t.Run("GetFlag", func(t *testing.T) {
+		vwoClient, err := vwo.Init(map[string]interface{}{
+			enums.OptionSDKKey.GetValue():    "abcd",
+			enums.OptionAccountID.GetValue(): 12345,
+			enums.OptionSettings.GetValue(): segmentedSettings(t, map[string]interface{}{"or": []interface{}{
+				webtesting.InVariation(122, 2).DSL(),
+			}}),
+		})
+		assert.NoError(t, err)
+
+		for id, assigned := range map[string]webtesting.Campaigns{
+			"web-user-2": webtesting.New().Assign(122, 2),
+			"web-user-3": webtesting.New().Assign(122, 1),
+			"web-user-4": webtesting.New(),
+		} {
+			flag, err := vwoClient.GetFlag("feature1", assigned.Context(map[string]interface{}{"id": id}))
+			assert.NoError(t, err)
+			assert.Equal(t, id == "web-user-2", flag.IsEnabled(), id)
+		}
+	})
+}